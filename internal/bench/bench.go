@@ -0,0 +1,108 @@
+// Package bench compares the output of `go test -bench` against a stored baseline,
+// to detect performance regressions of the core operations.
+package bench
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Results maps the name of a benchmark to its mean time per operation, in ns/op.
+//
+// The -GOMAXPROCS suffix is removed from the names, so that results from different machines can be compared.
+type Results map[string]float64
+
+// Parse reads the output of `go test -bench`, and returns the mean ns/op of each benchmark,
+// which may be run several times with -count. Lines which are not benchmark results are ignored.
+func Parse(r io.Reader) (Results, error) {
+	sums := map[string]float64{}
+	counts := map[string]int{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// BenchmarkName-8  <iterations>  <value> ns/op  [<value> <unit>]...
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+		found := false
+		for i := 3; i < len(fields); i += 2 {
+			if fields[i] != "ns/op" {
+				continue
+			}
+			v, err := strconv.ParseFloat(fields[i-1], 64)
+			if err != nil {
+				return nil, fmt.Errorf("bench: invalid ns/op for %s: %w", fields[0], err)
+			}
+			name := trimProcs(fields[0])
+			sums[name] += v
+			counts[name]++
+			found = true
+			break
+		}
+		if !found {
+			return nil, fmt.Errorf("bench: no ns/op for %s", fields[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("bench: %w", err)
+	}
+	results := make(Results, len(sums))
+	for name, sum := range sums {
+		results[name] = sum / float64(counts[name])
+	}
+	return results, nil
+}
+
+// trimProcs removes the -GOMAXPROCS suffix which `go test` appends to benchmark names.
+func trimProcs(name string) string {
+	i := strings.LastIndexByte(name, '-')
+	if i < 0 {
+		return name
+	}
+	if _, err := strconv.Atoi(name[i+1:]); err != nil {
+		return name
+	}
+	return name[:i]
+}
+
+// Regression is a benchmark which is slower than its baseline by more than the allowed threshold.
+type Regression struct {
+	Name     string
+	Baseline float64
+	Current  float64
+}
+
+// Ratio returns Current / Baseline.
+func (r Regression) Ratio() float64 {
+	return r.Current / r.Baseline
+}
+
+func (r Regression) String() string {
+	return fmt.Sprintf("%s: %.0f ns/op -> %.0f ns/op (%+.1f%%)", r.Name, r.Baseline, r.Current, 100*(r.Ratio()-1))
+}
+
+// Compare returns the benchmarks of current whose time per operation exceeds that of baseline
+// by more than threshold, a fraction such as 0.2 for 20%, sorted by name.
+//
+// Benchmarks which are only present in one of the results are not compared.
+func Compare(baseline, current Results, threshold float64) []Regression {
+	var regressions []Regression
+	for name, cur := range current {
+		base, ok := baseline[name]
+		if !ok || base <= 0 {
+			continue
+		}
+		if cur > base*(1+threshold) {
+			regressions = append(regressions, Regression{Name: name, Baseline: base, Current: cur})
+		}
+	}
+	sort.Slice(regressions, func(i, j int) bool { return regressions[i].Name < regressions[j].Name })
+	return regressions
+}
@@ -0,0 +1,61 @@
+package bench
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baselineOutput = `goos: linux
+goarch: amd64
+pkg: github.com/taurusgroup/multi-party-sig/pkg/paillier
+BenchmarkEncryption-8   	     100	  10000000 ns/op
+BenchmarkEncryption-8   	     100	  12000000 ns/op
+BenchmarkDecryption-8   	     100	  20000000 ns/op
+BenchmarkKeygen-8       	       2	4000000000 ns/op	2000000000 ns/party
+BenchmarkExponent_Evaluate/degree=4-8         	    2000	    600000 ns/op
+BenchmarkRemoved-8      	    1000	      1000 ns/op
+PASS
+ok  	github.com/taurusgroup/multi-party-sig/pkg/paillier	3.0s
+`
+
+const currentOutput = `BenchmarkEncryption-16   	     100	  12500000 ns/op
+BenchmarkDecryption-16   	     100	  25000000 ns/op
+BenchmarkKeygen-16       	       2	3000000000 ns/op	1500000000 ns/party
+BenchmarkExponent_Evaluate/degree=4-16        	    2000	    800000 ns/op
+BenchmarkAdded-16        	    1000	      9000 ns/op
+`
+
+func TestParse(t *testing.T) {
+	results, err := Parse(strings.NewReader(baselineOutput))
+	require.NoError(t, err)
+	assert.Equal(t, Results{
+		"BenchmarkEncryption":                 11000000,
+		"BenchmarkDecryption":                 20000000,
+		"BenchmarkKeygen":                     4000000000,
+		"BenchmarkExponent_Evaluate/degree=4": 600000,
+		"BenchmarkRemoved":                    1000,
+	}, results)
+
+	_, err = Parse(strings.NewReader("BenchmarkBroken-8 100 12 allocs/op\n"))
+	assert.Error(t, err, "a result without ns/op should be rejected")
+}
+
+func TestCompare(t *testing.T) {
+	baseline, err := Parse(strings.NewReader(baselineOutput))
+	require.NoError(t, err)
+	current, err := Parse(strings.NewReader(currentOutput))
+	require.NoError(t, err)
+
+	regressions := Compare(baseline, current, 0.2)
+	require.Len(t, regressions, 2)
+	assert.Equal(t, "BenchmarkDecryption", regressions[0].Name, "+25% is above the threshold")
+	assert.Equal(t, "BenchmarkExponent_Evaluate/degree=4", regressions[1].Name)
+	assert.InDelta(t, 1.25, regressions[0].Ratio(), 1e-9)
+	assert.Equal(t, "BenchmarkDecryption: 20000000 ns/op -> 25000000 ns/op (+25.0%)", regressions[0].String())
+
+	assert.Empty(t, Compare(baseline, current, 0.5), "no benchmark slowed down by more than 50%")
+	assert.Empty(t, Compare(baseline, baseline, 0), "a baseline does not regress against itself")
+}
@@ -0,0 +1,52 @@
+// Command benchcheck fails if the benchmarks read from stdin regressed compared to a stored baseline.
+//
+// The baseline is recorded once on the CI machine, and each run is compared against it:
+//
+//	go test -run XXX -bench . -count 5 ./pkg/paillier ./pkg/math/... ./protocols/cmp/... > baseline.txt
+//	go test -run XXX -bench . -count 5 ./pkg/paillier ./pkg/math/... ./protocols/cmp/... | go run ./internal/bench/benchcheck -baseline baseline.txt
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/taurusgroup/multi-party-sig/internal/bench"
+)
+
+func main() {
+	baselinePath := flag.String("baseline", "", "path to the stored output of go test -bench")
+	threshold := flag.Float64("threshold", 0.2, "allowed slowdown, as a fraction of the baseline")
+	flag.Parse()
+	if *baselinePath == "" {
+		fmt.Fprintln(os.Stderr, "benchcheck: -baseline is required")
+		os.Exit(2)
+	}
+
+	f, err := os.Open(*baselinePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "benchcheck:", err)
+		os.Exit(2)
+	}
+	baseline, err := bench.Parse(f)
+	_ = f.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "benchcheck:", err)
+		os.Exit(2)
+	}
+	current, err := bench.Parse(os.Stdin)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "benchcheck:", err)
+		os.Exit(2)
+	}
+
+	regressions := bench.Compare(baseline, current, *threshold)
+	for _, r := range regressions {
+		fmt.Println(r)
+	}
+	if len(regressions) > 0 {
+		fmt.Printf("benchcheck: %d benchmarks regressed by more than %.0f%%\n", len(regressions), 100**threshold)
+		os.Exit(1)
+	}
+	fmt.Printf("benchcheck: %d benchmarks within %.0f%% of the baseline\n", len(current), 100**threshold)
+}
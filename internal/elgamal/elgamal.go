@@ -1,7 +1,6 @@
 package elgamal
 
 import (
-	"crypto/rand"
 	"io"
 
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
@@ -23,7 +22,7 @@ type Ciphertext struct {
 // Encrypt returns the encryption of `message` as (L=nonce⋅G, M=message⋅G + nonce⋅public), as well as the `nonce`.
 func Encrypt(public PublicKey, message curve.Scalar) (*Ciphertext, Nonce) {
 	group := public.Curve()
-	nonce := sample.Scalar(rand.Reader, group)
+	nonce := sample.Scalar(sample.Reader, group)
 	L := nonce.ActOnBase()
 	M := message.ActOnBase().Add(nonce.Act(public))
 	return &Ciphertext{
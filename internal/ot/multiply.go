@@ -1,8 +1,8 @@
 package ot
 
 import (
-	"crypto/rand"
 	"errors"
+	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
@@ -27,7 +27,7 @@ func encode(beta curve.Scalar, noise []curve.Scalar) ([]byte, error) {
 	group := beta.Curve()
 
 	gamma := make([]byte, len(noise)/8)
-	_, _ = rand.Read(gamma)
+	_, _ = io.ReadFull(sample.Reader, gamma)
 
 	acc := group.NewScalar().Set(beta)
 	mulNat := new(saferith.Nat)
@@ -59,7 +59,7 @@ func makeGadget(ctxHash *hash.Hash, group curve.Curve) []curve.Scalar {
 	for i := (scalarEnd >> 3) - 1; i >= 0; i-- {
 		for j := 0; j < 8; j++ {
 			out[(i<<3)|j] = group.NewScalar().Set(acc)
-			acc.Add(acc)
+			curve.DoubleScalar(acc)
 		}
 	}
 	// Generate random noise
@@ -92,7 +92,7 @@ func NewMultiplySender(ctxHash *hash.Hash, setup *CorreOTSendSetup, alpha curve.
 	gadget := makeGadget(ctxHash, group)
 	var doubleAlpha [2]curve.Scalar
 	doubleAlpha[0] = alpha
-	doubleAlpha[1] = sample.Scalar(rand.Reader, group)
+	doubleAlpha[1] = sample.Scalar(sample.Reader, group)
 	return &MultiplySender{
 		ctxHash:     ctxHash,
 		group:       group,
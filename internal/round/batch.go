@@ -0,0 +1,318 @@
+package round
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// batch runs several sessions in lockstep, as a single protocol execution.
+//
+// In each round, the messages produced by the sessions for the same recipient are bundled together,
+// so that the number of messages and round trips is the same as for a single session.
+type batch struct {
+	*Helper
+	sessions []Session
+	combine  func(results []interface{}) interface{}
+}
+
+// batchBroadcast is a batch whose sessions expect a broadcast message in the current round.
+type batchBroadcast struct {
+	*batch
+}
+
+// NewBatch returns a Session which runs all sessions in parallel.
+//
+// The sessions must all be in the same round, and have the same final round number as helper.
+// When all sessions have produced an output, the result of the batch is combine applied to the outputs,
+// in the same order as sessions.
+// If any session aborts, the whole batch is aborted.
+func NewBatch(helper *Helper, sessions []Session, combine func(results []interface{}) interface{}) (Session, error) {
+	if len(sessions) == 0 {
+		return nil, errors.New("batch: no sessions")
+	}
+	for i, s := range sessions {
+		if s == nil {
+			return nil, fmt.Errorf("batch: session %d is nil", i)
+		}
+		if s.FinalRoundNumber() != helper.FinalRoundNumber() {
+			return nil, fmt.Errorf("batch: session %d has final round %d, expected %d", i, s.FinalRoundNumber(), helper.FinalRoundNumber())
+		}
+	}
+	return newBatch(helper, sessions, combine)
+}
+
+func newBatch(helper *Helper, sessions []Session, combine func(results []interface{}) interface{}) (Session, error) {
+	number := sessions[0].Number()
+	isBroadcast := false
+	for i, s := range sessions {
+		if s.Number() != number {
+			return nil, fmt.Errorf("batch: session %d is in round %d, expected %d", i, s.Number(), number)
+		}
+		if _, ok := s.(BroadcastRound); ok {
+			isBroadcast = true
+		}
+	}
+
+	b := &batch{
+		Helper:   helper,
+		sessions: sessions,
+		combine:  combine,
+	}
+	if isBroadcast {
+		return &batchBroadcast{b}, nil
+	}
+	return b, nil
+}
+
+// subMessage returns the message for session i contained in msg.
+func subMessage(msg Message, content Content) Message {
+	return Message{
+		From:      msg.From,
+		To:        msg.To,
+		Broadcast: msg.Broadcast,
+		Content:   content,
+	}
+}
+
+// VerifyMessage implements Round.
+func (b *batch) VerifyMessage(msg Message) error {
+	body, ok := msg.Content.(*batchContent)
+	if !ok || body == nil || len(body.contents) != len(b.sessions) {
+		return ErrInvalidContent
+	}
+	for i, s := range b.sessions {
+		if body.contents[i] == nil {
+			continue
+		}
+		if err := s.VerifyMessage(subMessage(msg, body.contents[i])); err != nil {
+			return fmt.Errorf("batch %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// StoreMessage implements Round.
+func (b *batch) StoreMessage(msg Message) error {
+	body, ok := msg.Content.(*batchContent)
+	if !ok || body == nil || len(body.contents) != len(b.sessions) {
+		return ErrInvalidContent
+	}
+	for i, s := range b.sessions {
+		if body.contents[i] == nil {
+			continue
+		}
+		if err := s.StoreMessage(subMessage(msg, body.contents[i])); err != nil {
+			return fmt.Errorf("batch %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// StoreBroadcastMessage implements BroadcastRound.
+func (b *batchBroadcast) StoreBroadcastMessage(msg Message) error {
+	body, ok := msg.Content.(*batchBroadcastContent)
+	if !ok || body == nil || len(body.contents) != len(b.sessions) {
+		return ErrInvalidContent
+	}
+	for i, s := range b.sessions {
+		if body.contents[i] == nil {
+			continue
+		}
+		r, ok := s.(BroadcastRound)
+		if !ok {
+			return ErrInvalidContent
+		}
+		if err := r.StoreBroadcastMessage(subMessage(msg, body.contents[i])); err != nil {
+			return fmt.Errorf("batch %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// batchKey identifies the recipient of a message.
+type batchKey struct {
+	to        party.ID
+	broadcast bool
+}
+
+// Finalize implements Round.
+//
+// Each session is finalized, and the messages they produce are bundled by recipient.
+func (b *batch) Finalize(out chan<- *Message) (Session, error) {
+	var (
+		next     = make([]Session, len(b.sessions))
+		results  = make([]interface{}, 0, len(b.sessions))
+		keys     []batchKey
+		contents = map[batchKey][]Content{}
+	)
+	for i, s := range b.sessions {
+		subOut := make(chan *Message, cap(out))
+		r, err := s.Finalize(subOut)
+		close(subOut)
+		if err != nil {
+			return nil, fmt.Errorf("batch %d: %w", i, err)
+		}
+		switch R := r.(type) {
+		case *Abort:
+			return b.AbortRound(fmt.Errorf("batch %d: %w", i, R.Err), R.Culprits...), nil
+		case *Output:
+			results = append(results, R.Result)
+		}
+		next[i] = r
+
+		for msg := range subOut {
+			key := batchKey{to: msg.To, broadcast: msg.Broadcast}
+			if _, ok := contents[key]; !ok {
+				keys = append(keys, key)
+				contents[key] = make([]Content, len(b.sessions))
+			}
+			if contents[key][i] != nil {
+				return nil, fmt.Errorf("batch %d: more than one message for the same recipient", i)
+			}
+			contents[key][i] = msg.Content
+		}
+	}
+
+	if len(results) == len(b.sessions) {
+		return b.ResultRound(b.combine(results)), nil
+	}
+	if len(results) > 0 {
+		return nil, errors.New("batch: some sessions finished before others")
+	}
+
+	for _, key := range keys {
+		var number Number
+		for _, c := range contents[key] {
+			if c != nil {
+				number = c.RoundNumber()
+				break
+			}
+		}
+		content := &batchContent{number: number, contents: contents[key]}
+		var err error
+		if key.broadcast {
+			err = b.BroadcastMessage(out, &batchBroadcastContent{content})
+		} else {
+			err = b.SendMessage(out, content, key.to)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newBatch(b.Helper, next, b.combine)
+}
+
+// MessageContent implements Round.
+func (b *batch) MessageContent() Content {
+	templates := make([]Content, len(b.sessions))
+	empty := true
+	for i, s := range b.sessions {
+		templates[i] = s.MessageContent()
+		if templates[i] != nil {
+			empty = false
+		}
+	}
+	if empty {
+		return nil
+	}
+	return &batchContent{number: b.Number(), contents: templates}
+}
+
+// BroadcastContent implements BroadcastRound.
+func (b *batchBroadcast) BroadcastContent() BroadcastContent {
+	templates := make([]Content, len(b.sessions))
+	empty := true
+	for i, s := range b.sessions {
+		r, ok := s.(BroadcastRound)
+		if !ok {
+			continue
+		}
+		if c := r.BroadcastContent(); c != nil {
+			templates[i] = c
+			empty = false
+		}
+	}
+	if empty {
+		return nil
+	}
+	return &batchBroadcastContent{&batchContent{number: b.Number(), contents: templates}}
+}
+
+// Number implements Round.
+func (b *batch) Number() Number { return b.sessions[0].Number() }
+
+// batchContent bundles the contents of each session in a batch.
+// An entry is nil if the corresponding session does not send or expect a message.
+type batchContent struct {
+	number   Number
+	contents []Content
+}
+
+// RoundNumber implements Content.
+func (c *batchContent) RoundNumber() Number { return c.number }
+
+// MarshalCBOR implements cbor.Marshaler.
+func (c *batchContent) MarshalCBOR() ([]byte, error) {
+	raw := make([]cbor.RawMessage, len(c.contents))
+	for i, content := range c.contents {
+		if content == nil {
+			continue
+		}
+		data, err := cbor.Marshal(content)
+		if err != nil {
+			return nil, err
+		}
+		raw[i] = data
+	}
+	return cbor.Marshal(raw)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+//
+// c must have been created by MessageContent or BroadcastContent, so that each entry can be decoded
+// into the type expected by the corresponding session.
+func (c *batchContent) UnmarshalCBOR(data []byte) error {
+	var raw []cbor.RawMessage
+	if err := cbor.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) != len(c.contents) {
+		return fmt.Errorf("batch: got %d messages, expected %d", len(raw), len(c.contents))
+	}
+	for i := range raw {
+		isEmpty := len(raw[i]) == 0 || (len(raw[i]) == 1 && raw[i][0] == 0xf6)
+		if c.contents[i] == nil {
+			if !isEmpty {
+				return fmt.Errorf("batch %d: unexpected message", i)
+			}
+			continue
+		}
+		if isEmpty {
+			return fmt.Errorf("batch %d: missing message", i)
+		}
+		if err := cbor.Unmarshal(raw[i], c.contents[i]); err != nil {
+			return fmt.Errorf("batch %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// batchBroadcastContent is a batchContent for broadcast messages.
+type batchBroadcastContent struct {
+	*batchContent
+}
+
+// Reliable implements BroadcastContent.
+// The batch requires reliable broadcast as soon as one of its sessions does.
+func (c *batchBroadcastContent) Reliable() bool {
+	for _, content := range c.contents {
+		if b, ok := content.(BroadcastContent); ok && b.Reliable() {
+			return true
+		}
+	}
+	return false
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/taurusgroup/multi-party-sig/internal/types"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 )
@@ -31,6 +32,9 @@ type Helper struct {
 
 	hash *hash.Hash
 
+	// profile is nil unless profiling was enabled with SetProfile
+	profile *Profile
+
 	mtx sync.Mutex
 }
 
@@ -41,10 +45,20 @@ type Helper struct {
 // It could be a simple counter which is incremented after execution,  or a common random string.
 // `auxInfo` is a variable list of objects which should be included in the session's hash state.
 func NewSession(info Info, sessionID []byte, pl *pool.Pool, auxInfo ...hash.WriterToWithDomain) (*Helper, error) {
+	// the session will consume randomness, which must not come from a failing generator
+	if err := sample.HealthCheck(); err != nil {
+		return nil, fmt.Errorf("session: %w", err)
+	}
+
 	partyIDs := party.NewIDSlice(info.PartyIDs)
 	if !partyIDs.Valid() {
 		return nil, errors.New("session: partyIDs invalid")
 	}
+	for _, id := range partyIDs {
+		if err := id.Validate(); err != nil {
+			return nil, fmt.Errorf("session: %w", err)
+		}
+	}
 
 	// verify our ID is present
 	if !partyIDs.Contains(info.SelfID) {
@@ -196,6 +210,13 @@ func (h *Helper) AbortRound(err error, culprits ...party.ID) Session {
 	}
 }
 
+// SetProfile enables the recording of the phases started with StartPhase in profile.
+func (h *Helper) SetProfile(profile *Profile) { h.profile = profile }
+
+// StartPhase starts measuring a step of the current round, if profiling is enabled.
+// The returned function must be called when the step is done.
+func (h *Helper) StartPhase(name string) func() { return h.profile.StartPhase(name) }
+
 // ProtocolID is an identifier for this protocol.
 func (h *Helper) ProtocolID() string { return h.info.ProtocolID }
 
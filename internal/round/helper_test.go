@@ -69,6 +69,15 @@ func TestNewSession(t *testing.T) {
 			curve.Secp256k1{},
 			true,
 		},
+		{
+			"ID with a leading zero byte",
+			RNumber,
+			selfID,
+			append(partyIDs, "\x00"+partyIDs[1]),
+			T,
+			curve.Secp256k1{},
+			true,
+		},
 		{
 			"threshold N",
 			RNumber,
@@ -0,0 +1,121 @@
+package round
+
+import (
+	"sync"
+	"time"
+)
+
+// Timing is the wall-clock duration of a round, or of a phase within a round.
+type Timing struct {
+	// Round is the number of the round during which the time was spent.
+	Round Number
+	// Name describes the phase. It is empty for the timing of a full round.
+	Name string
+	// Duration is the wall-clock time spent.
+	Duration time.Duration
+}
+
+// Breakdown describes where the time was spent during a protocol execution.
+type Breakdown struct {
+	// Total is the wall-clock time from the start of the protocol until it finished.
+	Total time.Duration
+	// Rounds contains the time spent in each round, from the moment it started until it was finalized.
+	// This includes the time spent waiting for messages from other parties, so the rounds add up to Total.
+	Rounds []Timing
+	// Phases contains the time spent in the main steps of each round, such as generating proofs,
+	// in the order in which they finished.
+	// Phases may overlap when they run concurrently.
+	Phases []Timing
+}
+
+// Phase returns the sum of the durations of all phases with the given name.
+func (b *Breakdown) Phase(name string) time.Duration {
+	var total time.Duration
+	for _, p := range b.Phases {
+		if p.Name == name {
+			total += p.Duration
+		}
+	}
+	return total
+}
+
+// Profile records a Breakdown of a protocol execution.
+// It is safe for concurrent use, and its methods do nothing on a nil Profile.
+type Profile struct {
+	mtx        sync.Mutex
+	start      time.Time
+	roundStart time.Time
+	current    Number
+	finished   bool
+	breakdown  Breakdown
+}
+
+// NewProfile returns a Profile for an execution starting now, in round 1.
+func NewProfile() *Profile {
+	now := time.Now()
+	return &Profile{start: now, roundStart: now, current: 1}
+}
+
+// NextRound records the end of the current round, and the start of round number.
+func (p *Profile) NextRound(number Number) {
+	if p == nil {
+		return
+	}
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.finished {
+		return
+	}
+	now := time.Now()
+	p.breakdown.Rounds = append(p.breakdown.Rounds, Timing{Round: p.current, Duration: now.Sub(p.roundStart)})
+	p.roundStart = now
+	p.current = number
+}
+
+// Finish records the end of the current round, which is the last one.
+func (p *Profile) Finish() {
+	if p == nil {
+		return
+	}
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.finished {
+		return
+	}
+	now := time.Now()
+	p.breakdown.Rounds = append(p.breakdown.Rounds, Timing{Round: p.current, Duration: now.Sub(p.roundStart)})
+	p.breakdown.Total = now.Sub(p.start)
+	p.finished = true
+}
+
+// StartPhase starts measuring a phase of the current round.
+// The returned function must be called when the phase is done.
+func (p *Profile) StartPhase(name string) func() {
+	if p == nil {
+		return func() {}
+	}
+	p.mtx.Lock()
+	number := p.current
+	p.mtx.Unlock()
+	start := time.Now()
+	return func() {
+		duration := time.Since(start)
+		p.mtx.Lock()
+		defer p.mtx.Unlock()
+		p.breakdown.Phases = append(p.breakdown.Phases, Timing{Round: number, Name: name, Duration: duration})
+	}
+}
+
+// Breakdown returns a copy of the timings recorded so far.
+func (p *Profile) Breakdown() *Breakdown {
+	if p == nil {
+		return nil
+	}
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return &Breakdown{
+		Total:  p.breakdown.Total,
+		Rounds: append([]Timing(nil), p.breakdown.Rounds...),
+		Phases: append([]Timing(nil), p.breakdown.Phases...),
+	}
+}
@@ -0,0 +1,59 @@
+package round
+
+import (
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Schema describes the layout of the encoded content of a message,
+// so that malformed or oversized messages can be rejected before they are unmarshalled and verified.
+type Schema struct {
+	// MaxSize is the maximum length in bytes of the encoded content, or 0 to skip this check.
+	MaxSize int
+	// Fields maps the name of each field with a fixed size encoding, such as a curve.Point or a curve.Scalar,
+	// to the length in bytes of that encoding. These fields must be present.
+	Fields map[string]int
+}
+
+// SchemaRound is implemented by rounds which declare the Schema of the messages they receive.
+//
+// The handler checks the encoded content of a message against it before calling Round.VerifyMessage.
+type SchemaRound interface {
+	// MessageSchema returns the Schema of the broadcast or normal messages of this round,
+	// or nil if they should not be checked.
+	MessageSchema(broadcast bool) *Schema
+}
+
+// Check returns an error if data, the encoded content of a message, does not match s.
+//
+// Only the outer layer of data is decoded, which is much cheaper than unmarshalling the content.
+func (s *Schema) Check(data []byte) error {
+	if s == nil {
+		return nil
+	}
+	if s.MaxSize > 0 && len(data) > s.MaxSize {
+		return fmt.Errorf("schema: content has %d bytes, more than %d", len(data), s.MaxSize)
+	}
+	if len(s.Fields) == 0 {
+		return nil
+	}
+	var fields map[string]cbor.RawMessage
+	if err := cbor.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("schema: %w", err)
+	}
+	for name, size := range s.Fields {
+		raw, ok := fields[name]
+		if !ok {
+			return fmt.Errorf("schema: missing field %s", name)
+		}
+		var value []byte
+		if err := cbor.Unmarshal(raw, &value); err != nil {
+			return fmt.Errorf("schema: field %s: %w", name, err)
+		}
+		if len(value) != size {
+			return fmt.Errorf("schema: field %s has %d bytes, expected %d", name, len(value), size)
+		}
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ type Info struct {
 	// Threshold is the maximum number of parties that are assumed to be corrupted during the execution of this protocol.
 	Threshold int
 	// Group returns the group used for this protocol execution.
+	// Its name is included in the session's hash, so parties using different groups derive a different SSID.
 	Group curve.Curve
 }
 
@@ -0,0 +1,71 @@
+package round
+
+import "github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+
+// MessageSize describes the messages of a given type sent by a single party during one round.
+type MessageSize struct {
+	// Round is the round in which the message is received.
+	Round Number
+	// Broadcast is true if the message is sent to all parties using a broadcast.
+	Broadcast bool
+	// Size is the expected length in bytes of the serialized content of a single message.
+	// It does not include the header of protocol.Message.
+	Size int
+	// Count is the number of such messages sent by a party in this round.
+	Count int
+}
+
+// SizeSchema returns a Schema bounding the size of the messages of round number, with the given broadcast flag,
+// to margin times their size in sizes. It returns nil if sizes does not contain such messages.
+func SizeSchema(sizes []MessageSize, number Number, broadcast bool, margin int) *Schema {
+	for _, size := range sizes {
+		if size.Round == number && size.Broadcast == broadcast {
+			return &Schema{MaxSize: margin * size.Size}
+		}
+	}
+	return nil
+}
+
+// PointSize returns the length of the encoding of a point of group.
+func PointSize(group curve.Curve) int {
+	data, err := group.NewBasePoint().MarshalBinary()
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+// CBORHeader returns the length of the header of a CBOR item with the given argument.
+func CBORHeader(n int) int {
+	switch {
+	case n < 24:
+		return 1
+	case n < 1<<8:
+		return 2
+	case n < 1<<16:
+		return 3
+	case int64(n) < 1<<32:
+		return 5
+	default:
+		return 9
+	}
+}
+
+// CBORBytes returns the length of a CBOR byte string of length n.
+func CBORBytes(n int) int {
+	return CBORHeader(n) + n
+}
+
+// CBORArray returns the length of a CBOR array of n items, each of the given encoded length.
+func CBORArray(n, item int) int {
+	return CBORHeader(n) + n*item
+}
+
+// CBORMap returns the length of a CBOR encoded struct, given the lengths of its encoded fields.
+func CBORMap(fields map[string]int) int {
+	total := CBORHeader(len(fields))
+	for name, size := range fields {
+		total += CBORHeader(len(name)) + len(name) + size
+	}
+	return total
+}
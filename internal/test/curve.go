@@ -0,0 +1,18 @@
+package test
+
+import "github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+
+// OtherCurve pretends to be a different curve than secp256k1, to check that its elements are rejected.
+type OtherCurve struct{ curve.Secp256k1 }
+
+func (OtherCurve) Name() string { return "P-256" }
+
+// OtherPoint wraps a point so that it pretends to be an element of OtherCurve.
+type OtherPoint struct{ curve.Point }
+
+func (OtherPoint) Curve() curve.Curve { return OtherCurve{} }
+
+// OtherScalar wraps a scalar so that it pretends to be an element of OtherCurve.
+type OtherScalar struct{ curve.Scalar }
+
+func (OtherScalar) Curve() curve.Curve { return OtherCurve{} }
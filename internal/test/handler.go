@@ -25,3 +25,38 @@ func HandlerLoop(id party.ID, h protocol.Handler, network *Network) {
 		}
 	}
 }
+
+// Deliver passes the messages queued by the handlers to the other parties, until no handler has any message left.
+//
+// Unlike HandlerLoop, it runs on the calling goroutine, and returns once no more progress is made,
+// even if some handlers have not finished. If deliver is nil, the messages are given to Accept,
+// and otherwise deliver is called for each message and each of its recipients.
+func Deliver(handlers map[party.ID]*protocol.MultiHandler, deliver func(from, to party.ID, msg *protocol.Message)) {
+	if deliver == nil {
+		deliver = func(_, to party.ID, msg *protocol.Message) {
+			handlers[to].Accept(msg)
+		}
+	}
+	for delivered := true; delivered; {
+		delivered = false
+		for from, h := range handlers {
+			for more := true; more; {
+				select {
+				case msg, ok := <-h.Listen():
+					if !ok {
+						more = false
+						continue
+					}
+					delivered = true
+					for to := range handlers {
+						if to != from && msg.IsFor(to) {
+							deliver(from, to, msg)
+						}
+					}
+				default:
+					more = false
+				}
+			}
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/fxamacker/cbor/v2"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -133,6 +134,29 @@ func Rounds(rounds []round.Session, rule Rule) (error, bool) {
 	return nil, false
 }
 
+// StartRounds returns the first round of the session started by start(id) for each id in partyIDs, in the same order.
+func StartRounds(partyIDs []party.ID, start func(id party.ID) protocol.StartFunc) ([]round.Session, error) {
+	rounds := make([]round.Session, 0, len(partyIDs))
+	for _, id := range partyIDs {
+		r, err := start(id)(nil)
+		if err != nil {
+			return nil, err
+		}
+		rounds = append(rounds, r)
+	}
+	return rounds, nil
+}
+
+// RunRounds calls Rounds with rule until the protocol is done, or returns the first error.
+func RunRounds(rounds []round.Session, rule Rule) error {
+	for {
+		err, done := Rounds(rounds, rule)
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
 func checkAllRoundsSame(rounds []round.Session) (reflect.Type, error) {
 	var t reflect.Type
 	for _, r := range rounds {
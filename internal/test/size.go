@@ -0,0 +1,37 @@
+package test
+
+import (
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// SizeRule is a Rule which records the serialized size of each message sent by one party.
+type SizeRule struct {
+	from party.ID
+	// Sizes[number][broadcast] lists the sizes of the messages received in round number.
+	Sizes map[round.Number]map[bool][]int
+}
+
+// NewSizeRule returns a SizeRule recording the messages sent by from.
+func NewSizeRule(from party.ID) *SizeRule {
+	return &SizeRule{from: from, Sizes: map[round.Number]map[bool][]int{}}
+}
+
+func (*SizeRule) ModifyBefore(round.Session) {}
+func (*SizeRule) ModifyAfter(round.Session)  {}
+func (r *SizeRule) ModifyContent(rNext round.Session, _ party.ID, content round.Content) {
+	if rNext.SelfID() != r.from {
+		return
+	}
+	data, err := cbor.Marshal(content)
+	if err != nil {
+		panic(err)
+	}
+	_, broadcast := content.(round.BroadcastContent)
+	number := content.RoundNumber()
+	if r.Sizes[number] == nil {
+		r.Sizes[number] = map[bool][]int{}
+	}
+	r.Sizes[number][broadcast] = append(r.Sizes[number][broadcast], len(data))
+}
@@ -20,3 +20,18 @@ func (t SigningMessage) Domain() string {
 	}
 	return "Signature Message"
 }
+
+// SigningContext wraps a byte string agreed upon by all signers, which is bound to a signing session
+// without being part of the signed message.
+type SigningContext []byte
+
+// WriteTo implements io.WriterTo interface.
+func (t SigningContext) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(t)
+	return int64(n), err
+}
+
+// Domain implements hash.WriterToWithDomain.
+func (SigningContext) Domain() string {
+	return "Signature Context"
+}
@@ -0,0 +1,49 @@
+// Package bitcoin computes Bitcoin addresses for secp256k1 public keys, such as the public key of a threshold config.
+package bitcoin
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/taproot"
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck // HASH160 is required by P2WPKH
+)
+
+// Human-readable parts of segwit addresses.
+const (
+	MainnetHRP = "bc"
+	TestnetHRP = "tb"
+	RegtestHRP = "bcrt"
+)
+
+// P2WPKHAddress returns the pay-to-witness-public-key-hash address of publicKey,
+// that is the bech32 encoding of the version 0 witness program HASH160(compressed public key).
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0173.mediawiki
+func P2WPKHAddress(hrp string, publicKey *curve.Secp256k1Point) (string, error) {
+	if publicKey.IsIdentity() {
+		return "", errors.New("bitcoin: public key is the identity")
+	}
+	compressed, _ := publicKey.MarshalBinary()
+	sha := sha256.Sum256(compressed)
+	h := ripemd160.New()
+	_, _ = h.Write(sha[:])
+	return encodeSegwit(hrp, 0, h.Sum(nil))
+}
+
+// P2TRAddress returns the pay-to-taproot address whose output key is internalKey tweaked with merkleRoot,
+// encoded with bech32m as a version 1 witness program.
+//
+// merkleRoot is the root of the script tree, or empty for an output which can only be spent with the key,
+// as in BIP-86.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0341.mediawiki
+func P2TRAddress(hrp string, internalKey taproot.PublicKey, merkleRoot []byte) (string, error) {
+	outputKey, err := internalKey.TweakedKey(merkleRoot)
+	if err != nil {
+		return "", fmt.Errorf("bitcoin: %w", err)
+	}
+	return encodeSegwit(hrp, 1, outputKey)
+}
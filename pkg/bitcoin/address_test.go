@@ -0,0 +1,73 @@
+package bitcoin
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/taproot"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	data, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return data
+}
+
+func TestEncodeSegwit(t *testing.T) {
+	// test vectors from BIP-173 and BIP-350
+	vectors := []struct {
+		hrp     string
+		version byte
+		program string
+		address string
+	}{
+		{"bc", 0, "751e76e8199196d454941c45d1b3a323f1433bd6", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		{"tb", 0, "1863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262", "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7"},
+		{"bc", 1, "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0"},
+		{"tb", 1, "000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433", "tb1pqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesf3hn0c"},
+	}
+	for _, v := range vectors {
+		address, err := encodeSegwit(v.hrp, v.version, decodeHex(t, v.program))
+		require.NoError(t, err)
+		assert.Equal(t, v.address, address)
+	}
+
+	_, err := encodeSegwit("BC", 0, make([]byte, 20))
+	assert.Error(t, err)
+	_, err = encodeSegwit("bc", 17, make([]byte, 20))
+	assert.Error(t, err)
+}
+
+func TestP2WPKHAddress(t *testing.T) {
+	// the generator, whose addresses are given in BIP-173
+	G := curve.Secp256k1{}.NewBasePoint().(*curve.Secp256k1Point)
+	address, err := P2WPKHAddress(MainnetHRP, G)
+	require.NoError(t, err)
+	assert.Equal(t, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", address)
+	address, err = P2WPKHAddress(TestnetHRP, G)
+	require.NoError(t, err)
+	assert.Equal(t, "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", address)
+
+	_, err = P2WPKHAddress(MainnetHRP, curve.Secp256k1{}.NewPoint().(*curve.Secp256k1Point))
+	assert.Error(t, err)
+}
+
+func TestP2TRAddress(t *testing.T) {
+	// first receiving address of the BIP-86 test vectors, m/86'/0'/0'/0/0
+	internalKey := taproot.PublicKey(decodeHex(t, "cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115"))
+	address, err := P2TRAddress(MainnetHRP, internalKey, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", address)
+
+	// the same derivation on testnet, m/86'/1'/0'/0/0, from the mnemonic of the BIP-86 test vectors
+	internalKey = taproot.PublicKey(decodeHex(t, "55355ca83c973f1d97ce0e3843c85d78905af16b4dc531bc488e57212d230116"))
+	address, err = P2TRAddress(TestnetHRP, internalKey, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "tb1p8wpt9v4frpf3tkn0srd97pksgsxc5hs52lafxwru9kgeephvs7rqlqt9zj", address)
+
+	_, err = P2TRAddress(MainnetHRP, internalKey, make([]byte, 31))
+	assert.Error(t, err, "a merkle root of 31 bytes should be rejected")
+}
@@ -0,0 +1,93 @@
+package bitcoin
+
+import (
+	"errors"
+	"strings"
+)
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Checksum constants of bech32, used for witness version 0, and of bech32m, used for later versions.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0350.mediawiki
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+// bech32Polymod computes the BCH checksum of values, as defined in BIP-173.
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// hrpExpand returns the high bits of each character of hrp, a zero, and then their low bits.
+func hrpExpand(hrp string) []byte {
+	out := make([]byte, 0, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// convertBits regroups 8-bit bytes into 5-bit groups, padding the last group with zeros.
+func convertBits(data []byte) []byte {
+	out := make([]byte, 0, (len(data)*8+4)/5)
+	acc, bits := uint32(0), 0
+	for _, b := range data {
+		acc = acc<<8 | uint32(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out = append(out, byte(acc>>bits)&31)
+		}
+	}
+	if bits > 0 {
+		out = append(out, byte(acc<<(5-bits))&31)
+	}
+	return out
+}
+
+// encodeSegwit returns the address of a witness program of the given version,
+// encoded with bech32 for version 0, and with bech32m otherwise.
+func encodeSegwit(hrp string, version byte, program []byte) (string, error) {
+	if hrp == "" || hrp != strings.ToLower(hrp) {
+		return "", errors.New("bitcoin: the human-readable part must be non-empty and lowercase")
+	}
+	if version > 16 || len(program) < 2 || len(program) > 40 {
+		return "", errors.New("bitcoin: invalid witness program")
+	}
+	data := append([]byte{version}, convertBits(program)...)
+
+	constant := uint32(bech32Const)
+	if version > 0 {
+		constant = bech32mConst
+	}
+	values := append(hrpExpand(hrp), data...)
+	polymod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ constant
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, d := range data {
+		sb.WriteByte(bech32Charset[d])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[(polymod>>(5*(5-i)))&31])
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,246 @@
+// Package channel implements an optional encryption layer for the messages exchanged by the parties of a session.
+//
+// The protocols assume authenticated and confidential point-to-point channels, which this package provides
+// over an untrusted transport. Each party samples an ephemeral key for the session, and binds it to its pinned
+// identity, for instance its share of a Config, with a Schnorr proof. Every pair of parties then derives
+// a key for each direction from their ephemeral Diffie-Hellman secret, which is used with ChaCha20-Poly1305.
+package channel
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Errors returned by Channel.Open.
+var (
+	ErrUnknownPeer = errors.New("channel: unknown peer")
+	ErrTampered    = errors.New("channel: message could not be authenticated")
+	ErrReplayed    = errors.New("channel: message was replayed or reordered")
+	ErrMisrouted   = errors.New("channel: message does not match its envelope")
+)
+
+// sequenceLength is the number of bytes of the sequence number which prefixes every envelope.
+const sequenceLength = 8
+
+// Hello is the message each party sends to all others to start a Channel.
+type Hello struct {
+	// From is the party sending this Hello.
+	From party.ID
+	// Ephemeral = e⋅G is the ephemeral key of From for this session.
+	Ephemeral curve.Point
+	// Proof is a proof of knowledge of the identity secret of From, bound to Ephemeral and the session.
+	Proof *zksch.Proof
+}
+
+// EmptyHello returns a Hello with a fixed group, ready for unmarshalling.
+func EmptyHello(group curve.Curve) *Hello {
+	return &Hello{
+		Ephemeral: group.NewPoint(),
+		Proof:     zksch.EmptyProof(group),
+	}
+}
+
+// Handshake holds the ephemeral secret of a party, until the Hello of all other parties are received.
+type Handshake struct {
+	group     curve.Curve
+	selfID    party.ID
+	sessionID []byte
+	ephemeral curve.Scalar
+	hello     *Hello
+}
+
+// NewHandshake starts a handshake for party selfID, whose pinned identity is identity⋅G.
+//
+// The session ID must be unique, and shared by all parties, for instance the session ID of the protocol
+// which runs over the Channel. With a Config, identity is its ECDSA share, and the pinned identities of
+// the others are the ECDSA entries of its Public data.
+func NewHandshake(group curve.Curve, selfID party.ID, identity curve.Scalar, sessionID []byte) (*Handshake, error) {
+	if identity == nil || identity.IsZero() {
+		return nil, errors.New("channel: identity is zero")
+	}
+	if len(sessionID) == 0 {
+		return nil, errors.New("channel: session ID is empty")
+	}
+	ephemeral, Ephemeral := sample.ScalarPointPair(sample.Reader, group)
+	proof := zksch.NewProof(helloHash(sessionID, selfID, Ephemeral), identity.ActOnBase(), identity, nil)
+	return &Handshake{
+		group:     group,
+		selfID:    selfID,
+		sessionID: sessionID,
+		ephemeral: ephemeral,
+		hello: &Hello{
+			From:      selfID,
+			Ephemeral: Ephemeral,
+			Proof:     proof,
+		},
+	}, nil
+}
+
+// Hello returns the message to send to all other parties.
+func (h *Handshake) Hello() *Hello {
+	return h.hello
+}
+
+// Finish verifies the Hello of every party in identities other than this one,
+// and returns a Channel with all of them.
+//
+// identities maps every party to its pinned identity, which must have produced the proof in its Hello.
+// An error naming the party is returned if its Hello is missing, or if its proof does not verify.
+func (h *Handshake) Finish(identities map[party.ID]curve.Point, hellos []*Hello) (*Channel, error) {
+	received := make(map[party.ID]*Hello, len(hellos))
+	for _, hello := range hellos {
+		if hello == nil {
+			return nil, errors.New("channel: nil Hello")
+		}
+		if _, ok := received[hello.From]; ok {
+			return nil, fmt.Errorf("channel: party %s: duplicate Hello", hello.From)
+		}
+		received[hello.From] = hello
+	}
+
+	c := &Channel{
+		selfID:   h.selfID,
+		send:     make(map[party.ID]cipher.AEAD, len(identities)),
+		receive:  make(map[party.ID]cipher.AEAD, len(identities)),
+		sent:     make(map[party.ID]uint64, len(identities)),
+		received: make(map[party.ID]uint64, len(identities)),
+	}
+	for id, identity := range identities {
+		if id == h.selfID {
+			continue
+		}
+		hello, ok := received[id]
+		if !ok {
+			return nil, fmt.Errorf("channel: party %s: missing Hello", id)
+		}
+		if hello.Ephemeral == nil || hello.Ephemeral.IsIdentity() || curve.CheckCurve(h.group, hello.Ephemeral) != nil {
+			return nil, fmt.Errorf("channel: party %s: invalid ephemeral key", id)
+		}
+		if !hello.Proof.Verify(helloHash(h.sessionID, id, hello.Ephemeral), identity, nil) {
+			return nil, fmt.Errorf("channel: party %s: failed to prove its identity", id)
+		}
+		// eᵢ⋅Eⱼ = eⱼ⋅Eᵢ
+		shared := h.ephemeral.Act(hello.Ephemeral)
+		var err error
+		if c.send[id], err = h.deriveKey(shared, h.selfID, h.hello.Ephemeral, id, hello.Ephemeral); err != nil {
+			return nil, err
+		}
+		if c.receive[id], err = h.deriveKey(shared, id, hello.Ephemeral, h.selfID, h.hello.Ephemeral); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// deriveKey returns the AEAD for the messages from sender to receiver.
+func (h *Handshake) deriveKey(shared curve.Point, sender party.ID, senderEphemeral curve.Point, receiver party.ID, receiverEphemeral curve.Point) (cipher.AEAD, error) {
+	kdf := hash.New(&hash.BytesWithDomain{TheDomain: "Channel Key", Bytes: h.sessionID})
+	if err := kdf.WriteAny(shared, sender, senderEphemeral, receiver, receiverEphemeral); err != nil {
+		return nil, fmt.Errorf("channel: %w", err)
+	}
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(kdf.Digest(), key); err != nil {
+		return nil, fmt.Errorf("channel: %w", err)
+	}
+	return chacha20poly1305.New(key)
+}
+
+func helloHash(sessionID []byte, from party.ID, ephemeral curve.Point) *hash.Hash {
+	h := hash.New(&hash.BytesWithDomain{TheDomain: "Channel Hello", Bytes: sessionID})
+	_ = h.WriteAny(from, ephemeral)
+	return h
+}
+
+// Channel encrypts the messages of this party to the others, and decrypts the ones it receives.
+//
+// Every envelope carries a sequence number, so that replayed or reordered messages are rejected.
+// It is safe for concurrent use.
+type Channel struct {
+	selfID   party.ID
+	send     map[party.ID]cipher.AEAD
+	receive  map[party.ID]cipher.AEAD
+	sent     map[party.ID]uint64
+	received map[party.ID]uint64
+	mtx      sync.Mutex
+}
+
+// Seal returns the encryption of msg for the party to, which it can decrypt with Open.
+//
+// A broadcast message must be sealed once for each of its recipients.
+func (c *Channel) Seal(msg *protocol.Message, to party.ID) ([]byte, error) {
+	if !msg.IsFor(to) || msg.From != c.selfID {
+		return nil, ErrMisrouted
+	}
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("channel: %w", err)
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	aead, ok := c.send[to]
+	if !ok {
+		return nil, ErrUnknownPeer
+	}
+	c.sent[to]++
+	envelope := binary.BigEndian.AppendUint64(make([]byte, 0, sequenceLength+len(data)+aead.Overhead()), c.sent[to])
+	return aead.Seal(envelope, nonce(c.sent[to]), data, associatedData(c.selfID, to)), nil
+}
+
+// Open returns the message encrypted by Seal in envelope, which was received from the party from.
+//
+// It returns ErrTampered if envelope was modified or is not from the party from,
+// and ErrReplayed if it is not more recent than the last message opened from that party.
+func (c *Channel) Open(from party.ID, envelope []byte) (*protocol.Message, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	aead, ok := c.receive[from]
+	if !ok {
+		return nil, ErrUnknownPeer
+	}
+	if len(envelope) < sequenceLength {
+		return nil, ErrTampered
+	}
+	sequence := binary.BigEndian.Uint64(envelope)
+	data, err := aead.Open(nil, nonce(sequence), envelope[sequenceLength:], associatedData(from, c.selfID))
+	if err != nil {
+		return nil, ErrTampered
+	}
+	if sequence <= c.received[from] {
+		return nil, ErrReplayed
+	}
+
+	var msg protocol.Message
+	if err = msg.UnmarshalBinary(data); err != nil {
+		return nil, fmt.Errorf("channel: %w", err)
+	}
+	if msg.From != from || !msg.IsFor(c.selfID) {
+		return nil, ErrMisrouted
+	}
+	c.received[from] = sequence
+	return &msg, nil
+}
+
+// nonce encodes the sequence number of an envelope as a ChaCha20-Poly1305 nonce.
+// Each key is only used in one direction, so the sequence numbers never repeat for a key.
+func nonce(sequence uint64) []byte {
+	n := make([]byte, chacha20poly1305.NonceSize)
+	binary.BigEndian.PutUint64(n[chacha20poly1305.NonceSize-sequenceLength:], sequence)
+	return n
+}
+
+func associatedData(from, to party.ID) []byte {
+	return []byte(fmt.Sprintf("%q→%q", from, to))
+}
@@ -0,0 +1,144 @@
+package channel_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/channel"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/example"
+)
+
+// setup runs the handshake between partyIDs, with the Hello messages sent through cbor as over a network.
+func setup(t *testing.T, group curve.Curve, partyIDs party.IDSlice, sessionID []byte) map[party.ID]*channel.Channel {
+	secrets := make(map[party.ID]curve.Scalar, len(partyIDs))
+	identities := make(map[party.ID]curve.Point, len(partyIDs))
+	for _, id := range partyIDs {
+		secrets[id], identities[id] = sample.ScalarPointPair(rand.Reader, group)
+	}
+	handshakes := make(map[party.ID]*channel.Handshake, len(partyIDs))
+	hellos := make([]*channel.Hello, 0, len(partyIDs))
+	for _, id := range partyIDs {
+		h, err := channel.NewHandshake(group, id, secrets[id], sessionID)
+		require.NoError(t, err)
+		handshakes[id] = h
+		data, err := cbor.Marshal(h.Hello())
+		require.NoError(t, err)
+		hello := channel.EmptyHello(group)
+		require.NoError(t, cbor.Unmarshal(data, hello))
+		hellos = append(hellos, hello)
+	}
+	channels := make(map[party.ID]*channel.Channel, len(partyIDs))
+	for _, id := range partyIDs {
+		c, err := handshakes[id].Finish(identities, hellos)
+		require.NoError(t, err)
+		channels[id] = c
+	}
+	return channels
+}
+
+func TestChannel(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b", "c"}
+	channels := setup(t, group, partyIDs, []byte("session"))
+
+	secret := []byte("a secret share which must stay confidential")
+	msg := &protocol.Message{SSID: []byte("ssid"), From: "a", To: "b", Protocol: "test", RoundNumber: 1, Data: secret}
+	envelope, err := channels["a"].Seal(msg, "b")
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(envelope, secret), "envelope should not contain the plaintext")
+
+	opened, err := channels["b"].Open("a", envelope)
+	require.NoError(t, err)
+	assert.Equal(t, secret, opened.Data)
+	assert.Equal(t, msg.To, opened.To)
+
+	_, err = channels["b"].Open("a", envelope)
+	assert.ErrorIs(t, err, channel.ErrReplayed, "an envelope should only be opened once")
+	_, err = channels["c"].Open("a", envelope)
+	assert.ErrorIs(t, err, channel.ErrTampered, "another party should not be able to open the envelope")
+	_, err = channels["b"].Open("c", envelope)
+	assert.ErrorIs(t, err, channel.ErrTampered, "the sender should be authenticated")
+
+	envelope, err = channels["a"].Seal(msg, "b")
+	require.NoError(t, err)
+	for i := range envelope {
+		tampered := bytes.Clone(envelope)
+		tampered[i] ^= 1
+		_, err = channels["b"].Open("a", tampered)
+		assert.Error(t, err, "flipping byte %d should be detected", i)
+	}
+	_, err = channels["b"].Open("a", envelope[:len(envelope)-1])
+	assert.ErrorIs(t, err, channel.ErrTampered)
+	_, err = channels["b"].Open("a", envelope)
+	assert.NoError(t, err, "the original envelope should still open")
+
+	_, err = channels["a"].Seal(msg, "c")
+	assert.ErrorIs(t, err, channel.ErrMisrouted, "a message should only be sealed for its recipients")
+	_, err = channels["a"].Seal(&protocol.Message{From: "a", Data: secret}, "d")
+	assert.ErrorIs(t, err, channel.ErrUnknownPeer)
+}
+
+func TestHandshakeImpostor(t *testing.T) {
+	group := curve.Secp256k1{}
+	sessionID := []byte("session")
+	identity, Identity := sample.ScalarPointPair(rand.Reader, group)
+	honest, err := channel.NewHandshake(group, "a", identity, sessionID)
+	require.NoError(t, err)
+
+	// the impostor claims to be b, but does not know the secret of its pinned identity
+	_, pinned := sample.ScalarPointPair(rand.Reader, group)
+	impostor, err := channel.NewHandshake(group, "b", sample.Scalar(rand.Reader, group), sessionID)
+	require.NoError(t, err)
+	identities := map[party.ID]curve.Point{"a": Identity, "b": pinned}
+	_, err = honest.Finish(identities, []*channel.Hello{impostor.Hello()})
+	assert.EqualError(t, err, "channel: party b: failed to prove its identity")
+
+	// a Hello can't be replayed in another session
+	identity, identities["b"] = sample.ScalarPointPair(rand.Reader, group)
+	other, err := channel.NewHandshake(group, "b", identity, []byte("other session"))
+	require.NoError(t, err)
+	_, err = honest.Finish(identities, []*channel.Hello{other.Hello()})
+	assert.Error(t, err)
+	_, err = honest.Finish(identities, nil)
+	assert.EqualError(t, err, "channel: party b: missing Hello")
+}
+
+// TestChannelProtocol runs a protocol with all messages sent through the channels, over a transport which only sees envelopes.
+func TestChannelProtocol(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b", "c"}
+	sessionID := []byte("session")
+	channels := setup(t, group, partyIDs, sessionID)
+
+	handlers := make(map[party.ID]*protocol.MultiHandler, len(partyIDs))
+	for _, id := range partyIDs {
+		h, err := protocol.NewMultiHandler(example.StartXOR(id, partyIDs), sessionID)
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	test.Deliver(handlers, func(from, to party.ID, msg *protocol.Message) {
+		envelope, err := channels[from].Seal(msg, to)
+		require.NoError(t, err)
+		opened, err := channels[to].Open(from, envelope)
+		require.NoError(t, err)
+		handlers[to].Accept(opened)
+	})
+	var first interface{}
+	for _, id := range partyIDs {
+		r, err := handlers[id].Result()
+		require.NoError(t, err)
+		if first == nil {
+			first = r
+		}
+		assert.Equal(t, first, r, "all parties should obtain the same result")
+	}
+}
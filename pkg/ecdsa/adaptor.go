@@ -0,0 +1,138 @@
+package ecdsa
+
+import (
+	"errors"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// AdaptorPreSignature is a PreSignature bound to an adaptor point T = t⋅G.
+//
+// The signatures it produces use the nonce point R_T = t⋅R, and are only valid once adapted with t.
+type AdaptorPreSignature struct {
+	*PreSignature
+	// T = t⋅G
+	T curve.Point
+	// RT = δ⁻¹⋅(∑ⱼ γⱼ⋅T) = (k⁻¹t)⋅G
+	RT curve.Point
+}
+
+// EmptyAdaptorPreSignature returns an AdaptorPreSignature with a given group, ready for unmarshalling.
+func EmptyAdaptorPreSignature(group curve.Curve) *AdaptorPreSignature {
+	return &AdaptorPreSignature{
+		PreSignature: EmptyPreSignature(group),
+		T:            group.NewPoint(),
+		RT:           group.NewPoint(),
+	}
+}
+
+// SignatureShare returns this party's share σᵢ = kᵢm+rχᵢ, where r is the x-coordinate of R_T.
+func (sig *AdaptorPreSignature) SignatureShare(hash []byte) curve.Scalar {
+	return sig.signatureShare(curve.MessageToScalar(sig.Group(), hash), sig.RT.XScalar())
+}
+
+// Signature combines the given shares σⱼ and returns the adaptor signature (R, R_T, T, s̃), where s̃ = ∑ⱼσⱼ.
+func (sig *AdaptorPreSignature) Signature(shares map[party.ID]SignatureShare) *AdaptorSignature {
+	s := sig.Group().NewScalar()
+	for _, sigma := range shares {
+		s.Add(sigma)
+	}
+	return &AdaptorSignature{
+		R:  sig.R,
+		RT: sig.RT,
+		T:  sig.T,
+		S:  s,
+	}
+}
+
+// VerifySignatureShares should be called if the signature returned by AdaptorPreSignature.Signature is not valid.
+// It returns the list of parties whose shares are invalid.
+func (sig *AdaptorPreSignature) VerifySignatureShares(shares map[party.ID]SignatureShare, hash []byte) (culprits []party.ID) {
+	return sig.verifySignatureShares(shares, curve.MessageToScalar(sig.Group(), hash), sig.RT.XScalar())
+}
+
+func (sig *AdaptorPreSignature) Validate() error {
+	if sig.PreSignature == nil {
+		return errors.New("presignature: missing presignature")
+	}
+	if err := sig.PreSignature.Validate(); err != nil {
+		return err
+	}
+	if sig.T == nil || sig.T.IsIdentity() {
+		return errors.New("presignature: T is identity")
+	}
+	if sig.RT == nil || sig.RT.IsIdentity() {
+		return errors.New("presignature: RT is identity")
+	}
+	return nil
+}
+
+// AdaptorSignature is an ECDSA signature which only becomes valid once adapted with the discrete logarithm t of T.
+//
+// It can be exchanged for a hash-locked payment in an atomic swap:
+// publishing the adapted signature reveals t to anyone holding the AdaptorSignature.
+type AdaptorSignature struct {
+	// R = k⁻¹⋅G
+	R curve.Point
+	// RT = t⋅R, the nonce point of the adapted signature.
+	RT curve.Point
+	// T = t⋅G
+	T curve.Point
+	// S = s̃ = k⋅(m + r⋅x), where r is the x-coordinate of RT.
+	S curve.Scalar
+}
+
+// Verify returns true if s̃⁻¹⋅(m⋅G + r⋅X) = R.
+//
+// It does not show that RT = t⋅R, which is guaranteed by the protocol that produced the signature.
+func (sig *AdaptorSignature) Verify(X curve.Point, hash []byte) bool {
+	group := X.Curve()
+
+	r := sig.RT.XScalar()
+	if r.IsZero() || sig.S.IsZero() {
+		return false
+	}
+
+	sInv := group.NewScalar().Set(sig.S).Invert()
+	m := curve.MessageToScalar(group, hash)
+	R2 := sInv.Act(m.ActOnBase().Add(r.Act(X)))
+	return R2.Equal(sig.R)
+}
+
+// Adapt completes the adaptor signature with t, and returns the ECDSA signature (R_T, s̃⋅t⁻¹).
+func Adapt(sig *AdaptorSignature, t curve.Scalar) (*Signature, error) {
+	if t == nil || t.IsZero() {
+		return nil, errors.New("ecdsa: adaptor secret is zero")
+	}
+	if !t.ActOnBase().Equal(sig.T) {
+		return nil, errors.New("ecdsa: adaptor secret does not match T")
+	}
+	group := sig.T.Curve()
+	tInv := group.NewScalar().Set(t).Invert()
+	return &Signature{
+		R: sig.RT,
+		S: tInv.Mul(sig.S),
+	}, nil
+}
+
+// Extract returns the adaptor secret t = s̃⋅s⁻¹, given the signature obtained by adapting sig.
+//
+// The signature may have been normalized to use -s instead of s.
+func Extract(sig *AdaptorSignature, signature *Signature) (curve.Scalar, error) {
+	if signature == nil || signature.S == nil || signature.S.IsZero() {
+		return nil, errors.New("ecdsa: invalid signature")
+	}
+	if signature.R == nil || !signature.R.Equal(sig.RT) {
+		return nil, errors.New("ecdsa: signature was not adapted from this adaptor signature")
+	}
+	group := sig.T.Curve()
+	t := group.NewScalar().Set(signature.S).Invert().Mul(sig.S)
+	if t.ActOnBase().Equal(sig.T) {
+		return t, nil
+	}
+	if t.Negate().ActOnBase().Equal(sig.T) {
+		return t, nil
+	}
+	return nil, errors.New("ecdsa: extracted secret does not match T")
+}
@@ -45,8 +45,11 @@ type SignatureShare = curve.Scalar
 
 // SignatureShare returns this party's share σᵢ = kᵢm+rχᵢ, where s = ∑ⱼσⱼ.
 func (sig *PreSignature) SignatureShare(hash []byte) curve.Scalar {
-	m := curve.FromHash(sig.Group(), hash)
-	r := sig.R.XScalar()
+	return sig.signatureShare(curve.MessageToScalar(sig.Group(), hash), sig.R.XScalar())
+}
+
+// signatureShare returns σᵢ = kᵢm+rχᵢ for the message m and the x-coordinate r of the signature's nonce point.
+func (sig *PreSignature) signatureShare(m, r curve.Scalar) curve.Scalar {
 	mk := m.Mul(sig.KShare)
 	rx := r.Mul(sig.ChiShare)
 	sigma := mk.Add(rx)
@@ -68,8 +71,11 @@ func (sig *PreSignature) Signature(shares map[party.ID]SignatureShare) *Signatur
 // VerifySignatureShares should be called if the signature returned by PreSignature.Signature is not valid.
 // It returns the list of parties whose shares are invalid.
 func (sig *PreSignature) VerifySignatureShares(shares map[party.ID]SignatureShare, hash []byte) (culprits []party.ID) {
-	r := sig.R.XScalar()
-	m := curve.FromHash(sig.Group(), hash)
+	return sig.verifySignatureShares(shares, curve.MessageToScalar(sig.Group(), hash), sig.R.XScalar())
+}
+
+// verifySignatureShares returns the parties whose shares do not satisfy σⱼ⋅R = m⋅R̄ⱼ + r⋅Sⱼ.
+func (sig *PreSignature) verifySignatureShares(shares map[party.ID]SignatureShare, m, r curve.Scalar) (culprits []party.ID) {
 	for j, share := range shares {
 		Rj, Sj := sig.RBar.Points[j], sig.S.Points[j]
 		if Rj == nil || Sj == nil {
@@ -1,9 +1,19 @@
 package ecdsa
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 )
 
+// signatureVersion is the first byte of the encoding produced by MarshalBinary.
+const signatureVersion byte = 1
+
+// Signature is the result of an ECDSA signing protocol.
+//
+// R is the nonce point, from which the r value is obtained with RX, and S is the s value.
+// Together with the public key, these are the inputs of the DER, compact and recoverable encodings.
 type Signature struct {
 	R curve.Point
 	S curve.Scalar
@@ -14,16 +24,25 @@ func EmptySignature(group curve.Curve) Signature {
 	return Signature{R: group.NewPoint(), S: group.NewScalar()}
 }
 
+// RX returns r, the x-coordinate of R reduced modulo the group order, as a new Scalar.
+func (sig Signature) RX() curve.Scalar {
+	return sig.R.XScalar()
+}
+
 // Verify is a custom signature format using curve data.
 func (sig Signature) Verify(X curve.Point, hash []byte) bool {
+	return sig.VerifyScalar(X, curve.MessageToScalar(X.Curve(), hash))
+}
+
+// VerifyScalar is the same as Verify, but for a message which was already reduced to the scalar m.
+func (sig Signature) VerifyScalar(X curve.Point, m curve.Scalar) bool {
 	group := X.Curve()
 
-	r := sig.R.XScalar()
+	r := sig.RX()
 	if r.IsZero() || sig.S.IsZero() {
 		return false
 	}
 
-	m := curve.FromHash(group, hash)
 	sInv := group.NewScalar().Set(sig.S).Invert()
 	mG := m.ActOnBase()
 	rX := r.Act(X)
@@ -32,6 +51,124 @@ func (sig Signature) Verify(X curve.Point, hash []byte) bool {
 	return R2.Equal(sig.R)
 }
 
+// IsLowS returns true if s is at most half the group order, as required by Bitcoin and Ethereum.
+func (sig Signature) IsLowS() bool {
+	return !sig.S.IsOverHalfOrder()
+}
+
+// Normalize returns the equivalent signature (-R, -s) if s is greater than half the group order,
+// and a copy of sig otherwise.
+//
+// Both signatures have the same r, and are valid for the same message and public key.
+// Negating R flips the parity of its y-coordinate, so that the recovery id derived from R remains correct.
+func (sig Signature) Normalize() *Signature {
+	group := sig.R.Curve()
+	if sig.IsLowS() {
+		return &Signature{R: sig.R, S: group.NewScalar().Set(sig.S)}
+	}
+	return &Signature{R: sig.R.Negate(), S: group.NewScalar().Set(sig.S).Negate()}
+}
+
+// Bytes returns the signature as r ‖ s, where r is the x-coordinate of R reduced modulo the group order.
+// For secp256k1, this is the 64 byte layout expected by most other ECDSA implementations.
+//
+// s is returned as is, and may be greater than half the group order.
+// Verifiers enforcing low-s (such as Bitcoin or Ethereum) require s to be replaced by -s in that case.
+// No recovery id is included, since other libraries disagree on its encoding.
+// SigEthereum can be used to obtain the 65 byte format with a recovery id.
+func (sig Signature) Bytes() ([]byte, error) {
+	r, err := sig.RX().MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	s, err := sig.S.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(r, s...), nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+//
+// The encoding is self-describing, so that a signature can be stored and verified later:
+// version ‖ len(name) ‖ name ‖ R ‖ s, where name is the name of the curve,
+// and R is the encoding of the point given by the curve.
+// For secp256k1, R is compressed, and therefore also determines the recovery id.
+func (sig Signature) MarshalBinary() ([]byte, error) {
+	if sig.R == nil || sig.S == nil {
+		return nil, errors.New("ecdsa: signature is incomplete")
+	}
+	name := sig.R.Curve().Name()
+	if len(name) > 0xff {
+		return nil, errors.New("ecdsa: curve name is too long")
+	}
+	R, err := sig.R.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	S, err := sig.S.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, 2+len(name)+len(R)+len(S))
+	out = append(out, signatureVersion, byte(len(name)))
+	out = append(out, name...)
+	out = append(out, R...)
+	return append(out, S...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+//
+// If sig was created with EmptySignature, the encoded curve must match.
+// Otherwise, the curve is determined from the encoding.
+func (sig *Signature) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return errors.New("ecdsa: signature is too short")
+	}
+	if data[0] != signatureVersion {
+		return fmt.Errorf("ecdsa: unknown signature version %d", data[0])
+	}
+	nameLength := int(data[1])
+	if len(data) < 2+nameLength {
+		return errors.New("ecdsa: signature is too short")
+	}
+	name := string(data[2 : 2+nameLength])
+	data = data[2+nameLength:]
+
+	var group curve.Curve
+	if sig.R != nil {
+		group = sig.R.Curve()
+		if group.Name() != name {
+			return fmt.Errorf("ecdsa: expected a signature over %s, got %s", group.Name(), name)
+		}
+	} else {
+		switch name {
+		case curve.Secp256k1{}.Name():
+			group = curve.Secp256k1{}
+		default:
+			return fmt.Errorf("ecdsa: unknown curve %s", name)
+		}
+	}
+
+	// the encoding of R has a fixed length, given by the encoding of any point
+	pointData, err := group.NewBasePoint().MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if len(data) < len(pointData) {
+		return errors.New("ecdsa: signature is too short")
+	}
+	R, S := group.NewPoint(), group.NewScalar()
+	if err = R.UnmarshalBinary(data[:len(pointData)]); err != nil {
+		return fmt.Errorf("ecdsa: %w", err)
+	}
+	if err = S.UnmarshalBinary(data[len(pointData):]); err != nil {
+		return fmt.Errorf("ecdsa: %w", err)
+	}
+	sig.R, sig.S = R, S
+	return nil
+}
+
 // get a signature in ethereum format
 func (sig Signature) SigEthereum() ([]byte, error) {
 	IsOverHalfOrder := sig.S.IsOverHalfOrder() // s-values greater than secp256k1n/2 are considered invalid
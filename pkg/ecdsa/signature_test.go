@@ -2,8 +2,11 @@ package ecdsa
 
 import (
 	"crypto/rand"
+	"math/big"
 	"testing"
 
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	decred "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 )
@@ -37,6 +40,33 @@ func TestSignature_Verify(t *testing.T) {
 	}
 }
 
+func TestSignature_RX(t *testing.T) {
+	group := curve.Secp256k1{}
+	order := group.Order().Big()
+
+	for i := 0; i < 16; i++ {
+		x := sample.Scalar(rand.Reader, group)
+		sig := NewSignature(x, []byte("hello"), nil)
+
+		// r = R|ₓ mod n, computed independently of XScalar
+		xBytes := sig.R.(*curve.Secp256k1Point).XBytes()
+		expected := new(big.Int).Mod(new(big.Int).SetBytes(xBytes), order)
+		rBytes, err := sig.RX().MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if new(big.Int).SetBytes(rBytes).Cmp(expected) != 0 {
+			t.Errorf("RX should be the x-coordinate of R reduced modulo n")
+		}
+
+		// the result is a copy
+		sig.RX().Add(curve.ScalarOne(group))
+		if !sig.RX().Equal(sig.R.XScalar()) {
+			t.Error("modifying the result of RX should not modify the signature")
+		}
+	}
+}
+
 func TestSignature_Verify_Zero(t *testing.T) {
 	group := curve.Secp256k1{}
 
@@ -59,3 +89,114 @@ func TestSignature_Verify_Zero(t *testing.T) {
 		t.Error("zero R/S signature should not verify")
 	}
 }
+
+func TestSignature_Bytes(t *testing.T) {
+	group := curve.Secp256k1{}
+
+	m := make([]byte, 32)
+	_, _ = rand.Read(m)
+	x := sample.Scalar(rand.Reader, group)
+	X := x.ActOnBase()
+	sig := NewSignature(x, m, nil)
+
+	sigBytes, err := sig.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sigBytes) != 64 {
+		t.Fatalf("expected 64 bytes, got %d", len(sigBytes))
+	}
+
+	// verify with an independent implementation
+	XBytes, err := X.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := secp256k1.ParsePubKey(XBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r, s secp256k1.ModNScalar
+	if r.SetByteSlice(sigBytes[:32]) || s.SetByteSlice(sigBytes[32:]) {
+		t.Fatal("signature bytes overflow the group order")
+	}
+	if !decred.NewSignature(&r, &s).Verify(m, pk) {
+		t.Error("signature should verify with decred's implementation")
+	}
+}
+
+func TestSignature_MarshalBinary(t *testing.T) {
+	group := curve.Secp256k1{}
+	x := sample.Scalar(rand.Reader, group)
+	X := x.ActOnBase()
+	hash := make([]byte, 32)
+	_, _ = rand.Read(hash)
+	sig := NewSignature(x, hash, nil)
+
+	data, err := sig.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the curve is read from the encoding
+	var decoded Signature
+	if err = decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.R.Equal(sig.R) || !decoded.S.Equal(sig.S) {
+		t.Error("signature should round trip")
+	}
+	if !decoded.Verify(X, hash) {
+		t.Error("decoded signature should verify")
+	}
+
+	empty := EmptySignature(group)
+	if err = empty.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !empty.Verify(X, hash) {
+		t.Error("decoded signature should verify")
+	}
+
+	for _, bad := range [][]byte{
+		nil,
+		append([]byte{2}, data[1:]...),
+		data[:len(data)-1],
+		append([]byte{1, 1, 'x'}, data[2+len(group.Name()):]...),
+	} {
+		var s Signature
+		if s.UnmarshalBinary(bad) == nil {
+			t.Errorf("invalid signature %x should be rejected", bad)
+		}
+	}
+}
+
+func TestSignature_Normalize(t *testing.T) {
+	group := curve.Secp256k1{}
+
+	m := []byte("hello")
+	x := sample.Scalar(rand.Reader, group)
+	X := x.ActOnBase()
+	sig := NewSignature(x, m, nil)
+	high := sig
+	if sig.IsLowS() {
+		high = &Signature{R: sig.R.Negate(), S: group.NewScalar().Set(sig.S).Negate()}
+	}
+	if high.IsLowS() || !high.Verify(X, m) {
+		t.Fatal("expected a valid high-s signature")
+	}
+
+	normalized := high.Normalize()
+	if !normalized.IsLowS() {
+		t.Error("normalized signature should have a low s")
+	}
+	if !normalized.Verify(X, m) {
+		t.Error("normalized signature should be valid")
+	}
+	if !normalized.R.XScalar().Equal(high.R.XScalar()) {
+		t.Error("normalization should not change r")
+	}
+	if high.IsLowS() {
+		t.Error("Normalize should not modify the signature")
+	}
+}
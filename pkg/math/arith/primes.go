@@ -0,0 +1,31 @@
+package arith
+
+import "math/big"
+
+// SmallFactorBound is the bound below which a Paillier modulus N, and ϕ(N) for a modulus made of safe primes,
+// may have no odd prime factor.
+const SmallFactorBound = 1 << 12
+
+// smallPrimesProduct = ∏ r, for all odd primes r < SmallFactorBound.
+var smallPrimesProduct = productOfOddPrimes(SmallFactorBound)
+
+// SmallPrimesProduct returns ∏ r, for all odd primes r < SmallFactorBound.
+func SmallPrimesProduct() *big.Int {
+	return new(big.Int).Set(smallPrimesProduct)
+}
+
+// productOfOddPrimes returns the product of all odd primes < below.
+func productOfOddPrimes(below int) *big.Int {
+	sieve := make([]bool, below)
+	product := big.NewInt(1)
+	for p := 3; p < below; p += 2 {
+		if sieve[p] {
+			continue
+		}
+		product.Mul(product, big.NewInt(int64(p)))
+		for i := p * p; i < below; i += 2 * p {
+			sieve[i] = true
+		}
+	}
+	return product
+}
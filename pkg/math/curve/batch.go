@@ -0,0 +1,39 @@
+package curve
+
+// BatchInvert returns the inverses of scalars, which must all belong to the same group, as new scalars.
+//
+// It uses Montgomery's trick, which replaces n inversions by a single one and 3(n-1) multiplications.
+// As with Scalar.Invert, the inverse of 0 is 0: zero scalars are skipped, and do not affect the others.
+func BatchInvert(scalars []Scalar) []Scalar {
+	inverses := make([]Scalar, len(scalars))
+	if len(scalars) == 0 {
+		return inverses
+	}
+	group := scalars[0].Curve()
+
+	// prefixes[i] = s₀⋅⋅⋅sᵢ, ignoring the zero scalars
+	prefixes := make([]Scalar, len(scalars))
+	product := ScalarOne(group)
+	for i, s := range scalars {
+		if !s.IsZero() {
+			product.Mul(s)
+		}
+		prefixes[i] = group.NewScalar().Set(product)
+	}
+
+	// inverse = (s₀⋅⋅⋅sᵢ)⁻¹, starting with the last i
+	inverse := product.Invert()
+	for i := len(scalars) - 1; i >= 0; i-- {
+		if scalars[i].IsZero() {
+			inverses[i] = group.NewScalar()
+			continue
+		}
+		// sᵢ⁻¹ = (s₀⋅⋅⋅sᵢ)⁻¹ ⋅ (s₀⋅⋅⋅sᵢ₋₁)
+		inverses[i] = group.NewScalar().Set(inverse)
+		if i > 0 {
+			inverses[i].Mul(prefixes[i-1])
+		}
+		inverse.Mul(scalars[i])
+	}
+	return inverses
+}
@@ -0,0 +1,61 @@
+package curve_test
+
+import (
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+)
+
+func TestBatchInvert(t *testing.T) {
+	group := curve.Secp256k1{}
+	assert.Empty(t, curve.BatchInvert(nil))
+
+	scalars := make([]curve.Scalar, 16)
+	for i := range scalars {
+		scalars[i] = sample.Scalar(rand.Reader, group)
+	}
+	// zero scalars at the start, in the middle and at the end
+	scalars[0], scalars[7], scalars[15] = group.NewScalar(), group.NewScalar(), group.NewScalar()
+	originals := make([]curve.Scalar, len(scalars))
+	for i, s := range scalars {
+		originals[i] = group.NewScalar().Set(s)
+	}
+
+	inverses := curve.BatchInvert(scalars)
+	require.Len(t, inverses, len(scalars))
+	for i, s := range scalars {
+		assert.True(t, s.Equal(originals[i]), "scalars should not be modified")
+		assert.True(t, group.NewScalar().Set(s).Invert().Equal(inverses[i]), "inverse %d should match Invert", i)
+		if !s.IsZero() {
+			assert.True(t, group.NewScalar().Set(s).Mul(inverses[i]).Equal(curve.ScalarOne(group)))
+		}
+	}
+	assert.True(t, curve.BatchInvert([]curve.Scalar{group.NewScalar()})[0].IsZero())
+}
+
+func BenchmarkBatchInvert(b *testing.B) {
+	group := curve.Secp256k1{}
+	for _, n := range []int{4, 16, 64} {
+		scalars := make([]curve.Scalar, n)
+		for i := range scalars {
+			scalars[i] = sample.Scalar(rand.Reader, group)
+		}
+		b.Run(fmt.Sprintf("batch/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				curve.BatchInvert(scalars)
+			}
+		})
+		b.Run(fmt.Sprintf("individual/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, s := range scalars {
+					group.NewScalar().Set(s).Invert()
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package curve_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/cronokirby/saferith"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// toyCurve is the additive group ℤ/(h⋅ℓ), with cofactor h = 4 and prime order subgroup ℓ = 1019, generated by h.
+//
+// It is only meant to check the cofactor clearing rules, and the fallbacks of the optional methods,
+// which it does not implement, apart from HasCofactor and ClearCofactor.
+type toyCurve struct{}
+
+const (
+	toyOrder    = 1019
+	toyCofactor = 4
+	toyModulus  = toyOrder * toyCofactor
+)
+
+type toyScalar struct{ v uint64 }
+
+type toyPoint struct{ v uint64 }
+
+func (toyCurve) NewPoint() curve.Point     { return &toyPoint{} }
+func (toyCurve) NewBasePoint() curve.Point { return &toyPoint{v: toyCofactor} }
+func (toyCurve) NewScalar() curve.Scalar   { return &toyScalar{} }
+func (toyCurve) Name() string              { return "toy" }
+func (toyCurve) ScalarBits() int           { return 10 }
+func (toyCurve) SafeScalarBytes() int      { return 8 }
+func (toyCurve) HasCofactor() bool         { return true }
+func (toyCurve) Order() *saferith.Modulus {
+	return saferith.ModulusFromUint64(toyOrder)
+}
+
+func (s *toyScalar) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint64(nil, s.v), nil
+}
+func (s *toyScalar) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return errors.New("toyScalar: invalid length")
+	}
+	s.v = binary.BigEndian.Uint64(data) % toyOrder
+	return nil
+}
+func (*toyScalar) Curve() curve.Curve { return toyCurve{} }
+func (s *toyScalar) Add(t curve.Scalar) curve.Scalar {
+	s.v = (s.v + t.(*toyScalar).v) % toyOrder
+	return s
+}
+func (s *toyScalar) Sub(t curve.Scalar) curve.Scalar {
+	s.v = (s.v + toyOrder - t.(*toyScalar).v) % toyOrder
+	return s
+}
+func (s *toyScalar) Negate() curve.Scalar { s.v = (toyOrder - s.v) % toyOrder; return s }
+func (s *toyScalar) Mul(t curve.Scalar) curve.Scalar {
+	s.v = s.v * t.(*toyScalar).v % toyOrder
+	return s
+}
+func (s *toyScalar) Invert() curve.Scalar {
+	// s⁻¹ = s^(ℓ-2)
+	result, base := uint64(1), s.v
+	for e := uint64(toyOrder - 2); e > 0; e >>= 1 {
+		if e&1 == 1 {
+			result = result * base % toyOrder
+		}
+		base = base * base % toyOrder
+	}
+	s.v = result
+	return s
+}
+func (s *toyScalar) Equal(t curve.Scalar) bool       { return s.v == t.(*toyScalar).v }
+func (s *toyScalar) IsZero() bool                    { return s.v == 0 }
+func (s *toyScalar) Set(t curve.Scalar) curve.Scalar { s.v = t.(*toyScalar).v; return s }
+func (s *toyScalar) SetNat(n *saferith.Nat) curve.Scalar {
+	s.v = new(saferith.Nat).Mod(n, saferith.ModulusFromUint64(toyOrder)).Big().Uint64()
+	return s
+}
+func (s *toyScalar) Act(p curve.Point) curve.Point {
+	return &toyPoint{v: s.v * p.(*toyPoint).v % toyModulus}
+}
+func (s *toyScalar) ActOnBase() curve.Point { return s.Act(toyCurve{}.NewBasePoint()) }
+func (s *toyScalar) IsOverHalfOrder() bool  { return s.v > toyOrder/2 }
+
+func (p *toyPoint) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint64(nil, p.v), nil
+}
+
+// UnmarshalBinary clears the cofactor of the decoded point, as required by curve.HasCofactor.
+func (p *toyPoint) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return errors.New("toyPoint: invalid length")
+	}
+	p.v = binary.BigEndian.Uint64(data) % toyModulus
+	p.v = p.ClearCofactor().(*toyPoint).v
+	return nil
+}
+func (*toyPoint) Curve() curve.Curve { return toyCurve{} }
+func (p *toyPoint) Add(q curve.Point) curve.Point {
+	return &toyPoint{v: (p.v + q.(*toyPoint).v) % toyModulus}
+}
+func (p *toyPoint) Sub(q curve.Point) curve.Point { return p.Add(q.Negate()) }
+func (p *toyPoint) Negate() curve.Point           { return &toyPoint{v: (toyModulus - p.v) % toyModulus} }
+func (p *toyPoint) Equal(q curve.Point) bool      { return p.v == q.(*toyPoint).v }
+func (p *toyPoint) IsIdentity() bool              { return p.v == 0 }
+func (*toyPoint) XScalar() curve.Scalar           { return nil }
+
+// ClearCofactor returns [h⋅(h⁻¹ mod ℓ)]P, which is P on the subgroup, and the identity on points of order h.
+func (p *toyPoint) ClearCofactor() curve.Point {
+	hInv := (&toyScalar{v: toyCofactor}).Invert().(*toyScalar).v
+	return &toyPoint{v: toyCofactor * hInv % toyModulus * p.v % toyModulus}
+}
+
+func TestClearCofactor(t *testing.T) {
+	group := toyCurve{}
+	require.True(t, curve.HasCofactor(group))
+
+	// ℓ has order h, and is not in the subgroup generated by h
+	torsion := &toyPoint{v: toyOrder}
+	assert.True(t, curve.ClearCofactor(torsion).IsIdentity(), "a torsion point should be cleared to the identity")
+
+	P := (&toyScalar{v: 123}).ActOnBase()
+	assert.True(t, curve.ClearCofactor(P).Equal(P), "points of the subgroup should not change")
+	assert.True(t, curve.ClearCofactor(P.Add(torsion)).Equal(P), "the torsion component should be removed")
+
+	// received points are cleared when decoded
+	data, err := P.Add(torsion).MarshalBinary()
+	require.NoError(t, err)
+	received := group.NewPoint()
+	require.NoError(t, received.UnmarshalBinary(data))
+	assert.True(t, received.Equal(P))
+
+	data, err = torsion.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, received.UnmarshalBinary(data))
+	assert.True(t, received.IsIdentity(), "a received torsion point should become the identity")
+}
+
+func TestSecp256k1_ClearCofactor(t *testing.T) {
+	group := curve.Secp256k1{}
+	assert.False(t, curve.HasCofactor(group))
+	P := group.NewBasePoint()
+	assert.True(t, curve.ClearCofactor(P).Equal(P))
+}
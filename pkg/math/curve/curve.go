@@ -2,8 +2,11 @@ package curve
 
 import (
 	"encoding"
+	"encoding/binary"
+	"fmt"
 
 	"github.com/cronokirby/saferith"
+	"github.com/zeebo/blake3"
 )
 
 // Curve represents the starting point for working with an Elliptic Curve group.
@@ -140,6 +143,22 @@ type Point interface {
 	XScalar() Scalar
 }
 
+// CheckCurve returns an error if one of the given Points or Scalars does not belong to group.
+//
+// It does not call IsOnCurve, since UnmarshalBinary already rejects points which are not on the curve,
+// once when they are decoded. Nil elements are ignored, and should be checked separately.
+func CheckCurve(group Curve, elements ...interface{ Curve() Curve }) error {
+	for _, e := range elements {
+		if e == nil {
+			continue
+		}
+		if name := e.Curve().Name(); name != group.Name() {
+			return fmt.Errorf("curve: expected an element of %s, got %s", group.Name(), name)
+		}
+	}
+	return nil
+}
+
 // MakeInt converts a scalar into an Int.
 func MakeInt(s Scalar) *saferith.Int {
 	bytes, err := s.MarshalBinary()
@@ -173,3 +192,28 @@ func FromHash(group Curve, h []byte) Scalar {
 	}
 	return group.NewScalar().SetNat(s)
 }
+
+// HashToScalar hashes domain and data to a Scalar of group, using BLAKE3 in XOF mode.
+//
+// The domain and each element of data are prefixed by their length, so that different inputs
+// can't produce the same encoding. Twice as many bytes as the order are read from the hash and reduced
+// modulo the order, so that the bias of the reduction is negligible (64 bytes for a 256 bit order).
+func HashToScalar(group Curve, domain string, data ...[]byte) Scalar {
+	h := blake3.New()
+	_, _ = h.WriteString("CMP-HashToScalar")
+	writeWithLength(h, []byte(group.Name()))
+	writeWithLength(h, []byte(domain))
+	for _, d := range data {
+		writeWithLength(h, d)
+	}
+	buffer := make([]byte, 2*((group.Order().BitLen()+7)/8))
+	_, _ = h.Digest().Read(buffer)
+	return group.NewScalar().SetNat(new(saferith.Nat).SetBytes(buffer))
+}
+
+func writeWithLength(h *blake3.Hasher, data []byte) {
+	var length [8]byte
+	binary.BigEndian.PutUint64(length[:], uint64(len(data)))
+	_, _ = h.Write(length[:])
+	_, _ = h.Write(data)
+}
@@ -0,0 +1,85 @@
+package curve_test
+
+import (
+	"crypto/rand"
+	"math"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+)
+
+// dudectThreshold is the value of Welch's t-statistic above which dudect
+// considers that an operation is definitely not constant time.
+const dudectThreshold = 10
+
+// dudect measures op on inputs of two classes, interleaved in a random order,
+// and returns Welch's t-statistic for the difference between the timings of both classes,
+// following "Dude, is my code constant time?" (Reparaz, Balasch, Verbauwhede).
+//
+// The slowest measurements are cropped, since they are mostly due to interruptions.
+func dudect(measurements int, prepare func(class int), op func(class int)) float64 {
+	const batch = 16
+	classes := make([]byte, measurements)
+	_, _ = rand.Read(classes)
+	timings := make([]float64, measurements)
+	for i := range timings {
+		class := int(classes[i] & 1)
+		prepare(class)
+		start := time.Now()
+		for j := 0; j < batch; j++ {
+			op(class)
+		}
+		timings[i] = float64(time.Since(start))
+	}
+
+	sorted := append([]float64(nil), timings...)
+	sort.Float64s(sorted)
+	crop := sorted[len(sorted)*9/10]
+
+	var n, mean, m2 [2]float64
+	for i, x := range timings {
+		if x > crop {
+			continue
+		}
+		c := classes[i] & 1
+		n[c]++
+		delta := x - mean[c]
+		mean[c] += delta / n[c]
+		m2[c] += delta * (x - mean[c])
+	}
+	v0, v1 := m2[0]/(n[0]-1), m2[1]/(n[1]-1)
+	return math.Abs(mean[0]-mean[1]) / math.Sqrt(v0/n[0]+v1/n[1])
+}
+
+// TestCondNegateConstantTime only runs with DUDECT=1, since timing measurements are slow,
+// and can fail spuriously on a loaded machine:
+//
+//	DUDECT=1 go test -run ConstantTime ./pkg/math/curve
+func TestCondNegateConstantTime(t *testing.T) {
+	if os.Getenv("DUDECT") != "1" {
+		t.Skip("set DUDECT=1 to run the timing measurements")
+	}
+	group := curve.Secp256k1{}
+
+	var s curve.Scalar
+	tScalar := dudect(100_000,
+		func(int) { s = sample.Scalar(rand.Reader, group) },
+		func(class int) { curve.CondNegateScalar(s, class) },
+	)
+	if tScalar > dudectThreshold {
+		t.Errorf("CondNegateScalar: timings depend on choose (t = %.2f)", tScalar)
+	}
+
+	var P curve.Point
+	tPoint := dudect(100_000,
+		func(int) { P = sample.Scalar(rand.Reader, group).ActOnBase() },
+		func(class int) { curve.CondNegatePoint(P, class) },
+	)
+	if tPoint > dudectThreshold {
+		t.Errorf("CondNegatePoint: timings depend on choose (t = %.2f)", tPoint)
+	}
+}
@@ -0,0 +1,12 @@
+package curve
+
+import "github.com/decred/dcrd/dcrec/secp256k1/v4"
+
+// NewSecp256k1PointUnchecked returns the point with affine coordinates (x, y), which may not be on the curve.
+func NewSecp256k1PointUnchecked(x, y *secp256k1.FieldVal) *Secp256k1Point {
+	p := new(Secp256k1Point)
+	p.value.X.Set(x)
+	p.value.Y.Set(y)
+	p.value.Z.SetInt(1)
+	return p
+}
@@ -0,0 +1,27 @@
+package curve
+
+// GobEncode implements gob.GobEncoder, using MarshalBinary.
+func (p *Secp256k1Point) GobEncode() ([]byte, error) {
+	return p.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder, using UnmarshalBinary.
+//
+// Since Point embeds encoding.BinaryMarshaler, encoding/gob encodes a field of type Point without the name of
+// its concrete type. As with UnmarshalBinary, such a field must be set to a point of the expected group,
+// for instance with Curve.NewPoint, before decoding into it.
+func (p *Secp256k1Point) GobDecode(data []byte) error {
+	return p.UnmarshalBinary(data)
+}
+
+// GobEncode implements gob.GobEncoder, using MarshalBinary.
+func (s *Secp256k1Scalar) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder, using UnmarshalBinary.
+//
+// As for points, a field of type Scalar must be set to a scalar of the expected group before decoding into it.
+func (s *Secp256k1Scalar) GobDecode(data []byte) error {
+	return s.UnmarshalBinary(data)
+}
@@ -0,0 +1,55 @@
+package curve_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/gob"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+)
+
+type gobPair struct {
+	Point  curve.Point
+	Scalar curve.Scalar
+}
+
+func TestGob(t *testing.T) {
+	group := curve.Secp256k1{}
+	x, X := sample.ScalarPointPair(rand.Reader, group)
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(gobPair{Point: X, Scalar: x}))
+	// like UnmarshalBinary, decoding requires elements of the expected group
+	decoded := gobPair{Point: group.NewPoint(), Scalar: group.NewScalar()}
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&decoded))
+	assert.True(t, decoded.Point.Equal(X))
+	assert.True(t, decoded.Scalar.Equal(x))
+
+	type concretePair struct {
+		Point  *curve.Secp256k1Point
+		Scalar *curve.Secp256k1Scalar
+	}
+	buf.Reset()
+	require.NoError(t, gob.NewEncoder(&buf).Encode(concretePair{
+		Point:  X.(*curve.Secp256k1Point),
+		Scalar: x.(*curve.Secp256k1Scalar),
+	}))
+	var decodedConcrete concretePair
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&decodedConcrete))
+	assert.True(t, decodedConcrete.Point.Equal(X))
+	assert.True(t, decodedConcrete.Scalar.Equal(x))
+
+	// invalid points are rejected by UnmarshalBinary
+	buf.Reset()
+	require.NoError(t, gob.NewEncoder(&buf).Encode(struct{ Point rawGob }{Point: rawGob{0x02, 0xff}}))
+	assert.Error(t, gob.NewDecoder(&buf).Decode(&decodedConcrete))
+}
+
+// rawGob is encoded by gob as is, like the GobEncode methods of the curve types.
+type rawGob []byte
+
+func (r rawGob) GobEncode() ([]byte, error) { return r, nil }
@@ -0,0 +1,95 @@
+package curve
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// JSONEncoding describes how a JSONPoint is represented as a JSON string.
+//
+// The zero value is the compressed encoding of MarshalBinary, as a base64 string,
+// which is also how encoding/json represents a []byte.
+type JSONEncoding struct {
+	// Hex uses a hex string instead of base64.
+	Hex bool
+	// Uncompressed uses the 65 byte encoding 0x04 ‖ x ‖ y instead of MarshalBinary.
+	// This is only supported by points which implement MarshalUncompressed and UnmarshalUncompressed,
+	// such as Secp256k1Point.
+	Uncompressed bool
+}
+
+// uncompressedPoint is implemented by points which support an uncompressed encoding.
+type uncompressedPoint interface {
+	MarshalUncompressed() ([]byte, error)
+	UnmarshalUncompressed([]byte) error
+}
+
+// JSONPoint wraps a Point to encode it as a JSON string, following Encoding.
+//
+// To unmarshal a JSONPoint, Point must first be set to a point of the expected group,
+// for instance with Curve.NewPoint.
+type JSONPoint struct {
+	Point    Point
+	Encoding JSONEncoding
+}
+
+// MarshalJSON implements json.Marshaler.
+func (p JSONPoint) MarshalJSON() ([]byte, error) {
+	if p.Point == nil {
+		return nil, errors.New("curve: nil point")
+	}
+	var (
+		data []byte
+		err  error
+	)
+	if p.Encoding.Uncompressed {
+		u, ok := p.Point.(uncompressedPoint)
+		if !ok {
+			return nil, fmt.Errorf("curve: %s does not support uncompressed points", p.Point.Curve().Name())
+		}
+		data, err = u.MarshalUncompressed()
+	} else {
+		data, err = p.Point.MarshalBinary()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.Encoding.Hex {
+		return json.Marshal(hex.EncodeToString(data))
+	}
+	return json.Marshal(base64.StdEncoding.EncodeToString(data))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (p *JSONPoint) UnmarshalJSON(b []byte) error {
+	if p.Point == nil {
+		return errors.New("curve: JSONPoint must be initialized with a point of the expected group")
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	var (
+		data []byte
+		err  error
+	)
+	if p.Encoding.Hex {
+		data, err = hex.DecodeString(s)
+	} else {
+		data, err = base64.StdEncoding.DecodeString(s)
+	}
+	if err != nil {
+		return fmt.Errorf("curve: %w", err)
+	}
+	if p.Encoding.Uncompressed {
+		u, ok := p.Point.(uncompressedPoint)
+		if !ok {
+			return fmt.Errorf("curve: %s does not support uncompressed points", p.Point.Curve().Name())
+		}
+		return u.UnmarshalUncompressed(data)
+	}
+	return p.Point.UnmarshalBinary(data)
+}
@@ -0,0 +1,65 @@
+package curve_test
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+)
+
+func TestJSONPoint(t *testing.T) {
+	group := curve.Secp256k1{}
+	point := sample.Scalar(rand.Reader, group).ActOnBase()
+	compressed, err := point.MarshalBinary()
+	require.NoError(t, err)
+
+	encodings := []struct {
+		encoding curve.JSONEncoding
+		length   int
+	}{
+		{curve.JSONEncoding{}, 33},
+		{curve.JSONEncoding{Hex: true}, 33},
+		{curve.JSONEncoding{Uncompressed: true}, 65},
+		{curve.JSONEncoding{Hex: true, Uncompressed: true}, 65},
+	}
+	for _, e := range encodings {
+		data, err := json.Marshal(curve.JSONPoint{Point: point, Encoding: e.encoding})
+		require.NoError(t, err)
+
+		var s string
+		require.NoError(t, json.Unmarshal(data, &s))
+		if e.encoding.Hex {
+			raw, err := hex.DecodeString(s)
+			require.NoError(t, err)
+			assert.Len(t, raw, e.length)
+			if !e.encoding.Uncompressed {
+				assert.Equal(t, hex.EncodeToString(compressed), s)
+			}
+		}
+
+		decoded := curve.JSONPoint{Point: group.NewPoint(), Encoding: e.encoding}
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.True(t, point.Equal(decoded.Point), "point should round trip with %+v", e.encoding)
+	}
+
+	// the default matches the encoding of the compressed bytes by encoding/json
+	data, err := json.Marshal(curve.JSONPoint{Point: point})
+	require.NoError(t, err)
+	expected, err := json.Marshal(compressed)
+	require.NoError(t, err)
+	assert.Equal(t, expected, data)
+
+	// the identity has no uncompressed encoding
+	_, err = json.Marshal(curve.JSONPoint{Point: group.NewPoint(), Encoding: curve.JSONEncoding{Uncompressed: true}})
+	assert.Error(t, err)
+
+	// a point which is not on the curve is rejected
+	bad := hex.EncodeToString(append([]byte{4}, make([]byte, 64)...))
+	decoded := curve.JSONPoint{Point: group.NewPoint(), Encoding: curve.JSONEncoding{Hex: true, Uncompressed: true}}
+	assert.Error(t, json.Unmarshal([]byte(`"`+bad+`"`), &decoded))
+}
@@ -0,0 +1,169 @@
+package curve
+
+import (
+	"crypto/subtle"
+
+	"github.com/cronokirby/saferith"
+)
+
+// The functions in this file extend the Curve, Scalar and Point interfaces without growing them,
+// so that existing implementations keep satisfying them.
+//
+// A curve can provide a faster or more specific version of an operation by implementing the matching
+// optional method, which is detected with a type assertion. Otherwise, a generic fallback built from
+// the methods of the interfaces is used.
+
+// Identity returns a new identity point of group.
+//
+// Each call returns a fresh value, so the result can be modified freely.
+func Identity(group Curve) Point {
+	return group.NewPoint()
+}
+
+// Generator returns a new copy of the generator of group.
+func Generator(group Curve) Point {
+	return group.NewBasePoint()
+}
+
+// ScalarZero returns a new scalar of group with the value of 0.
+func ScalarZero(group Curve) Scalar {
+	return group.NewScalar()
+}
+
+// ScalarOne returns a new scalar of group with the value of 1.
+func ScalarOne(group Curve) Scalar {
+	return group.NewScalar().SetNat(new(saferith.Nat).SetUint64(1))
+}
+
+// HasCofactor returns true if the group of points on the curve is larger than the prime-order group of its scalars.
+//
+// This calls the optional method HasCofactor() bool of group, and returns false if it is missing.
+//
+// The protocols assume that all points belong to the prime-order subgroup, but never clear cofactors themselves.
+// An implementation of a curve with a cofactor must therefore implement it, along with ClearCofactor,
+// and clear the cofactor in its UnmarshalBinary, so that a received torsion point becomes the identity,
+// which the rounds reject. secp256k1 has cofactor 1, so its points are decoded as is.
+func HasCofactor(group Curve) bool {
+	if c, ok := group.(interface{ HasCofactor() bool }); ok {
+		return c.HasCofactor()
+	}
+	return false
+}
+
+// ClearCofactor returns the projection of p onto the prime-order subgroup.
+//
+// Points of the subgroup are unchanged, and points of small order become the identity.
+// This calls the optional method ClearCofactor() Point of p. If it is missing,
+// the curve is assumed to have no cofactor, and p itself is returned.
+func ClearCofactor(p Point) Point {
+	if c, ok := p.(interface{ ClearCofactor() Point }); ok {
+		return c.ClearCofactor()
+	}
+	return p
+}
+
+// IsOnCurve checks if p satisfies the equation of its curve.
+//
+// This holds for every point obtained through UnmarshalBinary, or from the methods of Curve and Point,
+// but not necessarily for points constructed from external coordinates. The identity is on the curve.
+//
+// This calls the optional method IsOnCurve() bool of p. If it is missing, p is encoded and decoded again,
+// which only succeeds, and gives back p, if it is on the curve.
+func IsOnCurve(p Point) bool {
+	if c, ok := p.(interface{ IsOnCurve() bool }); ok {
+		return c.IsOnCurve()
+	}
+	data, err := p.MarshalBinary()
+	if err != nil {
+		return false
+	}
+	decoded := p.Curve().NewPoint()
+	if err = decoded.UnmarshalBinary(data); err != nil {
+		return false
+	}
+	return decoded.Equal(p)
+}
+
+// MessageToScalar converts the hash of a message to the Scalar of group used by ECDSA.
+//
+// This is the conversion used by all signing protocols. It calls the optional method
+// MessageToScalar(hash []byte) Scalar of group, and otherwise truncates the hash to the bit-length of the order,
+// and reduces it modulo the order, see FromHash.
+func MessageToScalar(group Curve, hash []byte) Scalar {
+	if c, ok := group.(interface{ MessageToScalar([]byte) Scalar }); ok {
+		return c.MessageToScalar(hash)
+	}
+	return FromHash(group, hash)
+}
+
+// DoubleScalar mutates s, replacing it with 2⋅s, and returns s.
+//
+// This calls the optional method Double() Scalar of s, and is otherwise equivalent to s.Add(s).
+func DoubleScalar(s Scalar) Scalar {
+	if d, ok := s.(interface{ Double() Scalar }); ok {
+		return d.Double()
+	}
+	return s.Add(s)
+}
+
+// DoublePoint returns 2⋅p, which is the identity if p is the identity, without mutating p.
+//
+// This calls the optional method Double() Point of p, and is otherwise equivalent to p.Add(p).
+func DoublePoint(p Point) Point {
+	if d, ok := p.(interface{ Double() Point }); ok {
+		return d.Double()
+	}
+	return p.Add(p)
+}
+
+// CondNegateScalar mutates s, replacing it with its negation if choose is 1,
+// and leaving it unchanged if choose is 0. It returns s.
+//
+// choose must be 0 or 1. This is done in constant time, so that neither choose
+// nor the value of s can be recovered from the running time, as long as the optional method
+// CondNegate(choose int) Scalar of s, or else its Negate and binary encoding, run in constant time.
+func CondNegateScalar(s Scalar, choose int) Scalar {
+	if c, ok := s.(interface{ CondNegate(int) Scalar }); ok {
+		return c.CondNegate(choose)
+	}
+	negated := s.Curve().NewScalar().Set(s).Negate()
+	value, err := s.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	negatedValue, err := negated.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	subtle.ConstantTimeCopy(choose, value, negatedValue)
+	if err = s.UnmarshalBinary(value); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// CondNegatePoint returns the negation of p if choose is 1, and a copy of p if choose is 0, without mutating p.
+//
+// choose must be 0 or 1. As for CondNegateScalar, this is done in constant time,
+// and is used for instance to normalize a point to an even y coordinate.
+// It calls the optional method CondNegate(choose int) Point of p, and otherwise selects between
+// the binary encodings of p and its negation.
+func CondNegatePoint(p Point, choose int) Point {
+	if c, ok := p.(interface{ CondNegate(int) Point }); ok {
+		return c.CondNegate(choose)
+	}
+	value, err := p.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	negatedValue, err := p.Negate().MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	subtle.ConstantTimeCopy(choose, value, negatedValue)
+	out := p.Curve().NewPoint()
+	if err = out.UnmarshalBinary(value); err != nil {
+		panic(err)
+	}
+	return out
+}
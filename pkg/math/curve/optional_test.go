@@ -0,0 +1,59 @@
+package curve_test
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// toyCurve only implements HasCofactor and ClearCofactor, so the other functions use their generic fallback.
+func TestOptionalFallbacks(t *testing.T) {
+	group := toyCurve{}
+	one := &toyScalar{v: 1}
+	assert.True(t, curve.Identity(group).IsIdentity())
+	assert.True(t, curve.Generator(group).Equal(one.ActOnBase()))
+	assert.True(t, curve.ScalarZero(group).IsZero())
+	assert.True(t, curve.ScalarOne(group).Equal(one))
+
+	for _, v := range []uint64{0, 1, 123, toyOrder - 1} {
+		s := &toyScalar{v: v}
+		P := s.ActOnBase()
+
+		assert.True(t, curve.DoubleScalar(&toyScalar{v: v}).Equal(s.Add(&toyScalar{v: v})))
+		assert.True(t, curve.DoublePoint(P).Equal(P.Add(P)))
+		assert.True(t, curve.IsOnCurve(P))
+
+		assert.True(t, curve.CondNegateScalar(&toyScalar{v: v}, 0).Equal(&toyScalar{v: v}))
+		assert.True(t, curve.CondNegateScalar(&toyScalar{v: v}, 1).Equal((&toyScalar{v: v}).Negate()))
+		assert.True(t, curve.CondNegatePoint(P, 0).Equal(P))
+		assert.True(t, curve.CondNegatePoint(P, 1).Equal(P.Negate()))
+	}
+
+	// the torsion component is removed when decoding, so the fallback of IsOnCurve rejects points outside the subgroup
+	assert.False(t, curve.IsOnCurve(&toyPoint{v: toyOrder}))
+
+	digest := sha256.Sum256([]byte("hello"))
+	assert.True(t, curve.MessageToScalar(group, digest[:]).Equal(curve.FromHash(group, digest[:])))
+}
+
+// secp256k1 provides its own version of the optional methods, instead of the generic fallbacks.
+func TestSecp256k1_OptionalMethods(t *testing.T) {
+	group := curve.Secp256k1{}
+	P := group.NewBasePoint()
+	s := curve.ScalarOne(group)
+
+	require.Implements(t, (*interface{ HasCofactor() bool })(nil), group)
+	require.Implements(t, (*interface{ CondNegate(int) curve.Scalar })(nil), s)
+	require.Implements(t, (*interface{ Double() curve.Scalar })(nil), s)
+	for _, method := range []interface{}{
+		(*interface{ CondNegate(int) curve.Point })(nil),
+		(*interface{ Double() curve.Point })(nil),
+		(*interface{ ClearCofactor() curve.Point })(nil),
+		(*interface{ IsOnCurve() bool })(nil),
+	} {
+		require.Implements(t, method, P)
+	}
+}
@@ -1,6 +1,7 @@
 package curve
 
 import (
+	"crypto/subtle"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -18,6 +19,11 @@ func init() {
 	secp256k1BaseY.SetByteSlice(Gy)
 }
 
+// Secp256k1 is the curve used by Bitcoin and Ethereum.
+//
+// The group of points has prime order, so every point other than the identity generates the whole group,
+// and there are no small subgroups. Since UnmarshalBinary also checks that points are on the curve,
+// rounds receiving points only need to reject the identity.
 type Secp256k1 struct{}
 
 func (Secp256k1) NewPoint() Point {
@@ -36,6 +42,13 @@ func (Secp256k1) NewScalar() Scalar {
 	return new(Secp256k1Scalar)
 }
 
+// HasCofactor is the optional method used by the HasCofactor function.
+//
+// The group of points of secp256k1 has prime order.
+func (Secp256k1) HasCofactor() bool {
+	return false
+}
+
 func (Secp256k1) ScalarBits() int {
 	return 256
 }
@@ -88,6 +101,12 @@ func (s *Secp256k1Scalar) MarshalBinary() ([]byte, error) {
 	return data[:], nil
 }
 
+// Bytes returns the same big-endian encoding as MarshalBinary, left-padded with zeros to 32 bytes,
+// as a fixed size array.
+func (s *Secp256k1Scalar) Bytes() [32]byte {
+	return s.value.Bytes()
+}
+
 func (s *Secp256k1Scalar) UnmarshalBinary(data []byte) error {
 	if len(data) != 32 {
 		return fmt.Errorf("invalid length for secp256k1 scalar: %d", len(data))
@@ -135,6 +154,23 @@ func (s *Secp256k1Scalar) Negate() Scalar {
 	return s
 }
 
+// CondNegate is the optional method used by CondNegateScalar.
+//
+// Both values are always computed, and the result is selected with subtle.ConstantTimeCopy.
+func (s *Secp256k1Scalar) CondNegate(choose int) Scalar {
+	var negated secp256k1.ModNScalar
+	negated.NegateVal(&s.value)
+	value, negatedValue := s.value.Bytes(), negated.Bytes()
+	subtle.ConstantTimeCopy(choose, value[:], negatedValue[:])
+	s.value.SetBytes(&value)
+	return s
+}
+
+func (s *Secp256k1Scalar) Double() Scalar {
+	s.value.Add(&s.value)
+	return s
+}
+
 func (s *Secp256k1Scalar) IsOverHalfOrder() bool {
 	return s.value.IsOverHalfOrder()
 }
@@ -162,6 +198,11 @@ func (s *Secp256k1Scalar) SetNat(x *saferith.Nat) Scalar {
 	return s
 }
 
+// Act implements Scalar.
+//
+// The multiplication uses the GLV endomorphism of secp256k1, as implemented by secp256k1.ScalarMultNonConst.
+// This variable-time multiplication is the only one provided by the secp256k1 package,
+// so there is no faster variant for public scalars, such as Lagrange coefficients.
 func (s *Secp256k1Scalar) Act(that Point) Point {
 	other := secp256k1CastPoint(that)
 	out := new(Secp256k1Point)
@@ -169,6 +210,9 @@ func (s *Secp256k1Scalar) Act(that Point) Point {
 	return out
 }
 
+// ActOnBase implements Scalar.
+//
+// Like Act, it runs in variable time, using a table of precomputed multiples of the base point.
 func (s *Secp256k1Scalar) ActOnBase() Point {
 	out := new(Secp256k1Point)
 	secp256k1.ScalarBaseMultNonConst(&s.value, &out.value)
@@ -192,20 +236,33 @@ func (*Secp256k1Point) Curve() Curve {
 }
 
 func (p *Secp256k1Point) XBytes() []byte {
-	p.value.ToAffine()
-	return p.value.X.Bytes()[:]
+	v := p.affine()
+	return v.X.Bytes()[:]
 }
 
-func (p *Secp256k1Point) MarshalBinary() ([]byte, error) {
-	out := make([]byte, 33)
-	// we clone v to not case a race during a hash.Write
+// affine returns a copy of p in affine coordinates.
+//
+// Normalizing p itself would be a data race when it is read concurrently, for instance from a shared Config,
+// so the methods of Secp256k1Point never modify their receiver or argument, except for Set and UnmarshalBinary.
+func (p *Secp256k1Point) affine() secp256k1.JacobianPoint {
 	v := p.value
 	v.ToAffine()
+	return v
+}
+
+func (p *Secp256k1Point) MarshalBinary() ([]byte, error) {
+	out := p.Bytes()
+	return out[:], nil
+}
+
+// Bytes returns the same compressed encoding as MarshalBinary, as a fixed size array.
+func (p *Secp256k1Point) Bytes() [33]byte {
+	var out [33]byte
+	v := p.affine()
 	// Doing it this way is compatible with Bitcoin
 	out[0] = byte(v.Y.IsOddBit()) + 2
-	data := v.X.Bytes()
-	copy(out[1:], data[:])
-	return out, nil
+	v.X.PutBytesUnchecked(out[1:])
+	return out
 }
 
 func (p *Secp256k1Point) UnmarshalBinary(data []byte) error {
@@ -222,6 +279,34 @@ func (p *Secp256k1Point) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// MarshalUncompressed encodes p as 0x04 ‖ x ‖ y, as in SEC 1.
+//
+// The identity has no such encoding, and returns an error.
+func (p *Secp256k1Point) MarshalUncompressed() ([]byte, error) {
+	if p.IsIdentity() {
+		return nil, errors.New("secp256k1Point.MarshalUncompressed: identity has no uncompressed encoding")
+	}
+	v := p.affine()
+	out := make([]byte, 65)
+	out[0] = 4
+	v.X.PutBytesUnchecked(out[1:33])
+	v.Y.PutBytesUnchecked(out[33:])
+	return out, nil
+}
+
+// UnmarshalUncompressed decodes a point encoded by MarshalUncompressed, checking that it is on the curve.
+func (p *Secp256k1Point) UnmarshalUncompressed(data []byte) error {
+	if len(data) != 65 || data[0] != 4 {
+		return fmt.Errorf("invalid uncompressed secp256k1Point of length %d", len(data))
+	}
+	pk, err := secp256k1.ParsePubKey(data)
+	if err != nil {
+		return fmt.Errorf("secp256k1Point.UnmarshalUncompressed: %w", err)
+	}
+	pk.AsJacobian(&p.value)
+	return nil
+}
+
 func (p *Secp256k1Point) Add(that Point) Point {
 	other := secp256k1CastPoint(that)
 
@@ -241,6 +326,14 @@ func (p *Secp256k1Point) Set(that Point) Point {
 	return p
 }
 
+// ScalarBaseMultVartime sets p to s⋅G, and returns p.
+//
+// This runs in variable time, and must only be used when s is public, such as a Lagrange coefficient.
+// It computes the same point as s.ActOnBase().
+func (p *Secp256k1Point) ScalarBaseMultVartime(s Scalar) Point {
+	return p.Set(s.ActOnBase())
+}
+
 func (p *Secp256k1Point) Negate() Point {
 	out := new(Secp256k1Point)
 	out.value.Set(&p.value)
@@ -249,26 +342,64 @@ func (p *Secp256k1Point) Negate() Point {
 	return out
 }
 
+// CondNegate is the optional method used by CondNegatePoint.
+//
+// Like Negate, it only changes the y coordinate, which is selected with subtle.ConstantTimeCopy.
+func (p *Secp256k1Point) CondNegate(choose int) Point {
+	out := new(Secp256k1Point)
+	out.value.Set(&p.value)
+	out.value.Y.Normalize()
+	var negated secp256k1.FieldVal
+	negated.NegateVal(&out.value.Y, 1).Normalize()
+	y, negatedY := out.value.Y.Bytes(), negated.Bytes()
+	subtle.ConstantTimeCopy(choose, y[:], negatedY[:])
+	out.value.Y.SetBytes(y)
+	return out
+}
+
+func (p *Secp256k1Point) Double() Point {
+	out := new(Secp256k1Point)
+	secp256k1.DoubleNonConst(&p.value, &out.value)
+	return out
+}
+
 func (p *Secp256k1Point) Equal(that Point) bool {
 	other := secp256k1CastPoint(that)
 
-	p.value.ToAffine()
-	other.value.ToAffine()
-	return p.value.X.Equals(&other.value.X) && p.value.Y.Equals(&other.value.Y) && p.value.Z.Equals(&other.value.Z)
+	v, w := p.affine(), other.affine()
+	return v.X.Equals(&w.X) && v.Y.Equals(&w.Y) && v.Z.Equals(&w.Z)
 }
 
 func (p *Secp256k1Point) IsIdentity() bool {
 	return p == nil || (p.value.X.IsZero() && p.value.Y.IsZero()) || p.value.Z.IsZero()
 }
 
+// HasEvenY returns true if the affine y coordinate of p is even, as required of public keys and nonces in BIP340.
+//
+// p is stored in Jacobian coordinates (X, Y, Z), with y = Y/Z³, and the parity of Y differs from that of y,
+// so p is converted to affine coordinates first.
 func (p *Secp256k1Point) HasEvenY() bool {
-	p.value.ToAffine()
-	return !p.value.Y.IsOdd()
+	v := p.affine()
+	return !v.Y.IsOdd()
+}
+
+// IsOnCurve is the optional method used by the IsOnCurve function, and checks that y² = x³ + 7 for the affine coordinates of p.
+func (p *Secp256k1Point) IsOnCurve() bool {
+	if p.IsIdentity() {
+		return true
+	}
+	v := p.affine()
+	return secp256k1.NewPublicKey(&v.X, &v.Y).IsOnCurve()
+}
+
+// ClearCofactor is the optional method used by the ClearCofactor function, and returns p, since secp256k1 has cofactor 1.
+func (p *Secp256k1Point) ClearCofactor() Point {
+	return p
 }
 
 func (p *Secp256k1Point) XScalar() Scalar {
 	out := new(Secp256k1Scalar)
-	p.value.ToAffine()
-	out.value.SetBytes(p.value.X.Bytes())
+	v := p.affine()
+	out.value.SetBytes(v.X.Bytes())
 	return out
 }
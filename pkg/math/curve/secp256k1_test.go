@@ -0,0 +1,315 @@
+package curve_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cronokirby/saferith"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/zeebo/blake3"
+)
+
+// actDoubleAndAdd computes s⋅P using a plain double-and-add, without the endomorphism.
+func actDoubleAndAdd(s curve.Scalar, P curve.Point) curve.Point {
+	data, err := s.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	out := P.Curve().NewPoint()
+	for _, b := range data {
+		for i := 7; i >= 0; i-- {
+			out = curve.DoublePoint(out)
+			if (b>>i)&1 == 1 {
+				out = out.Add(P)
+			}
+		}
+	}
+	return out
+}
+
+func TestSecp256k1Scalar_Act(t *testing.T) {
+	group := curve.Secp256k1{}
+	for i := 0; i < 64; i++ {
+		s := sample.Scalar(rand.Reader, group)
+		P := sample.Scalar(rand.Reader, group).ActOnBase()
+		if !s.Act(P).Equal(actDoubleAndAdd(s, P)) {
+			t.Fatal("Act and double-and-add should give the same result")
+		}
+		if !s.ActOnBase().Equal(actDoubleAndAdd(s, group.NewBasePoint())) {
+			t.Fatal("ActOnBase and double-and-add should give the same result")
+		}
+		if !new(curve.Secp256k1Point).ScalarBaseMultVartime(s).Equal(s.ActOnBase()) {
+			t.Fatal("ScalarBaseMultVartime and ActOnBase should give the same result")
+		}
+	}
+}
+
+func BenchmarkSecp256k1Scalar_Act(b *testing.B) {
+	group := curve.Secp256k1{}
+	s := sample.Scalar(rand.Reader, group)
+	P := sample.Scalar(rand.Reader, group).ActOnBase()
+
+	b.Run("endomorphism", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			s.Act(P)
+		}
+	})
+	b.Run("double-and-add", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			actDoubleAndAdd(s, P)
+		}
+	})
+}
+
+func TestSecp256k1Scalar_Bytes(t *testing.T) {
+	group := curve.Secp256k1{}
+	for _, s := range []curve.Scalar{
+		group.NewScalar(),
+		group.NewScalar().SetNat(new(saferith.Nat).SetUint64(1)),
+		sample.Scalar(rand.Reader, group),
+	} {
+		data, err := s.MarshalBinary()
+		require.NoError(t, err)
+		b := s.(*curve.Secp256k1Scalar).Bytes()
+		assert.Equal(t, data, b[:])
+	}
+
+	// a small scalar is left-padded, not truncated
+	b := group.NewScalar().SetNat(new(saferith.Nat).SetUint64(0x0102)).(*curve.Secp256k1Scalar).Bytes()
+	expected := [32]byte{30: 0x01, 31: 0x02}
+	assert.Equal(t, expected, b)
+}
+
+func TestSecp256k1Point_Bytes(t *testing.T) {
+	group := curve.Secp256k1{}
+	for i := 0; i < 16; i++ {
+		p := sample.Scalar(rand.Reader, group).ActOnBase()
+		data, err := p.MarshalBinary()
+		require.NoError(t, err)
+		b := p.(*curve.Secp256k1Point).Bytes()
+		assert.Equal(t, data, b[:])
+	}
+}
+
+func TestSecp256k1Point_IsOnCurve(t *testing.T) {
+	group := curve.Secp256k1{}
+	assert.True(t, curve.IsOnCurve(group.NewPoint()), "the identity should be on the curve")
+	for i := 0; i < 16; i++ {
+		p := sample.Scalar(rand.Reader, group).ActOnBase()
+		assert.True(t, curve.IsOnCurve(p))
+		assert.True(t, curve.IsOnCurve(p.Add(group.NewBasePoint())))
+		assert.NoError(t, curve.CheckCurve(group, p))
+	}
+
+	// (x, y + 1) is not on the curve, since the only points with abscissa x are (x, y) and (x, -y)
+	var x, y secp256k1.FieldVal
+	data := group.NewBasePoint().(*curve.Secp256k1Point).XBytes()
+	x.SetByteSlice(data)
+	secp256k1.DecompressY(&x, false, &y)
+	y.AddInt(1).Normalize()
+	offCurve := curve.NewSecp256k1PointUnchecked(&x, &y)
+	assert.False(t, curve.IsOnCurve(offCurve))
+
+	// such points are rejected when they are decoded
+	uncompressed := append([]byte{4}, data...)
+	uncompressed = append(uncompressed, y.Bytes()[:]...)
+	assert.Error(t, new(curve.Secp256k1Point).UnmarshalUncompressed(uncompressed))
+	// x³ + 7 is not a square for x = 5, so no point has abscissa 5
+	compressed := make([]byte, 33)
+	compressed[0], compressed[32] = 2, 5
+	assert.Error(t, new(curve.Secp256k1Point).UnmarshalBinary(compressed))
+}
+
+func TestSecp256k1_Double(t *testing.T) {
+	group := curve.Secp256k1{}
+	assert.True(t, curve.DoublePoint(group.NewPoint()).IsIdentity(), "the double of the identity should be the identity")
+	assert.True(t, curve.DoubleScalar(group.NewScalar()).IsZero())
+
+	order := group.Order().Nat()
+	minusOne := group.NewScalar().SetNat(new(saferith.Nat).Sub(order, new(saferith.Nat).SetUint64(1), -1))
+	minusTwo := group.NewScalar().SetNat(new(saferith.Nat).Sub(order, new(saferith.Nat).SetUint64(2), -1))
+	assert.True(t, curve.DoubleScalar(minusOne).Equal(minusTwo), "doubling should reduce modulo the order")
+
+	for i := 0; i < 16; i++ {
+		s := sample.Scalar(rand.Reader, group)
+		expected := group.NewScalar().Set(s).Add(s)
+		assert.True(t, curve.DoubleScalar(group.NewScalar().Set(s)).Equal(expected), "DoubleScalar(s) should equal s + s")
+
+		P := s.ActOnBase()
+		before, err := P.MarshalBinary()
+		require.NoError(t, err)
+		assert.True(t, curve.DoublePoint(P).Equal(P.Add(P)), "DoublePoint(P) should equal P + P")
+		assert.True(t, curve.DoublePoint(P).Equal(expected.ActOnBase()), "DoublePoint(P) should equal (2s)⋅G")
+		after, err := P.MarshalBinary()
+		require.NoError(t, err)
+		assert.Equal(t, before, after, "DoublePoint(P) should not mutate P")
+	}
+}
+
+func TestSecp256k1_MessageToScalar(t *testing.T) {
+	group := curve.Secp256k1{}
+	digest := sha256.Sum256([]byte("hello"))
+	long := sha512.Sum512([]byte("hello"))
+	allOnes := bytes.Repeat([]byte{0xff}, 32)
+	for name, hash := range map[string][]byte{
+		"sha256":   digest[:],
+		"sha512":   long[:],
+		"short":    digest[:20],
+		"overflow": allOnes,
+		"empty":    {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// the reference implementation used by secp256k1.Sign
+			var expected secp256k1.ModNScalar
+			expected.SetByteSlice(hash)
+			s := curve.MessageToScalar(group, hash).(*curve.Secp256k1Scalar)
+			assert.Equal(t, expected.Bytes(), s.Bytes())
+		})
+	}
+
+	// 2²⁵⁶-1 mod n
+	expected, _ := hex.DecodeString("000000000000000000000000000000014551231950b75fc4402da1732fc9bebe")
+	s := curve.MessageToScalar(group, allOnes).(*curve.Secp256k1Scalar).Bytes()
+	assert.Equal(t, expected, s[:])
+}
+
+func TestHashToScalar(t *testing.T) {
+	group := curve.Secp256k1{}
+	s := curve.HashToScalar(group, "domain", []byte("a"), []byte("b"))
+	assert.True(t, s.Equal(curve.HashToScalar(group, "domain", []byte("a"), []byte("b"))), "hash should be deterministic")
+	for name, other := range map[string]curve.Scalar{
+		"domain":        curve.HashToScalar(group, "other", []byte("a"), []byte("b")),
+		"concatenation": curve.HashToScalar(group, "domain", []byte("ab")),
+		"split":         curve.HashToScalar(group, "domain", []byte("a"), []byte{}, []byte("b")),
+		"domain prefix": curve.HashToScalar(group, "domaina", []byte("b")),
+	} {
+		assert.False(t, other.Equal(s), "%s should change the scalar", name)
+	}
+
+	// 64 bytes are read from the hash, and reduced modulo the order
+	h := blake3.New()
+	_, _ = h.WriteString("CMP-HashToScalar")
+	for _, d := range [][]byte{[]byte(group.Name()), []byte("domain"), []byte("a"), []byte("b")} {
+		_, _ = h.Write(binary.BigEndian.AppendUint64(nil, uint64(len(d))))
+		_, _ = h.Write(d)
+	}
+	wide := make([]byte, 64)
+	_, _ = h.Digest().Read(wide)
+	assert.True(t, s.Equal(group.NewScalar().SetNat(new(saferith.Nat).SetBytes(wide))), "the hash should be reduced from 64 bytes")
+
+	// A χ² test on a toy curve of order 1019, where reducing a single byte, or the 10 bits of the order, would be visibly biased.
+	// The inputs are fixed, so that the test is deterministic.
+	const perValue = 50
+	toy := toyCurve{}
+	counts := make(map[string]int, toyOrder)
+	for i := 0; i < toyOrder*perValue; i++ {
+		data, _ := curve.HashToScalar(toy, "bias", binary.BigEndian.AppendUint32(nil, uint32(i))).MarshalBinary()
+		counts[string(data)]++
+	}
+	var chi2 float64
+	for _, c := range counts {
+		d := float64(c - perValue)
+		chi2 += d * d / perValue
+	}
+	chi2 += float64((toyOrder - len(counts)) * perValue)
+	// with 1018 degrees of freedom, χ² has mean 1018 and standard deviation 45
+	assert.Less(t, chi2, 1018+6*45.0, "scalars should be close to uniform")
+}
+
+func BenchmarkSecp256k1Scalar_ActOnBase(b *testing.B) {
+	group := curve.Secp256k1{}
+	s := sample.Scalar(rand.Reader, group)
+	G := group.NewBasePoint()
+
+	b.Run("precomputed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			s.ActOnBase()
+		}
+	})
+	b.Run("act", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			s.Act(G)
+		}
+	})
+	b.Run("vartime", func(b *testing.B) {
+		p := new(curve.Secp256k1Point)
+		for i := 0; i < b.N; i++ {
+			p.ScalarBaseMultVartime(s)
+		}
+	})
+}
+
+func TestSecp256k1_Constants(t *testing.T) {
+	group := curve.Secp256k1{}
+	one := group.NewScalar().SetNat(new(saferith.Nat).SetUint64(1))
+
+	assert.True(t, curve.Identity(group).IsIdentity())
+	assert.True(t, curve.Generator(group).Equal(one.ActOnBase()))
+	assert.True(t, curve.ScalarZero(group).IsZero())
+	assert.True(t, curve.ScalarOne(group).Equal(one))
+
+	// mutating the returned values must not affect later calls
+	curve.ScalarZero(group).Add(one)
+	curve.ScalarOne(group).Add(one)
+	assert.True(t, curve.ScalarZero(group).IsZero())
+	assert.True(t, curve.ScalarOne(group).Equal(one))
+
+	other := sample.Scalar(rand.Reader, group).ActOnBase()
+	data, err := other.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, curve.Identity(group).UnmarshalBinary(data))
+	require.NoError(t, curve.Generator(group).UnmarshalBinary(data))
+	assert.True(t, curve.Identity(group).IsIdentity())
+	assert.True(t, curve.Generator(group).Equal(one.ActOnBase()))
+}
+
+func TestSecp256k1_CondNegate(t *testing.T) {
+	group := curve.Secp256k1{}
+	for i := 0; i < 32; i++ {
+		s := sample.Scalar(rand.Reader, group)
+		P := s.ActOnBase().Add(group.NewBasePoint())
+
+		assert.True(t, curve.CondNegateScalar(group.NewScalar().Set(s), 0).Equal(s), "CondNegate(0) should not change the scalar")
+		assert.True(t, curve.CondNegateScalar(group.NewScalar().Set(s), 1).Equal(group.NewScalar().Set(s).Negate()), "CondNegate(1) should negate the scalar")
+
+		assert.True(t, curve.CondNegatePoint(P, 0).Equal(P), "CondNegate(0) should not change the point")
+		assert.True(t, curve.CondNegatePoint(P, 1).Equal(P.Negate()), "CondNegate(1) should negate the point")
+		assert.True(t, curve.CondNegatePoint(P, 1).Add(P).IsIdentity())
+	}
+	assert.True(t, curve.CondNegatePoint(group.NewPoint(), 1).IsIdentity())
+	assert.True(t, curve.CondNegateScalar(group.NewScalar(), 1).IsZero())
+
+	// normalizing to an even y coordinate, as in BIP340
+	for i := 0; i < 32; i++ {
+		P := sample.Scalar(rand.Reader, group).ActOnBase().(*curve.Secp256k1Point)
+		odd := 0
+		if !P.HasEvenY() {
+			odd = 1
+		}
+		even := curve.CondNegatePoint(P, odd).(*curve.Secp256k1Point)
+		assert.True(t, even.HasEvenY())
+		assert.Equal(t, P.XBytes(), even.XBytes())
+	}
+}
+
+func TestSecp256k1_Parity(t *testing.T) {
+	group := curve.Secp256k1{}
+	for i := 0; i < 64; i++ {
+		s := sample.Scalar(rand.Reader, group)
+		// the sum is in Jacobian coordinates, and must be converted to affine form to read y
+		P := s.ActOnBase().Add(group.NewBasePoint()).(*curve.Secp256k1Point)
+		uncompressed, err := P.MarshalUncompressed()
+		require.NoError(t, err)
+		assert.Equal(t, uncompressed[64]&1 == 0, P.HasEvenY(), "HasEvenY should match the parity of the affine y coordinate")
+		assert.NotEqual(t, P.HasEvenY(), P.Negate().(*curve.Secp256k1Point).HasEvenY())
+	}
+}
@@ -0,0 +1,57 @@
+package curve
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// MarshalText implements encoding.TextMarshaler, as the hex encoding of MarshalBinary.
+//
+// This lets points be embedded in text formats such as YAML, TOML or environment variables.
+// For JSON with other encodings, see JSONPoint.
+//
+// Decoders which do not keep the value of a field of type Point, such as gopkg.in/yaml.v3,
+// can only decode into fields of the concrete type *Secp256k1Point.
+func (p *Secp256k1Point) MarshalText() ([]byte, error) {
+	return marshalHex(p.MarshalBinary())
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, using UnmarshalBinary.
+func (p *Secp256k1Point) UnmarshalText(text []byte) error {
+	data, err := unmarshalHex(text)
+	if err != nil {
+		return err
+	}
+	return p.UnmarshalBinary(data)
+}
+
+// MarshalText implements encoding.TextMarshaler, as the hex encoding of MarshalBinary.
+func (s *Secp256k1Scalar) MarshalText() ([]byte, error) {
+	return marshalHex(s.MarshalBinary())
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, using UnmarshalBinary.
+func (s *Secp256k1Scalar) UnmarshalText(text []byte) error {
+	data, err := unmarshalHex(text)
+	if err != nil {
+		return err
+	}
+	return s.UnmarshalBinary(data)
+}
+
+func marshalHex(data []byte, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	text := make([]byte, hex.EncodedLen(len(data)))
+	hex.Encode(text, data)
+	return text, nil
+}
+
+func unmarshalHex(text []byte) ([]byte, error) {
+	data := make([]byte, hex.DecodedLen(len(text)))
+	if _, err := hex.Decode(data, text); err != nil {
+		return nil, fmt.Errorf("curve: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,38 @@
+package curve_test
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"gopkg.in/yaml.v3"
+)
+
+type textPair struct {
+	Point  *curve.Secp256k1Point  `yaml:"point"`
+	Scalar *curve.Secp256k1Scalar `yaml:"scalar"`
+}
+
+func TestText(t *testing.T) {
+	group := curve.Secp256k1{}
+	x, X := sample.ScalarPointPair(rand.Reader, group)
+	pair := textPair{Point: X.(*curve.Secp256k1Point), Scalar: x.(*curve.Secp256k1Scalar)}
+
+	out, err := yaml.Marshal(pair)
+	require.NoError(t, err)
+	pointData, err := X.MarshalBinary()
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "point: "+hex.EncodeToString(pointData))
+
+	var decoded textPair
+	require.NoError(t, yaml.Unmarshal(out, &decoded))
+	assert.True(t, decoded.Point.Equal(X))
+	assert.True(t, decoded.Scalar.Equal(x))
+
+	assert.Error(t, yaml.Unmarshal([]byte("point: zz"), &decoded), "invalid hex should be rejected")
+	assert.Error(t, yaml.Unmarshal([]byte("point: 02ff"), &decoded), "invalid points should be rejected")
+}
@@ -3,11 +3,12 @@ package polynomial
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
 
-	"github.com/cronokirby/saferith"
 	"github.com/fxamacker/cbor/v2"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
 )
 
 type rawExponentData struct {
@@ -44,9 +45,43 @@ func NewPolynomialExponent(polynomial *Polynomial) *Exponent {
 	return p
 }
 
+// Validate returns an error if p was not fully initialized.
+//
+// This happens for a zero Exponent, or one created with EmptyExponent which was never unmarshalled.
+// The methods Constant and Degree assume that p is valid, and may panic otherwise.
+func (p *Exponent) Validate() error {
+	if p == nil || p.group == nil {
+		return errors.New("exponent: no group")
+	}
+	if !p.IsConstant && len(p.coefficients) == 0 {
+		return errors.New("exponent: no coefficients")
+	}
+	for _, c := range p.coefficients {
+		if c == nil {
+			return errors.New("exponent: nil coefficient")
+		}
+	}
+	return nil
+}
+
 // Evaluate returns F(x) = [secret + a₁•x + … + aₜ•xᵗ]•G.
+//
+// It panics with the error of Validate if p is invalid, rather than returning a meaningless point.
+// Exponents received from other parties should be evaluated with EvaluateChecked instead.
 func (p *Exponent) Evaluate(x curve.Scalar) curve.Point {
-	result := p.group.NewPoint()
+	result, err := p.EvaluateChecked(x)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// EvaluateChecked is like Evaluate, but returns the error of Validate if p is invalid.
+func (p *Exponent) EvaluateChecked(x curve.Scalar) (curve.Point, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	result := curve.Identity(p.group)
 
 	for i := len(p.coefficients) - 1; i >= 0; i-- {
 		// Bₙ₋₁ = [x]Bₙ  + Aₙ₋₁
@@ -59,7 +94,27 @@ func (p *Exponent) Evaluate(x curve.Scalar) curve.Point {
 		result = x.Act(result)
 	}
 
-	return result
+	return result, nil
+}
+
+// VerifyFeldmanShare returns true if share is the evaluation at id of the polynomial committed to by commitment,
+// namely if share⋅G = F(id).
+//
+// It only requires the public commitment, so that a single share can be audited without the rest of a Config.
+// It returns false if commitment is invalid, or if id is mapped to 0.
+func VerifyFeldmanShare(commitment *Exponent, id party.ID, share curve.Scalar) bool {
+	if share == nil || commitment.Validate() != nil {
+		return false
+	}
+	if err := curve.CheckCurve(commitment.group, share); err != nil {
+		return false
+	}
+	x := id.Scalar(commitment.group)
+	if x.IsZero() {
+		return false
+	}
+	expected, err := commitment.EvaluateChecked(x)
+	return err == nil && share.ActOnBase().Equal(expected)
 }
 
 // evaluateClassic evaluates a polynomial in a given variable index
@@ -67,8 +122,8 @@ func (p *Exponent) Evaluate(x curve.Scalar) curve.Point {
 func (p *Exponent) evaluateClassic(x curve.Scalar) curve.Point {
 	var tmp curve.Point
 
-	xPower := p.group.NewScalar().SetNat(new(saferith.Nat).SetUint64(1))
-	result := p.group.NewPoint()
+	xPower := curve.ScalarOne(p.group)
+	result := curve.Identity(p.group)
 
 	if p.IsConstant {
 		// since we start at index 1 of the polynomial, x must be x and not 1
@@ -157,7 +212,7 @@ func (p *Exponent) Equal(other Exponent) bool {
 
 // Constant returns the constant coefficient of the polynomial 'in the exponent'.
 func (p *Exponent) Constant() curve.Point {
-	c := p.group.NewPoint()
+	c := curve.Identity(p.group)
 	if p.IsConstant {
 		return c
 	}
@@ -184,27 +239,49 @@ func EmptyExponent(group curve.Curve) *Exponent {
 	return &Exponent{group: group}
 }
 
-func (e *Exponent) UnmarshalBinary(data []byte) error {
+func (e *Exponent) UnmarshalBinary(data []byte) (err error) {
 	if e == nil || e.group == nil {
 		return errors.New("can't unmarshal Exponent with no group")
 	}
 	group := e.group
+	if len(data) < 4 {
+		return fmt.Errorf("exponent: data too short (got %d bytes, need at least 4)", len(data))
+	}
 	size := binary.BigEndian.Uint32(data)
+	// each coefficient requires at least one byte of cbor, so this bounds the allocation below
+	if uint64(size) > uint64(len(data)-4) {
+		return fmt.Errorf("exponent: %d coefficients cannot fit in %d bytes", size, len(data)-4)
+	}
 	e.coefficients = make([]curve.Point, int(size))
 	for i := 0; i < len(e.coefficients); i++ {
 		e.coefficients[i] = group.NewPoint()
 	}
 	rawExponent := rawExponentData{Coefficients: e.coefficients}
-	if err := cbor.Unmarshal(data[4:], &rawExponent); err != nil {
+	// the decoder panics on a null coefficient, instead of returning an error
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("exponent: invalid coefficients: %v", r)
+		}
+	}()
+	if err = cbor.Unmarshal(data[4:], &rawExponent); err != nil {
 		return err
 	}
+	// the identity cannot be encoded, so a coefficient still equal to it was missing from data
+	for _, c := range rawExponent.Coefficients {
+		if c == nil || c.IsIdentity() {
+			return errors.New("exponent: missing coefficient")
+		}
+	}
 	e.group = group
 	e.coefficients = rawExponent.Coefficients
 	e.IsConstant = rawExponent.IsConstant
-	return nil
+	return e.Validate()
 }
 
 func (e *Exponent) MarshalBinary() ([]byte, error) {
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
 	data, err := cbor.Marshal(rawExponentData{
 		IsConstant:   e.IsConstant,
 		Coefficients: e.coefficients,
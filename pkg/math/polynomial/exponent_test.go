@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
 )
 
 func TestExponent_Evaluate(t *testing.T) {
@@ -84,3 +85,106 @@ func TestMarshall(t *testing.T) {
 	require.NoError(t, err, "failed to Unmarshal")
 	assert.True(t, polyExp.Equal(*polyExp2), "should be the same")
 }
+
+func TestExponent_UnmarshalShort(t *testing.T) {
+	group := curve.Secp256k1{}
+
+	assert.NotPanics(t, func() {
+		err := EmptyExponent(group).UnmarshalBinary([]byte{0, 1})
+		assert.Error(t, err, "should fail on data shorter than the length prefix")
+	})
+
+	assert.NotPanics(t, func() {
+		err := EmptyExponent(group).UnmarshalBinary([]byte{0xff, 0xff, 0xff, 0xff, 0})
+		assert.Error(t, err, "should fail on a length prefix larger than the payload")
+	})
+}
+
+func TestExponent_Validate(t *testing.T) {
+	group := curve.Secp256k1{}
+
+	assert.Error(t, EmptyExponent(group).Validate(), "empty exponent should be invalid")
+	assert.Error(t, (&Exponent{}).Validate(), "zero exponent should be invalid")
+	assert.Error(t, (*Exponent)(nil).Validate(), "nil exponent should be invalid")
+
+	x := sample.Scalar(rand.Reader, group)
+	_, err := EmptyExponent(group).EvaluateChecked(x)
+	assert.EqualError(t, err, "exponent: no coefficients", "an exponent which was never unmarshalled should not be evaluated")
+	_, err = (&Exponent{}).EvaluateChecked(x)
+	assert.EqualError(t, err, "exponent: no group")
+	assert.PanicsWithError(t, "exponent: no group", func() { (&Exponent{}).Evaluate(x) })
+
+	assert.NotPanics(t, func() {
+		_, err := EmptyExponent(group).MarshalBinary()
+		assert.Error(t, err, "should not marshal an empty exponent")
+	})
+
+	// an exponent with no coefficients must not be accepted from the wire
+	data, err := cbor.Marshal(&rawExponentData{IsConstant: false})
+	require.NoError(t, err)
+	assert.Error(t, EmptyExponent(group).UnmarshalBinary(append([]byte{0, 0, 0, 0}, data...)))
+
+	poly := NewPolynomial(group, 3, sample.Scalar(rand.Reader, group))
+	assert.NoError(t, NewPolynomialExponent(poly).Validate())
+	evaluated, err := NewPolynomialExponent(poly).EvaluateChecked(x)
+	require.NoError(t, err)
+	assert.True(t, poly.Evaluate(x).ActOnBase().Equal(evaluated))
+	assert.NoError(t, NewPolynomialExponent(NewPolynomial(group, 0, nil)).Validate(), "constant zero exponent is valid")
+}
+
+func FuzzExponentUnmarshal(f *testing.F) {
+	group := curve.Secp256k1{}
+	for degree := 0; degree <= 5; degree++ {
+		for _, constant := range []curve.Scalar{group.NewScalar(), sample.Scalar(rand.Reader, group)} {
+			data, err := NewPolynomialExponent(NewPolynomial(group, degree, constant)).MarshalBinary()
+			require.NoError(f, err)
+			f.Add(data)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		e := EmptyExponent(group)
+		if err := e.UnmarshalBinary(data); err != nil {
+			return
+		}
+		// a successful decode must be a valid Exponent which survives a round-trip
+		encoded, err := e.MarshalBinary()
+		require.NoError(t, err)
+		decoded := EmptyExponent(group)
+		require.NoError(t, decoded.UnmarshalBinary(encoded))
+		assert.True(t, e.Equal(*decoded))
+		reencoded, err := decoded.MarshalBinary()
+		require.NoError(t, err)
+		assert.Equal(t, encoded, reencoded)
+	})
+}
+
+func TestVerifyFeldmanShare(t *testing.T) {
+	group := curve.Secp256k1{}
+	secret := sample.Scalar(rand.Reader, group)
+	poly := NewPolynomial(group, 2, secret)
+	commitment := NewPolynomialExponent(poly)
+
+	id := party.ID("auditor")
+	share := poly.Evaluate(id.Scalar(group))
+	assert.True(t, VerifyFeldmanShare(commitment, id, share), "a valid share should be accepted")
+
+	invalid := group.NewScalar().Set(share).Add(curve.ScalarOne(group))
+	assert.False(t, VerifyFeldmanShare(commitment, id, invalid), "an invalid share should be rejected")
+	assert.False(t, VerifyFeldmanShare(commitment, party.ID("other"), share), "a share of another party should be rejected")
+	assert.False(t, VerifyFeldmanShare(EmptyExponent(group), id, share), "an uninitialized commitment should be rejected")
+	assert.False(t, VerifyFeldmanShare(commitment, id, nil))
+}
+
+func BenchmarkExponent_Evaluate(b *testing.B) {
+	group := curve.Secp256k1{}
+	for _, degree := range []int{1, 4, 16} {
+		polyExp := NewPolynomialExponent(NewPolynomial(group, degree, sample.Scalar(rand.Reader, group)))
+		x := sample.Scalar(rand.Reader, group)
+		b.Run(fmt.Sprintf("degree=%d", degree), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				polyExp.Evaluate(x)
+			}
+		})
+	}
+}
@@ -1,14 +1,71 @@
 package polynomial
 
 import (
-	"github.com/cronokirby/saferith"
+	"fmt"
+
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 )
 
+// CheckInterpolationDomain returns an error if the Lagrange coefficients of interpolationDomain are undefined,
+// namely if the scalar of an ID is 0, or if two IDs have the same scalar.
+//
+// This can happen for distinct IDs, for instance if they only differ by leading zero bytes.
+func CheckInterpolationDomain(group curve.Curve, interpolationDomain []party.ID) error {
+	seen := make(map[string]party.ID, len(interpolationDomain))
+	for _, id := range interpolationDomain {
+		x := id.Scalar(group)
+		if x.IsZero() {
+			return fmt.Errorf("polynomial: party %q has interpolation point 0", id)
+		}
+		data, err := x.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if other, ok := seen[string(data)]; ok {
+			return fmt.Errorf("polynomial: parties %q and %q have the same interpolation point", other, id)
+		}
+		seen[string(data)] = id
+	}
+	return nil
+}
+
 // Lagrange returns the Lagrange coefficients at 0 for all parties in the interpolation domain.
 func Lagrange(group curve.Curve, interpolationDomain []party.ID) map[party.ID]curve.Scalar {
-	return LagrangeFor(group, interpolationDomain, interpolationDomain...)
+	return LagrangeAtZero(group, interpolationDomain)
+}
+
+// LagrangeAtZero returns the Lagrange coefficients at 0 for all parties in the interpolation domain,
+// as used to reconstruct a secret, or to combine shares.
+//
+// It gives the same result as LagrangeFor with the whole domain as subset, but inverts all denominators at once
+// with curve.BatchInvert, so that it performs a single inversion instead of one per party.
+func LagrangeAtZero(group curve.Curve, interpolationDomain []party.ID) map[party.ID]curve.Scalar {
+	scalars, numerator := getScalarsAndNumerator(group, interpolationDomain)
+
+	// denominators[j] = xⱼ⋅(x₀ - xⱼ)⋅⋅⋅(xⱼ₋₁ - xⱼ)⋅(xⱼ₊₁ - xⱼ)⋅⋅⋅(xₖ - xⱼ)
+	denominators := make([]curve.Scalar, len(interpolationDomain))
+	tmp := group.NewScalar()
+	for j, idJ := range interpolationDomain {
+		xJ := scalars[idJ]
+		denominator := group.NewScalar().Set(xJ)
+		for _, idI := range interpolationDomain {
+			if idI == idJ {
+				continue
+			}
+			// tmp = xᵢ - xⱼ
+			tmp.Set(xJ).Negate().Add(scalars[idI])
+			denominator.Mul(tmp)
+		}
+		denominators[j] = denominator
+	}
+
+	coefficients := make(map[party.ID]curve.Scalar, len(interpolationDomain))
+	for j, inverse := range curve.BatchInvert(denominators) {
+		// lⱼ = numerator / denominators[j]
+		coefficients[interpolationDomain[j]] = inverse.Mul(numerator)
+	}
+	return coefficients
 }
 
 // LagrangeFor returns the Lagrange coefficients at 0 for all parties in the given subset.
@@ -31,7 +88,7 @@ func LagrangeSingle(group curve.Curve, interpolationDomain []party.ID, j party.I
 // getScalarsAndNumerator returns the Scalars associated to the list of party.IDs.
 func getScalarsAndNumerator(group curve.Curve, interpolationDomain []party.ID) (map[party.ID]curve.Scalar, curve.Scalar) {
 	// numerator = x₀ * … * xₖ
-	numerator := group.NewScalar().SetNat(new(saferith.Nat).SetUint64(1))
+	numerator := curve.ScalarOne(group)
 	scalars := make(map[party.ID]curve.Scalar, len(interpolationDomain))
 	for _, id := range interpolationDomain {
 		xi := id.Scalar(group)
@@ -57,7 +114,7 @@ func lagrange(group curve.Curve, interpolationDomain map[party.ID]curve.Scalar,
 	tmp := group.NewScalar()
 
 	// denominator = xⱼ⋅(xⱼ - x₀)⋅⋅⋅(xⱼ₋₁ - xⱼ)⋅(xⱼ₊₁ - xⱼ)⋅⋅⋅(xₖ - xⱼ)
-	denominator := group.NewScalar().SetNat(new(saferith.Nat).SetUint64(1))
+	denominator := curve.ScalarOne(group)
 	for i, xI := range interpolationDomain {
 		if i == j {
 			// lⱼ *= xⱼ
@@ -8,6 +8,7 @@ import (
 	"github.com/taurusgroup/multi-party-sig/internal/test"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
 )
 
 func TestLagrange(t *testing.T) {
@@ -29,3 +30,39 @@ func TestLagrange(t *testing.T) {
 	assert.True(t, sumEven.Equal(one))
 	assert.True(t, sumOdd.Equal(one))
 }
+
+func TestCheckInterpolationDomain(t *testing.T) {
+	group := curve.Secp256k1{}
+	assert.NoError(t, polynomial.CheckInterpolationDomain(group, test.PartyIDs(5)))
+	assert.Error(t, polynomial.CheckInterpolationDomain(group, []party.ID{"a", "\x00a"}), "leading zeros should collide")
+	assert.Error(t, polynomial.CheckInterpolationDomain(group, []party.ID{"a", "\x00"}), "zero should be rejected")
+}
+
+func TestLagrangeAtZero(t *testing.T) {
+	group := curve.Secp256k1{}
+
+	for _, N := range []int{0, 1, 2, 5, 10} {
+		ids := test.PartyIDs(N)
+		fast := polynomial.LagrangeAtZero(group, ids)
+		general := polynomial.LagrangeFor(group, ids, ids...)
+		assert.Len(t, fast, N)
+		for _, id := range ids {
+			assert.True(t, general[id].Equal(fast[id]), "coefficient of %s with %d parties", id, N)
+		}
+	}
+}
+
+func BenchmarkLagrange(b *testing.B) {
+	group := curve.Secp256k1{}
+	ids := test.PartyIDs(20)
+	b.Run("general", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			polynomial.LagrangeFor(group, ids, ids...)
+		}
+	})
+	b.Run("at zero", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			polynomial.LagrangeAtZero(group, ids)
+		}
+	})
+}
@@ -1,7 +1,7 @@
 package polynomial
 
 import (
-	"crypto/rand"
+	"io"
 
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -16,6 +16,11 @@ type Polynomial struct {
 // NewPolynomial generates a Polynomial f(X) = secret + a₁⋅X + … + aₜ⋅Xᵗ,
 // with coefficients in ℤₚ, and degree t.
 func NewPolynomial(group curve.Curve, degree int, constant curve.Scalar) *Polynomial {
+	return NewPolynomialFromReader(sample.Reader, group, degree, constant)
+}
+
+// NewPolynomialFromReader is like NewPolynomial, but the random coefficients are read from rand.
+func NewPolynomialFromReader(rand io.Reader, group curve.Curve, degree int, constant curve.Scalar) *Polynomial {
 	polynomial := &Polynomial{
 		group:        group,
 		coefficients: make([]curve.Scalar, degree+1),
@@ -28,7 +33,7 @@ func NewPolynomial(group curve.Curve, degree int, constant curve.Scalar) *Polyno
 	polynomial.coefficients[0] = constant
 
 	for i := 1; i <= degree; i++ {
-		polynomial.coefficients[i] = sample.Scalar(rand.Reader, group)
+		polynomial.coefficients[i] = sample.Scalar(rand, group)
 	}
 
 	return polynomial
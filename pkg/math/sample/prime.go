@@ -77,10 +77,8 @@ func tryBlumPrime(rand io.Reader) *saferith.Nat {
 
 	bytes := make([]byte, (params.BitsBlumPrime+7)/8)
 
-	_, err := io.ReadFull(rand, bytes)
-	if err != nil {
-		return nil
-	}
+	// returning nil would make Paillier search forever if rand keeps failing
+	mustReadBits(rand, bytes)
 	// For both p and (p - 1) / 2 to be prime, it must be the case that p = 3 mod 4
 
 	// Clear low bits to ensure that our number is 3 mod 4
@@ -159,6 +157,8 @@ func tryBlumPrime(rand io.Reader) *saferith.Nat {
 // Paillier generate the necessary integers for a Paillier key pair.
 // p, q are safe primes ((p - 1) / 2 is also prime), and Blum primes (p = 3 mod 4)
 // n = pq.
+//
+// It panics if reading from rand keeps failing.
 func Paillier(rand io.Reader, pl *pool.Pool) (p, q *saferith.Nat) {
 	reader := pool.NewLockedReader(rand)
 	results := pl.Search(2, func() interface{} {
@@ -14,13 +14,16 @@ const maxIterations = 255
 
 var ErrMaxIterations = fmt.Errorf("sample: failed to generate after %d iterations", maxIterations)
 
+// mustReadBits fills buf from rand, and panics with an error wrapping ErrMaxIterations and the last read error
+// if rand keeps failing, for instance with ErrUnhealthy.
 func mustReadBits(rand io.Reader, buf []byte) {
+	var err error
 	for i := 0; i < maxIterations; i++ {
-		if _, err := io.ReadFull(rand, buf); err == nil {
+		if _, err = io.ReadFull(rand, buf); err == nil {
 			return
 		}
 	}
-	panic(ErrMaxIterations)
+	panic(fmt.Errorf("%w: %w", ErrMaxIterations, err))
 }
 
 // ModN samples an element of ℤₙ.
@@ -39,7 +42,7 @@ func ModN(rand io.Reader, n *saferith.Modulus) *saferith.Nat {
 	return out
 }
 
-// UnitModN returns a u ∈ ℤₙˣ.
+// UnitModN returns a u ∈ ℤₙˣ, with 1 ≤ u < n.
 func UnitModN(rand io.Reader, n *saferith.Modulus) *saferith.Nat {
 	out := new(saferith.Nat)
 	buf := make([]byte, (n.BitLen()+7)/8)
@@ -48,7 +51,8 @@ func UnitModN(rand io.Reader, n *saferith.Modulus) *saferith.Nat {
 		// PERF: Reuse buffer instead of allocating each time
 		mustReadBits(rand, buf)
 		out.SetBytes(buf)
-		if out.IsUnit(n) == 1 {
+		// the buffer has the bit length of n, so out must also be reduced
+		if _, _, lt := out.CmpMod(n); lt == 1 && out.IsUnit(n) == 1 {
 			return out
 		}
 	}
@@ -2,6 +2,8 @@ package sample
 
 import (
 	"crypto/rand"
+	"errors"
+	"io"
 	"math/big"
 	"testing"
 
@@ -19,6 +21,20 @@ func TestModN(t *testing.T) {
 	}
 }
 
+func TestUnitModN(t *testing.T) {
+	// n is just above a power of 2, so that most samples of its bit length are larger than n
+	n := saferith.ModulusFromUint64(1<<20 + 7)
+	for i := 0; i < 100; i++ {
+		u := UnitModN(rand.Reader, n)
+		if _, _, lt := u.CmpMod(n); lt != 1 {
+			t.Fatalf("UnitModN generated a number >= %v: %v", n, u)
+		}
+		if u.IsUnit(n) != 1 {
+			t.Fatalf("UnitModN generated a number which is not a unit: %v", u)
+		}
+	}
+}
+
 const blumPrimeProbabilityIterations = 20
 
 func TestPaillier(t *testing.T) {
@@ -60,3 +76,63 @@ func BenchmarkModN(b *testing.B) {
 		resultNat = ModN(rand.Reader, n)
 	}
 }
+
+// failingSource is a RandSource whose health can be switched off.
+type failingSource struct{ healthy bool }
+
+func (s *failingSource) Read(p []byte) (int, error) { return rand.Read(p) }
+func (s *failingSource) HealthCheck() error {
+	if !s.healthy {
+		return errors.New("continuous test failed")
+	}
+	return nil
+}
+
+func TestRandSource(t *testing.T) {
+	source := &failingSource{healthy: true}
+	previous := SetRandSource(source)
+	defer SetRandSource(previous)
+
+	if err := HealthCheck(); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 32)
+	if _, err := io.ReadFull(Reader, buf); err != nil {
+		t.Fatal(err)
+	}
+
+	source.healthy = false
+	if err := HealthCheck(); !errors.Is(err, ErrUnhealthy) {
+		t.Errorf("expected ErrUnhealthy, got %v", err)
+	}
+	if n, err := Reader.Read(buf); n != 0 || !errors.Is(err, ErrUnhealthy) {
+		t.Errorf("an unhealthy source should not be read, got %d bytes and %v", n, err)
+	}
+
+	if SetRandSource(nil) != source {
+		t.Error("SetRandSource should return the previous source")
+	}
+	if err := HealthCheck(); err != nil {
+		t.Errorf("nil should restore the default source, got %v", err)
+	}
+}
+
+// brokenReader always fails.
+type brokenReader struct{}
+
+func (brokenReader) Read([]byte) (int, error) { return 0, ErrUnhealthy }
+
+func TestPaillierBrokenReader(t *testing.T) {
+	for _, pl := range []*pool.Pool{nil, pool.NewPool(2)} {
+		func() {
+			defer func() {
+				err, _ := recover().(error)
+				if !errors.Is(err, ErrUnhealthy) {
+					t.Errorf("Paillier should panic with the error of the reader, got %v", err)
+				}
+			}()
+			Paillier(brokenReader{}, pl)
+		}()
+		pl.TearDown()
+	}
+}
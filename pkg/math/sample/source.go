@@ -0,0 +1,74 @@
+package sample
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrUnhealthy is wrapped by the errors returned when the RandSource fails its health check.
+var ErrUnhealthy = errors.New("sample: random source is unhealthy")
+
+// RandSource is a cryptographically secure random number generator which tests its own health,
+// for instance a FIPS validated generator with continuous health tests, or an HSM.
+type RandSource interface {
+	// Read fills p with random bytes, as io.Reader.
+	Read(p []byte) (n int, err error)
+	// HealthCheck returns an error if the generator has failed its health tests,
+	// in which case its output must not be used.
+	HealthCheck() error
+}
+
+// cryptoSource is the default RandSource, which reads from crypto/rand.
+type cryptoSource struct{}
+
+func (cryptoSource) Read(p []byte) (int, error) { return rand.Read(p) }
+func (cryptoSource) HealthCheck() error         { return nil }
+
+// sourceBox allows storing RandSource values of different types in an atomic.Value.
+type sourceBox struct{ RandSource }
+
+var source atomic.Value
+
+func init() {
+	source.Store(sourceBox{cryptoSource{}})
+}
+
+// SetRandSource replaces the RandSource used for all the randomness of the protocols,
+// from the Paillier primes to the nonces and the randomness of the zero-knowledge proofs.
+// It returns the previous RandSource, and restores the default, crypto/rand, if s is nil.
+//
+// It should be called once, before any protocol is started.
+func SetRandSource(s RandSource) RandSource {
+	if s == nil {
+		s = cryptoSource{}
+	}
+	return source.Swap(sourceBox{s}).(sourceBox).RandSource
+}
+
+// HealthCheck returns an error wrapping ErrUnhealthy if the current RandSource fails its health check.
+//
+// The protocols call it when a session is created, and before each round,
+// so that they abort with this error instead of consuming bad randomness.
+func HealthCheck() error {
+	if err := source.Load().(sourceBox).HealthCheck(); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnhealthy, err)
+	}
+	return nil
+}
+
+// Reader reads from the current RandSource, and should be used everywhere randomness is consumed.
+//
+// The health of the RandSource is checked before every read, and no bytes are returned if it is unhealthy.
+var Reader = sourceReader{}
+
+type sourceReader struct{}
+
+func (sourceReader) Read(p []byte) (int, error) {
+	s := source.Load().(sourceBox)
+	if err := s.HealthCheck(); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrUnhealthy, err)
+	}
+	return s.Read(p)
+}
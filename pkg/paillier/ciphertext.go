@@ -1,7 +1,7 @@
 package paillier
 
 import (
-	"crypto/rand"
+	"fmt"
 	"io"
 
 	"github.com/cronokirby/saferith"
@@ -56,7 +56,7 @@ func (ct Ciphertext) Clone() *Ciphertext {
 // The receiver is updated, and the nonce update is returned.
 func (ct *Ciphertext) Randomize(pk *PublicKey, nonce *saferith.Nat) *saferith.Nat {
 	if nonce == nil {
-		nonce = sample.UnitModN(rand.Reader, pk.n.Modulus)
+		nonce = sample.UnitModN(sample.Reader, pk.n.Modulus)
 	}
 	// c = c*r^N
 	tmp := pk.nSquared.Exp(nonce, pk.nNat)
@@ -84,7 +84,13 @@ func (ct *Ciphertext) MarshalBinary() ([]byte, error) {
 	return ct.c.MarshalBinary()
 }
 
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+//
+// It rejects ciphertexts longer than 2⋅MaxBits bits before decoding them.
 func (ct *Ciphertext) UnmarshalBinary(data []byte) error {
+	if err := checkBits(data, 2*MaxBits); err != nil {
+		return fmt.Errorf("ciphertext: %w", err)
+	}
 	ct.c = new(saferith.Nat)
 	return ct.c.UnmarshalBinary(data)
 }
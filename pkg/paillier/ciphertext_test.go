@@ -7,6 +7,8 @@ import (
 
 	"github.com/cronokirby/saferith"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 )
@@ -80,6 +82,32 @@ func TestEncDecRoundTrip(t *testing.T) {
 	}
 }
 
+func TestEncDecSigned(t *testing.T) {
+	nHalf := new(saferith.Nat).Rsh(paillierPublic.N().Nat(), 1, -1)
+	scalar := sample.Scalar(rand.Reader, curve.Secp256k1{})
+	plaintexts := map[string]*saferith.Int{
+		"zero":          new(saferith.Int).SetUint64(0),
+		"negative zero": new(saferith.Int).SetUint64(0).Neg(1),
+		"one":           new(saferith.Int).SetUint64(1),
+		"minus one":     new(saferith.Int).SetUint64(1).Neg(1),
+		"(N-1)/2":       new(saferith.Int).SetNat(nHalf),
+		"-(N-1)/2":      new(saferith.Int).SetNat(nHalf).Neg(1),
+		"scalar":        curve.MakeInt(scalar),
+		"minus scalar":  curve.MakeInt(scalar).Neg(1),
+	}
+	for name, m := range plaintexts {
+		ct, nonce := paillierPublic.Enc(m)
+		mActual, err := paillierSecret.Dec(ct)
+		require.NoError(t, err, name)
+		assert.Equal(t, 1, int(mActual.Eq(m)), "%s should be recovered exactly", name)
+		assert.True(t, ct.Equal(paillierPublic.EncWithNonce(mActual, nonce)), "%s should re-encrypt to the same ciphertext", name)
+	}
+
+	tooBig := new(saferith.Int).SetNat(new(saferith.Nat).Add(nHalf, new(saferith.Nat).SetUint64(1), -1))
+	assert.Panics(t, func() { paillierPublic.Enc(tooBig) })
+	assert.Panics(t, func() { paillierPublic.Enc(tooBig.Neg(1)) })
+}
+
 func testEncDecHomomorphic(a, b uint64, aNeg, bNeg bool) bool {
 	ma := new(saferith.Int).SetUint64(a)
 	if aNeg {
@@ -164,6 +192,34 @@ func TestDecWithRandomness(t *testing.T) {
 	}
 }
 
+func TestEncWithValidNonce(t *testing.T) {
+	m := new(saferith.Int).SetUint64(42).Neg(1)
+	nonce := sample.UnitModN(rand.Reader, paillierPublic.N())
+	ct, err := paillierPublic.EncWithValidNonce(m, nonce)
+	require.NoError(t, err)
+	assert.True(t, ct.Equal(paillierPublic.EncWithNonce(m, nonce)))
+	mActual, nonceActual, err := paillierSecret.DecWithRandomness(ct)
+	require.NoError(t, err)
+	assert.Equal(t, 1, int(mActual.Eq(m)), "Dec(EncWithValidNonce(m, r)) should be m")
+	assert.Equal(t, 1, int(nonceActual.Eq(nonce)), "the nonce should be recovered")
+
+	invalid := map[string]*saferith.Nat{
+		"nil":  nil,
+		"zero": new(saferith.Nat).SetUint64(0),
+		"p":    paillierSecret.P(),
+		"q":    paillierSecret.Q(),
+		"N":    paillierPublic.N().Nat(),
+	}
+	for name, nonce := range invalid {
+		_, err := paillierPublic.EncWithValidNonce(m, nonce)
+		assert.ErrorIs(t, err, ErrPaillierNonce, "nonce %s should be rejected", name)
+	}
+
+	tooBig := new(saferith.Int).SetNat(paillierPublic.N().Nat())
+	_, err = paillierPublic.EncWithValidNonce(tooBig, nonce)
+	assert.ErrorIs(t, err, ErrPaillierRange)
+}
+
 // Used to avoid benchmark optimization.
 var resultCiphertext *Ciphertext
 
@@ -195,3 +251,13 @@ func BenchmarkMulCiphertext(b *testing.B) {
 		resultCiphertext = c.Mul(paillierPublic, m)
 	}
 }
+
+func BenchmarkDecryption(b *testing.B) {
+	b.StopTimer()
+	m := sample.IntervalLEps(rand.Reader)
+	c, _ := paillierPublic.Enc(m)
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = paillierSecret.Dec(c)
+	}
+}
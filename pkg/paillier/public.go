@@ -1,10 +1,11 @@
 package paillier
 
 import (
-	"crypto/rand"
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
+	"math/bits"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
@@ -16,8 +17,26 @@ var (
 	ErrPaillierLength = errors.New("wrong number bit length of Paillier modulus N")
 	ErrPaillierEven   = errors.New("modulus N is even")
 	ErrPaillierNil    = errors.New("modulus N is nil")
+	ErrPaillierSmall  = errors.New("modulus N has a small prime factor")
+	ErrPaillierNonce  = errors.New("nonce is not a unit modulo N")
+	ErrPaillierRange  = errors.New("message is outside of range [-(N-1)/2, …, (N-1)/2]")
+	ErrPaillierLarge  = errors.New("value is longer than the maximum bit length")
 )
 
+// MaxBits is the maximum bit length of a modulus N accepted by ModulusFromBytes.
+// Ciphertexts accepted by Ciphertext.UnmarshalBinary are limited to 2⋅MaxBits.
+//
+// These checks only look at the length of the encoding, and happen before any arithmetic,
+// so that a peer cannot make us allocate or compute with huge numbers by claiming a large N.
+// It can be raised by applications which use larger moduli, but ValidateN only accepts params.BitsPaillier bits.
+var MaxBits = params.BitsPaillier
+
+// SmallFactorBound is the bound below which ValidateN checks that N has no prime factor.
+const SmallFactorBound = arith.SmallFactorBound
+
+// smallPrimesProduct = ∏ r, for all odd primes r < SmallFactorBound.
+var smallPrimesProduct = arith.SmallPrimesProduct()
+
 // PublicKey is a Paillier public key. It is represented by a modulus N.
 type PublicKey struct {
 	// n = p⋅q
@@ -53,9 +72,51 @@ func NewPublicKey(n *saferith.Modulus) *PublicKey {
 	}
 }
 
+// ModulusFromBytes decodes a modulus N from its big-endian encoding,
+// after checking that it is not zero and has at most MaxBits bits.
+//
+// The result should still be checked with ValidateN.
+func ModulusFromBytes(data []byte) (*saferith.Modulus, error) {
+	if err := checkBits(data, MaxBits); err != nil {
+		return nil, err
+	}
+	if bitLen(data) == 0 {
+		return nil, ErrPaillierNil
+	}
+	return saferith.ModulusFromBytes(data), nil
+}
+
+// NatFromBytes decodes a number modulo N, such as the Pedersen parameters S and T,
+// from its big-endian encoding, after checking that it has at most MaxBits bits.
+func NatFromBytes(data []byte) (*saferith.Nat, error) {
+	if err := checkBits(data, MaxBits); err != nil {
+		return nil, err
+	}
+	return new(saferith.Nat).SetBytes(data), nil
+}
+
+// checkBits returns ErrPaillierLarge if the big-endian integer in data has more than maxBits bits.
+func checkBits(data []byte, maxBits int) error {
+	if bits := bitLen(data); bits > maxBits {
+		return fmt.Errorf("have: %d bits, maximum %d: %w", bits, maxBits, ErrPaillierLarge)
+	}
+	return nil
+}
+
+// bitLen returns the bit length of the big-endian integer in data, without decoding it.
+func bitLen(data []byte) int {
+	for i, b := range data {
+		if b != 0 {
+			return 8*(len(data)-i-1) + bits.Len8(b)
+		}
+	}
+	return 0
+}
+
 // ValidateN performs basic checks to make sure the modulus is valid:
 // - log₂(n) = params.BitsPaillier.
 // - n is odd.
+// - n has no prime factor below SmallFactorBound, which would indicate a broken or malicious key.
 func ValidateN(n *saferith.Modulus) error {
 	if n == nil {
 		return ErrPaillierNil
@@ -68,6 +129,10 @@ func ValidateN(n *saferith.Modulus) error {
 	if nBig.Bit(0) != 1 {
 		return ErrPaillierEven
 	}
+	// gcd(N, ∏ r) = 1, a single gcd is cheaper than trial division by each prime
+	if new(big.Int).GCD(nil, nil, nBig, smallPrimesProduct).Cmp(big.NewInt(1)) != 0 {
+		return ErrPaillierSmall
+	}
 	return nil
 }
 
@@ -75,24 +140,24 @@ func ValidateN(n *saferith.Modulus) error {
 // The nonce used to encrypt is returned.
 //
 // The message m must be in the range [-(N-1)/2, …, (N-1)/2] and panics otherwise.
+// A negative m is encrypted as N + m, and zero is a valid message.
 //
 // ct = (1+N)ᵐρᴺ (mod N²).
 func (pk PublicKey) Enc(m *saferith.Int) (*Ciphertext, *saferith.Nat) {
-	nonce := sample.UnitModN(rand.Reader, pk.n.Modulus)
+	nonce := sample.UnitModN(sample.Reader, pk.n.Modulus)
 	return pk.EncWithNonce(m, nonce), nonce
 }
 
-// EncWithNonce returns the encryption of m under the public key pk.
+// EncWithNonce returns the encryption of m under the public key pk, using the given nonce ρ.
 // The nonce is not returned.
 //
-// The message m must be in the range [-(N-1)/2, …, (N-1)/2] and panics otherwise
+// The message m must be in the range [-(N-1)/2, …, (N-1)/2] and panics otherwise.
+// The nonce is not checked, and must be a unit modulo N for the ciphertext to be decryptable
+// with its randomness, and to hide m. Use EncWithValidNonce when the nonce comes from elsewhere.
 //
 // ct = (1+N)ᵐρᴺ (mod N²).
 func (pk PublicKey) EncWithNonce(m *saferith.Int, nonce *saferith.Nat) *Ciphertext {
-	mAbs := m.Abs()
-	nHalf := new(saferith.Nat).SetNat(pk.nNat)
-	nHalf.Rsh(nHalf, 1, -1)
-	if gt, _, _ := mAbs.Cmp(nHalf); gt == 1 {
+	if !pk.inRange(m) {
 		panic("paillier.Encrypt: tried to encrypt message outside of range [-(N-1)/2, …, (N-1)/2]")
 	}
 
@@ -106,6 +171,42 @@ func (pk PublicKey) EncWithNonce(m *saferith.Int, nonce *saferith.Nat) *Cipherte
 	return &Ciphertext{c: c}
 }
 
+// EncWithValidNonce is the same as EncWithNonce, but returns an error instead of encrypting
+// if m is out of range, or if the nonce is not a unit modulo N.
+//
+// Dec(EncWithValidNonce(m, ρ)) = m, and DecWithRandomness also returns ρ.
+func (pk PublicKey) EncWithValidNonce(m *saferith.Int, nonce *saferith.Nat) (*Ciphertext, error) {
+	if m == nil || !pk.inRange(m) {
+		return nil, fmt.Errorf("paillier: %w", ErrPaillierRange)
+	}
+	if err := pk.ValidateNonce(nonce); err != nil {
+		return nil, fmt.Errorf("paillier: %w", err)
+	}
+	return pk.EncWithNonce(m, nonce), nil
+}
+
+// ValidateNonce returns ErrPaillierNonce unless nonce ∈ [1, …, N-1] and gcd(nonce, N) = 1.
+func (pk PublicKey) ValidateNonce(nonce *saferith.Nat) error {
+	if nonce == nil {
+		return ErrPaillierNonce
+	}
+	if _, _, lt := nonce.CmpMod(pk.n.Modulus); lt != 1 {
+		return ErrPaillierNonce
+	}
+	if nonce.IsUnit(pk.n.Modulus) != 1 {
+		return ErrPaillierNonce
+	}
+	return nil
+}
+
+// inRange returns true if m ∈ [-(N-1)/2, …, (N-1)/2].
+func (pk PublicKey) inRange(m *saferith.Int) bool {
+	nHalf := new(saferith.Nat).SetNat(pk.nNat)
+	nHalf.Rsh(nHalf, 1, -1)
+	gt, _, _ := m.Abs().Cmp(nHalf)
+	return gt != 1
+}
+
 // Equal returns true if pk ≡ other.
 func (pk PublicKey) Equal(other *PublicKey) bool {
 	_, eq, _ := pk.n.Cmp(other.n.Modulus)
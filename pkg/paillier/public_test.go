@@ -0,0 +1,62 @@
+package paillier
+
+import (
+	"math/big"
+	"runtime"
+	"testing"
+
+	"github.com/cronokirby/saferith"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// multipleBelow returns the largest odd multiple of r below n, which has the same length as n.
+func multipleBelow(n *big.Int, r int64) *saferith.Modulus {
+	m := new(big.Int).Div(n, big.NewInt(r))
+	m.SetBit(m, 0, 0)
+	m.Add(m, big.NewInt(1))
+	if m.Mul(m, big.NewInt(r)).Cmp(n) > 0 {
+		m.Sub(m, big.NewInt(2*r))
+	}
+	return saferith.ModulusFromNat(new(saferith.Nat).SetBig(m, m.BitLen()))
+}
+
+func TestValidateN(t *testing.T) {
+	n := paillierPublic.N()
+	assert.NoError(t, ValidateN(n))
+	assert.ErrorIs(t, ValidateN(nil), ErrPaillierNil)
+
+	// 4093 is the largest prime below SmallFactorBound
+	for _, r := range []int64{3, 5, 4093} {
+		assert.ErrorIs(t, ValidateN(multipleBelow(n.Big(), r)), ErrPaillierSmall, "N with factor %d should be rejected", r)
+	}
+}
+
+func TestModulusFromBytes(t *testing.T) {
+	n, err := ModulusFromBytes(paillierPublic.N().Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, paillierPublic.N().Big(), n.Big())
+
+	// leading zeros do not count
+	_, err = ModulusFromBytes(append(make([]byte, 8), paillierPublic.N().Bytes()...))
+	assert.NoError(t, err)
+
+	_, err = ModulusFromBytes(make([]byte, 256))
+	assert.ErrorIs(t, err, ErrPaillierNil)
+	_, err = ModulusFromBytes(nil)
+	assert.ErrorIs(t, err, ErrPaillierNil)
+
+	huge := make([]byte, 100_000/8)
+	huge[0] = 1
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	before := stats.TotalAlloc
+	_, err = ModulusFromBytes(huge)
+	runtime.ReadMemStats(&stats)
+	assert.ErrorIs(t, err, ErrPaillierLarge)
+	assert.Less(t, stats.TotalAlloc-before, uint64(len(huge)), "a 100,000-bit N should be rejected before it is decoded")
+
+	ct := new(Ciphertext)
+	assert.NoError(t, ct.UnmarshalBinary(make([]byte, 2*MaxBits/8)))
+	assert.ErrorIs(t, ct.UnmarshalBinary(huge), ErrPaillierLarge)
+}
@@ -1,9 +1,9 @@
 package paillier
 
 import (
-	"crypto/rand"
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
@@ -65,7 +65,7 @@ func KeyGen(pl *pool.Pool) (pk *PublicKey, sk *SecretKey) {
 // NewSecretKey generates primes p and q suitable for the scheme, and returns the initialized SecretKey.
 func NewSecretKey(pl *pool.Pool) *SecretKey {
 	// TODO maybe we could take the reader as argument?
-	return NewSecretKeyFromPrimes(sample.Paillier(rand.Reader, pl))
+	return NewSecretKeyFromPrimes(sample.Paillier(sample.Reader, pl))
 }
 
 // NewSecretKeyFromPrimes generates a new SecretKey. Assumes that P and Q are prime.
@@ -103,7 +103,8 @@ func NewSecretKeyFromPrimes(P, Q *saferith.Nat) *SecretKey {
 	}
 }
 
-// Dec decrypts c and returns the plaintext m ∈ ± (N-2)/2.
+// Dec decrypts c and returns the plaintext m ∈ [-(N-1)/2, …, (N-1)/2], which is the domain of Enc.
+// Negative plaintexts are encrypted as N + m, and are recovered with their sign, so that Dec(Enc(m)) = m.
 // It returns an error if gcd(c, N²) != 1 or if c is not in [1, N²-1].
 func (sk *SecretKey) Dec(ct *Ciphertext) (*saferith.Int, error) {
 	oneNat := new(saferith.Nat).SetUint64(1)
@@ -149,7 +150,12 @@ func (sk *SecretKey) DecWithRandomness(ct *Ciphertext) (*saferith.Int, *saferith
 }
 
 func (sk SecretKey) GeneratePedersen() (*pedersen.Parameters, *saferith.Nat) {
-	s, t, lambda := sample.Pedersen(rand.Reader, sk.phi, sk.n.Modulus)
+	return sk.GeneratePedersenFromReader(sample.Reader)
+}
+
+// GeneratePedersenFromReader is like GeneratePedersen, but the parameters are sampled from rand.
+func (sk SecretKey) GeneratePedersenFromReader(rand io.Reader) (*pedersen.Parameters, *saferith.Nat) {
+	s, t, lambda := sample.Pedersen(rand, sk.phi, sk.n.Modulus)
 	ped := pedersen.New(sk.n, s, t)
 	return ped, lambda
 }
@@ -0,0 +1,63 @@
+package paillier
+
+import (
+	"fmt"
+	"math/big"
+	"runtime"
+	"testing"
+
+	"github.com/cronokirby/saferith"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+)
+
+func TestKeyGenParallel(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	_, sk := KeyGen(pl)
+	require.NoError(t, ValidatePrime(sk.P()), "p should be a safe Blum prime")
+	require.NoError(t, ValidatePrime(sk.Q()), "q should be a safe Blum prime")
+	_, eq, _ := sk.P().Cmp(sk.Q())
+	assert.NotEqual(t, 1, int(eq), "p and q should be distinct")
+}
+
+func TestNewSecretKeyFromSource(t *testing.T) {
+	source := NewPrimePool(paillierSecret.P(), paillierSecret.Q())
+	sk, err := NewSecretKeyFromSource(source)
+	require.NoError(t, err)
+	assert.True(t, sk.PublicKey.Equal(paillierPublic), "the key should be made of the primes of the pool")
+	assert.Zero(t, source.Len())
+
+	_, err = NewSecretKeyFromSource(source)
+	assert.ErrorIs(t, err, ErrPrimePoolEmpty)
+
+	_, err = NewSecretKeyFromSource(NewPrimePool(paillierSecret.P(), paillierSecret.P()))
+	assert.EqualError(t, err, "paillier: prime source returned the same prime twice")
+
+	// (P-1)/2 is a prime other than 3, so (p-1)/2 is a multiple of 3 for p = P + 4 or p = P + 8
+	notSafe := new(saferith.Nat).Add(paillierSecret.P(), new(saferith.Nat).SetUint64(4), -1)
+	half := new(big.Int).Rsh(notSafe.Big(), 1)
+	if half.Mod(half, big.NewInt(3)).Sign() != 0 {
+		notSafe.Add(notSafe, new(saferith.Nat).SetUint64(4), -1)
+	}
+	_, err = NewSecretKeyFromSource(NewPrimePool(paillierSecret.P(), notSafe))
+	assert.Error(t, err, "a prime source should not be trusted")
+}
+
+func BenchmarkKeyGen(b *testing.B) {
+	workerCounts := []int{1}
+	if n := runtime.NumCPU(); n > 1 {
+		workerCounts = append(workerCounts, n)
+	}
+	for _, workers := range workerCounts {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			pl := pool.NewPool(workers)
+			defer pl.TearDown()
+			for i := 0; i < b.N; i++ {
+				paillierPublic, paillierSecret = KeyGen(pl)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package paillier
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cronokirby/saferith"
+)
+
+// ErrPrimePoolEmpty is returned by PrimePool.GetSafePrime once all its primes have been used.
+var ErrPrimePoolEmpty = errors.New("paillier: prime pool is empty")
+
+// PrimeSource provides the primes of new Paillier keys, instead of generating them on the fly.
+//
+// This allows operators to plug a pool of pre-generated safe primes, or an HSM, since the
+// generation of safe primes is the most expensive part of a keygen.
+// The primes it returns must be secret, and must never be handed out twice.
+type PrimeSource interface {
+	// GetSafePrime returns a new safe Blum prime of params.BitsBlumPrime bits, see ValidatePrime.
+	GetSafePrime() (*saferith.Nat, error)
+}
+
+// NewSecretKeyFromSource returns a SecretKey made of two primes taken from source.
+//
+// The primes are checked with ValidatePrime, so that a faulty source can't produce a weak key.
+func NewSecretKeyFromSource(source PrimeSource) (*SecretKey, error) {
+	p, err := getSafePrime(source)
+	if err != nil {
+		return nil, err
+	}
+	q, err := getSafePrime(source)
+	if err != nil {
+		return nil, err
+	}
+	if p.Eq(q) == 1 {
+		return nil, errors.New("paillier: prime source returned the same prime twice")
+	}
+	return NewSecretKeyFromPrimes(p, q), nil
+}
+
+func getSafePrime(source PrimeSource) (*saferith.Nat, error) {
+	p, err := source.GetSafePrime()
+	if err != nil {
+		return nil, fmt.Errorf("paillier: prime source: %w", err)
+	}
+	if err = ValidatePrime(p); err != nil {
+		return nil, fmt.Errorf("paillier: prime source: %w", err)
+	}
+	return p, nil
+}
+
+// PrimePool is a PrimeSource which hands out pre-generated primes, each of them only once.
+// It is safe for concurrent use.
+type PrimePool struct {
+	primes []*saferith.Nat
+	mtx    sync.Mutex
+}
+
+// NewPrimePool returns a PrimePool with the given primes, which are validated when they are taken.
+func NewPrimePool(primes ...*saferith.Nat) *PrimePool {
+	return &PrimePool{primes: append([]*saferith.Nat(nil), primes...)}
+}
+
+// Add adds primes to the pool.
+func (pp *PrimePool) Add(primes ...*saferith.Nat) {
+	pp.mtx.Lock()
+	defer pp.mtx.Unlock()
+	pp.primes = append(pp.primes, primes...)
+}
+
+// Len returns the number of primes left in the pool.
+func (pp *PrimePool) Len() int {
+	pp.mtx.Lock()
+	defer pp.mtx.Unlock()
+	return len(pp.primes)
+}
+
+// GetSafePrime implements PrimeSource, and returns ErrPrimePoolEmpty once the pool is exhausted.
+func (pp *PrimePool) GetSafePrime() (*saferith.Nat, error) {
+	pp.mtx.Lock()
+	defer pp.mtx.Unlock()
+	if len(pp.primes) == 0 {
+		return nil, ErrPrimePoolEmpty
+	}
+	last := len(pp.primes) - 1
+	p := pp.primes[last]
+	pp.primes[last] = nil
+	pp.primes = pp.primes[:last]
+	return p, nil
+}
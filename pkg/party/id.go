@@ -1,8 +1,11 @@
 package party
 
 import (
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
+	"strconv"
 
 	"github.com/cronokirby/saferith"
 	"github.com/fxamacker/cbor/v2"
@@ -18,10 +21,54 @@ import (
 // This ID is used as an interpolation point of a polynomial sharing of the secret key.
 type ID string
 
-// Scalar converts this ID into a scalar.
+// MaxIDBytes is the length of the binary IDs accepted by IDFromBytes, which fits a UUID.
+const MaxIDBytes = 16
+
+// IDFromBytes returns the canonical ID for the binary identifier b, such as the 16 bytes of a UUID,
+// which is the lowercase hexadecimal encoding of b.
+//
+// The resulting ID is at most 32 bytes long and starts with a printable character, so that distinct
+// identifiers of the same length always have distinct interpolation points.
+// It returns an error if b is empty or longer than MaxIDBytes.
+func IDFromBytes(b []byte) (ID, error) {
+	if len(b) == 0 {
+		return "", errors.New("party: empty ID")
+	}
+	if len(b) > MaxIDBytes {
+		return "", fmt.Errorf("party: ID of %d bytes is longer than %d bytes", len(b), MaxIDBytes)
+	}
+	return ID(hex.EncodeToString(b)), nil
+}
+
+// IDFromUint64 returns the canonical ID for n, which is its decimal representation.
+func IDFromUint64(n uint64) ID {
+	return ID(strconv.FormatUint(n, 10))
+}
+
+// Validate returns an error if id is not mapped to its scalar consistently, namely if it is empty,
+// longer than 32 bytes, or starts with a zero byte.
+//
+// IDs returned by IDFromBytes and IDFromUint64 are always valid, and the protocols reject sessions with other IDs.
+func (id ID) Validate() error {
+	if id == "" {
+		return errors.New("party: empty ID")
+	}
+	if len(id) > 32 {
+		return fmt.Errorf("party: ID %q is longer than 32 bytes", id)
+	}
+	if id[0] == 0 {
+		return fmt.Errorf("party: ID %q starts with a zero byte", id)
+	}
+	return nil
+}
+
+// Scalar converts this ID into a scalar, which is its interpolation point
+// in the polynomial sharing of the secret scalar value used for ECDSA.
 //
-// All of the IDs of our participants form a polynomial sharing of the secret
-// scalar value used for ECDSA.
+// The mapping is part of the format of the shares, and will not change: the bytes of the ID are read
+// as a big-endian integer, which is reduced modulo the order of group. Other implementations must use
+// the same mapping to interoperate. It is deterministic, and injective on IDs without leading zero bytes
+// which are shorter than the order; polynomial.CheckInterpolationDomain rejects sets of IDs where it isn't.
 func (id ID) Scalar(group curve.Curve) curve.Scalar {
 	return group.NewScalar().SetNat(new(saferith.Nat).SetBytes([]byte(id)))
 }
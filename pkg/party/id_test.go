@@ -0,0 +1,97 @@
+package party_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/cronokirby/saferith"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+func TestID_Scalar(t *testing.T) {
+	group := curve.Secp256k1{}
+
+	// the mapping is fixed, so that other implementations can agree on the interpolation points
+	for id, expected := range map[party.ID]uint64{
+		"a":                 0x61,
+		"ab":                0x6162,
+		"\x01\x00":          0x100,
+		"\x00\x00\x00\x07":  7,
+		"\xff\xff\xff\xff":  0xffffffff,
+		"\x01\x02\x03\x04":  0x01020304,
+		"\x7f\x00\x00\x00":  0x7f000000,
+		"\x00\x00\x00\x00z": 0x7a,
+	} {
+		assert.True(t, group.NewScalar().SetNat(new(saferith.Nat).SetUint64(expected)).Equal(id.Scalar(group)), "scalar of %q", id)
+	}
+	assert.True(t, party.ID("a").Scalar(group).Equal(party.ID("\x00a").Scalar(group)), "leading zero bytes are ignored")
+	order, _ := group.Order().Nat().MarshalBinary()
+	assert.True(t, party.ID(order).Scalar(group).IsZero(), "the ID is reduced modulo the order")
+
+	// deterministic and injective over realistic IDs
+	seen := make(map[string]party.ID)
+	for i := 0; i < 10000; i++ {
+		for _, id := range []party.ID{
+			party.ID(fmt.Sprintf("party-%d", i)),
+			party.ID(fmt.Sprintf("%08x-4e6f-4a1b-9c3d-%012x", i, i*7919)),
+			party.ID(fmt.Sprint(i + 1)),
+		} {
+			x := id.Scalar(group)
+			require.True(t, x.Equal(id.Scalar(group)), "the scalar of %q should be deterministic", id)
+			data, err := x.MarshalBinary()
+			require.NoError(t, err)
+			other, ok := seen[string(data)]
+			require.False(t, ok, "%q and %q have the same scalar", id, other)
+			seen[string(data)] = id
+		}
+	}
+}
+
+func TestIDFromBytes(t *testing.T) {
+	group := curve.Secp256k1{}
+
+	id, err := party.IDFromBytes([]byte{0x00, 0x01, 0xab})
+	require.NoError(t, err)
+	assert.Equal(t, party.ID("0001ab"), id, "the canonical form is lowercase hex")
+	assert.NoError(t, id.Validate())
+
+	_, err = party.IDFromBytes(nil)
+	assert.Error(t, err)
+	_, err = party.IDFromBytes(make([]byte, party.MaxIDBytes+1))
+	assert.Error(t, err)
+
+	// distinct UUIDs, including those with leading zero bytes, have distinct interpolation points
+	uuids := [][]byte{make([]byte, 16), make([]byte, 16), make([]byte, 16)}
+	uuids[1][15] = 1
+	for i := range uuids[2] {
+		uuids[2][i] = 0xff
+	}
+	seen := make(map[string]party.ID)
+	for _, b := range uuids {
+		id, err := party.IDFromBytes(b)
+		require.NoError(t, err)
+		require.NoError(t, id.Validate())
+		data, err := id.Scalar(group).MarshalBinary()
+		require.NoError(t, err)
+		other, ok := seen[string(data)]
+		require.False(t, ok, "%q and %q have the same scalar", id, other)
+		seen[string(data)] = id
+	}
+}
+
+func TestIDFromUint64(t *testing.T) {
+	assert.Equal(t, party.ID("0"), party.IDFromUint64(0))
+	assert.Equal(t, party.ID("18446744073709551615"), party.IDFromUint64(math.MaxUint64))
+	assert.NoError(t, party.IDFromUint64(42).Validate())
+}
+
+func TestID_Validate(t *testing.T) {
+	assert.NoError(t, party.ID("a").Validate())
+	assert.Error(t, party.ID("").Validate())
+	assert.Error(t, party.ID("\x00a").Validate())
+	assert.Error(t, party.ID(make([]byte, 33)).Validate())
+}
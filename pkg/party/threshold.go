@@ -0,0 +1,44 @@
+package party
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Fault models accepted by RecommendThreshold.
+const (
+	// FaultModelHonestMajority requires a strict majority of the parties to sign: t = ⌊n/2⌋.
+	FaultModelHonestMajority = "honest-majority"
+	// FaultModelBFT requires more than two thirds of the parties to sign, as in BFT consensus: t = ⌊2n/3⌋.
+	FaultModelBFT = "bft"
+	// FaultModelAll requires all parties to sign: t = n-1.
+	FaultModelAll = "all"
+)
+
+// RecommendThreshold returns a threshold for a group of n parties, given one of the fault models above.
+//
+// The threshold t is the largest number of parties which may be corrupted without revealing the key,
+// and t+1 parties are required to produce a signature. The result is always valid for n parties,
+// that is 0 ⩽ t ⩽ n-1.
+func RecommendThreshold(n int, faultModel string) (uint32, error) {
+	if n < 1 || int64(n) > math.MaxUint32 {
+		return 0, fmt.Errorf("party: invalid number of parties %d", n)
+	}
+	var t int
+	switch faultModel {
+	case FaultModelHonestMajority:
+		t = n / 2
+	case FaultModelBFT:
+		t = 2 * n / 3
+	case FaultModelAll:
+		t = n - 1
+	default:
+		return 0, fmt.Errorf("party: unknown fault model %q", faultModel)
+	}
+	// ⌊n/2⌋ and ⌊2n/3⌋ are already at most n-1 for n ⩾ 1, this is only a safeguard
+	if t > n-1 {
+		return 0, errors.New("party: recommended threshold is invalid")
+	}
+	return uint32(t), nil
+}
@@ -0,0 +1,47 @@
+package party_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+func TestRecommendThreshold(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 4, 5, 7, 10, 100} {
+		for model, expected := range map[string]int{
+			party.FaultModelHonestMajority: n / 2,
+			party.FaultModelBFT:            2 * n / 3,
+			party.FaultModelAll:            n - 1,
+		} {
+			threshold, err := party.RecommendThreshold(n, model)
+			require.NoError(t, err)
+			assert.EqualValues(t, expected, threshold, "n = %d, model %s", n, model)
+			assert.Less(t, int(threshold), n, "threshold should be valid")
+		}
+	}
+
+	// spot checks of the number of signers t+1
+	for _, c := range []struct {
+		n       int
+		model   string
+		signers int
+	}{
+		{3, party.FaultModelHonestMajority, 2},
+		{4, party.FaultModelHonestMajority, 3},
+		{5, party.FaultModelHonestMajority, 3},
+		{4, party.FaultModelBFT, 3},
+		{7, party.FaultModelBFT, 5},
+		{10, party.FaultModelBFT, 7},
+	} {
+		threshold, err := party.RecommendThreshold(c.n, c.model)
+		require.NoError(t, err)
+		assert.Equal(t, c.signers, int(threshold)+1, "n = %d, model %s", c.n, c.model)
+	}
+
+	_, err := party.RecommendThreshold(0, party.FaultModelBFT)
+	assert.Error(t, err)
+	_, err = party.RecommendThreshold(3, "unknown")
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package pool
 
 import (
+	"fmt"
 	"io"
 	"runtime"
 	"sync"
@@ -9,12 +10,14 @@ import (
 
 // searchAlone runs f, which may return nil, until count elements are found
 func searchAlone(f func() interface{}, count int) []interface{} {
+	g := func(int) interface{} { return f() }
 	results := make([]interface{}, count)
 	for i := 0; i < len(results); i++ {
 		results[i] = nil
-		for ; results[i] == nil; results[i] = f() {
+		for ; results[i] == nil; results[i] = call(g, 0) {
 		}
 	}
+	Repanic(results)
 	return results
 }
 
@@ -22,11 +25,53 @@ func searchAlone(f func() interface{}, count int) []interface{} {
 func parallelizeAlone(f func(int) interface{}, count int) []interface{} {
 	results := make([]interface{}, count)
 	for i := 0; i < len(results); i++ {
-		results[i] = f(i)
+		results[i] = call(f, i)
 	}
 	return results
 }
 
+// PanicError is returned by Parallelize in place of the result of f(Index), if that call panicked.
+//
+// Search panics with a PanicError if f panics.
+type PanicError struct {
+	// Index is the argument f was called with, or 0 for Search.
+	Index int
+	// Value is the value recovered from the panic.
+	Value interface{}
+}
+
+// Error implements error.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("pool: panic at index %d: %v", e.Index, e.Value)
+}
+
+// Repanic panics with the first *PanicError in results, if any.
+//
+// This raises a panic of a worker in the calling goroutine, for callers of Parallelize which can't return an error.
+func Repanic(results []interface{}) {
+	for _, result := range results {
+		if err, ok := result.(*PanicError); ok {
+			panic(err)
+		}
+	}
+}
+
+// Unwrap returns Value if it is an error, so that errors.Is and errors.As can be used on the cause of the panic.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// call returns f(i), or a *PanicError if f panics.
+func call(f func(int) interface{}, i int) (result interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = &PanicError{Index: i, Value: r}
+		}
+	}()
+	return f(i)
+}
+
 // command is used to trigger our latent workers to do something.
 //
 // The idea is that a worker is told to either calculate a function once,
@@ -48,17 +93,20 @@ type command struct {
 //
 // We need to keep searching for successful queries of f while *ctr > 0.
 // When we find a successful result, we decrement *ctr.
+// A panic of f is recovered, and stored as a result, which Search raises again.
 func workerSearch(results []interface{}, ctrChanged chan<- struct{}, f func(int) interface{}, ctr *int64) {
 	for atomic.LoadInt64(ctr) > 0 {
-		res := f(0)
+		res := call(f, 0)
 		if res == nil {
 			continue
 		}
+		// only the first count results are kept and signaled,
+		// so that ctrChanged never receives more than count values.
 		i := atomic.AddInt64(ctr, -1)
 		if i >= 0 {
 			results[i] = res
+			ctrChanged <- struct{}{}
 		}
-		ctrChanged <- struct{}{}
 	}
 }
 
@@ -68,7 +116,7 @@ func worker(commands <-chan command) {
 		if c.search {
 			workerSearch(c.results, c.ctrChanged, c.f, c.ctr)
 		} else {
-			c.results[c.i] = c.f(c.i)
+			c.results[c.i] = call(c.f, c.i)
 			atomic.AddInt64(c.ctr, -1)
 			c.ctrChanged <- struct{}{}
 		}
@@ -77,7 +125,7 @@ func worker(commands <-chan command) {
 
 // Pool represents a pool of workers, used for parallelizing functions.
 //
-// Functions needing a *Pool will work with a nil receiver, doing the equivalent
+// Functions needing a *Pool will work with a nil receiver, or a zero Pool, doing the equivalent
 // work on the current thread instead.
 //
 // By creating a pool, you avoid the overhead of spinning up goroutines for
@@ -114,9 +162,34 @@ func NewPool(count int) *Pool {
 	return &p
 }
 
+// NewForSigning creates a new pool suitable for a signing session with the given number of parties.
+//
+// Signing rounds parallelize over the other parties, so there is no benefit in having more workers than that.
+// The number of workers is also bounded by GOMAXPROCS.
+func NewForSigning(parties int) *Pool {
+	return NewPool(signingWorkers(parties, runtime.GOMAXPROCS(0)))
+}
+
+// signingWorkers returns the number of workers used by NewForSigning.
+func signingWorkers(parties, maxProcs int) int {
+	count := parties - 1
+	if count > maxProcs {
+		count = maxProcs
+	}
+	if count < 1 {
+		count = 1
+	}
+	return count
+}
+
+// serial returns true if the work should be done on the current thread.
+func (p *Pool) serial() bool {
+	return p == nil || p.workerCount <= 0 || p.commands == nil
+}
+
 // TearDown cleanly tears down a pool, closing channels, etc.
 func (p *Pool) TearDown() {
-	if p != nil {
+	if !p.serial() {
 		close(p.commands)
 	}
 }
@@ -127,15 +200,18 @@ func (p *Pool) TearDown() {
 // successful.
 //
 // The result will be an array containing the first count successes.
+// If f panics, Search panics with a *PanicError in the calling goroutine, instead of crashing a worker.
 func (p *Pool) Search(count int, f func() interface{}) []interface{} {
-	if p == nil {
+	if p.serial() {
 		return searchAlone(f, count)
 	}
 
 	results := make([]interface{}, count)
 
 	ctr := int64(count)
-	ctrChanged := make(chan struct{})
+	// workers signal at most count times, so they never block on ctrChanged,
+	// even after we stopped listening.
+	ctrChanged := make(chan struct{}, count)
 	cmd := command{
 		search:     true,
 		ctr:        &ctr,
@@ -144,33 +220,42 @@ func (p *Pool) Search(count int, f func() interface{}) []interface{} {
 		results:    results,
 	}
 	cmdI := 0
+	received := 0
 	for cmdI < p.workerCount {
 		select {
 		case p.commands <- cmd:
 			cmdI++
 		case <-ctrChanged:
+			received++
 		}
 	}
-	for atomic.LoadInt64(&ctr) > 0 {
+	// each signal is sent after the corresponding result is written
+	for ; received < count; received++ {
 		<-ctrChanged
 	}
 
+	Repanic(results)
 	return results
 }
 
 // Parallelize calls a function count times, passing in indices from 0..count-1.
 //
 // The result will be a slice containing [f(0), f(1), ..., f(count - 1)].
+// If f(i) panics, the panic is recovered, and the i-th entry is a *PanicError instead.
+// The other calls are not affected.
 func (p *Pool) Parallelize(count int, f func(int) interface{}) []interface{} {
-	if p == nil {
+	if p.serial() {
 		return parallelizeAlone(f, count)
 	}
 
 	results := make([]interface{}, count)
 
 	ctr := int64(count)
-	ctrChanged := make(chan struct{})
+	// each command signals exactly once, so workers never block on ctrChanged,
+	// even after we stopped listening.
+	ctrChanged := make(chan struct{}, count)
 	cmdI := 0
+	received := 0
 	for cmdI < count {
 		cmd := command{
 			search:     false,
@@ -187,9 +272,11 @@ func (p *Pool) Parallelize(count int, f func(int) interface{}) []interface{} {
 		case p.commands <- cmd:
 			cmdI++
 		case <-ctrChanged:
+			received++
 		}
 	}
-	for atomic.LoadInt64(&ctr) > 0 {
+	// each signal is sent after the corresponding result is written
+	for ; received < count; received++ {
 		<-ctrChanged
 	}
 
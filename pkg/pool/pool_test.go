@@ -0,0 +1,109 @@
+package pool
+
+import (
+	"testing"
+)
+
+func TestPool_Zero(t *testing.T) {
+	for _, p := range []*Pool{nil, {}} {
+		results := p.Parallelize(4, func(i int) interface{} { return i })
+		for i, r := range results {
+			if r.(int) != i {
+				t.Fatalf("expected %d, got %v", i, r)
+			}
+		}
+		if results := p.Search(3, func() interface{} { return true }); len(results) != 3 {
+			t.Fatalf("expected 3 results, got %d", len(results))
+		}
+		p.TearDown()
+	}
+}
+
+func TestPool_Repeated(t *testing.T) {
+	// workers must not get stuck signaling a caller who already returned
+	p := NewPool(1)
+	defer p.TearDown()
+	for n := 0; n < 1000; n++ {
+		results := p.Parallelize(3, func(i int) interface{} { return i })
+		for i, r := range results {
+			if r.(int) != i {
+				t.Fatalf("expected %d, got %v", i, r)
+			}
+		}
+		for _, r := range p.Search(2, func() interface{} { return true }) {
+			if r == nil {
+				t.Fatal("search returned a nil result")
+			}
+		}
+	}
+}
+
+func TestSigningWorkers(t *testing.T) {
+	tests := []struct {
+		parties, maxProcs, expected int
+	}{
+		{0, 4, 1},
+		{1, 4, 1},
+		{2, 4, 1},
+		{3, 4, 2},
+		{10, 4, 4},
+		{10, 1, 1},
+	}
+	for _, tt := range tests {
+		if got := signingWorkers(tt.parties, tt.maxProcs); got != tt.expected {
+			t.Errorf("signingWorkers(%d, %d) = %d, expected %d", tt.parties, tt.maxProcs, got, tt.expected)
+		}
+	}
+
+	p := NewForSigning(5)
+	defer p.TearDown()
+	if p.workerCount < 1 || p.workerCount > 4 {
+		t.Errorf("unexpected worker count %d", p.workerCount)
+	}
+}
+
+func TestPool_ParallelizePanic(t *testing.T) {
+	for _, pl := range []*Pool{nil, NewPool(2)} {
+		results := pl.Parallelize(8, func(i int) interface{} {
+			if i == 3 {
+				var m map[int]int
+				m[0] = 1
+			}
+			return i
+		})
+		for i, r := range results {
+			if i == 3 {
+				err, ok := r.(*PanicError)
+				if !ok {
+					t.Fatalf("expected a *PanicError, got %v", r)
+				}
+				if err.Index != 3 {
+					t.Errorf("expected index 3, got %d", err.Index)
+				}
+				continue
+			}
+			if r != i {
+				t.Errorf("expected %d, got %v", i, r)
+			}
+		}
+		pl.TearDown()
+	}
+}
+
+func TestPool_SearchPanic(t *testing.T) {
+	for _, pl := range []*Pool{nil, NewPool(2)} {
+		func() {
+			defer func() {
+				if _, ok := recover().(*PanicError); !ok {
+					t.Error("Search should panic with a *PanicError")
+				}
+			}()
+			pl.Search(2, func() interface{} {
+				var m map[int]int
+				m[0] = 1
+				return 1
+			})
+		}()
+		pl.TearDown()
+	}
+}
@@ -5,11 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/fxamacker/cbor/v2"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 )
 
 // StartFunc is function that creates the first round of a protocol.
@@ -43,25 +46,77 @@ type MultiHandler struct {
 	messages        map[round.Number]map[party.ID]*Message
 	broadcast       map[round.Number]map[party.ID]*Message
 	broadcastHashes map[round.Number][]byte
+	limiter         *rateLimiter
+	timer           *time.Timer
+	profile         *round.Profile
 	out             chan *Message
 	mtx             sync.Mutex
+
+	// pool is used to verify the messages of a round concurrently, in which case
+	// pending holds the messages of the current round which still need to be verified.
+	pool    *pool.Pool
+	pending map[party.ID]round.Message
 }
 
 // NewMultiHandler expects a StartFunc for the desired protocol. It returns a handler that the user can interact with.
 func NewMultiHandler(create StartFunc, sessionID []byte) (*MultiHandler, error) {
+	return NewMultiHandlerWithLimits(create, sessionID, Limits{})
+}
+
+// NewMultiHandlerWithLimits is the same as NewMultiHandler, but messages received from other parties
+// are first checked against the given Limits, and the handler aborts if a round exceeds its timeout.
+func NewMultiHandlerWithLimits(create StartFunc, sessionID []byte, limits Limits) (*MultiHandler, error) {
+	return newMultiHandler(create, sessionID, limits, nil, nil)
+}
+
+// NewParallelMultiHandler is the same as NewMultiHandlerWithLimits, but the messages of a round are verified
+// once all of them have been received, concurrently using pl. This speeds up rounds in which many parties
+// send zero-knowledge proofs, such as in signing, at the cost of detecting an invalid message only at the end of the round.
+//
+// If some messages are invalid, the protocol aborts with all their senders as culprits.
+//
+// pl must not be the pool given to the protocol, since the rounds may use it while verifying a message.
+func NewParallelMultiHandler(create StartFunc, sessionID []byte, limits Limits, pl *pool.Pool) (*MultiHandler, error) {
+	if pl == nil {
+		return nil, errors.New("protocol: NewParallelMultiHandler requires a pool")
+	}
+	return newMultiHandler(create, sessionID, limits, nil, pl)
+}
+
+// NewProfiledMultiHandler is the same as NewMultiHandlerWithLimits, but the handler also records
+// the time spent in each round and in the main steps of the protocol, which is returned by Profile.
+//
+// Profiling has a small overhead, and should only be enabled when tuning performance.
+func NewProfiledMultiHandler(create StartFunc, sessionID []byte, limits Limits) (*MultiHandler, error) {
+	return newMultiHandler(create, sessionID, limits, round.NewProfile(), nil)
+}
+
+func newMultiHandler(create StartFunc, sessionID []byte, limits Limits, profile *round.Profile, pl *pool.Pool) (*MultiHandler, error) {
 	r, err := create(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("protocol: failed to create round: %w", err)
 	}
+	if profile != nil {
+		if p, ok := r.(interface{ SetProfile(*round.Profile) }); ok {
+			p.SetProfile(profile)
+		}
+	}
 	h := &MultiHandler{
 		currentRound:    r,
 		rounds:          map[round.Number]round.Session{r.Number(): r},
 		messages:        newQueue(r.OtherPartyIDs(), r.FinalRoundNumber()),
 		broadcast:       newQueue(r.OtherPartyIDs(), r.FinalRoundNumber()),
 		broadcastHashes: map[round.Number][]byte{},
+		limiter:         newRateLimiter(limits),
+		pool:            pl,
+		pending:         map[party.ID]round.Message{},
+		profile:         profile,
 		out:             make(chan *Message, 2*r.N()),
 	}
+	// the timer of a round may fire before we return
+	h.mtx.Lock()
 	h.finalize()
+	h.mtx.Unlock()
 	return h, nil
 }
 
@@ -78,9 +133,51 @@ func (h *MultiHandler) Result() (interface{}, error) {
 	return nil, errors.New("protocol: not finished")
 }
 
+// Breakdown describes the time spent during a protocol execution.
+type Breakdown = round.Breakdown
+
+// Timing is the time spent in a round, or in a step of a round.
+type Timing = round.Timing
+
+// Profile returns the time spent in each round, and in the main steps of each round,
+// such as generating proofs or Paillier keys. Once the protocol is finished, the durations
+// of the rounds add up to the total.
+//
+// It returns nil if the handler was not created with NewProfiledMultiHandler.
+func (h *MultiHandler) Profile() *Breakdown {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return h.profile.Breakdown()
+}
+
+// CurrentRound returns the number of the round whose messages the handler is waiting for,
+// starting from 2 since the first round needs no messages, or 0 once the protocol has finished or aborted.
+//
+// While the protocol is running, CurrentRound() - 1 rounds out of TotalRounds() are complete.
+func (h *MultiHandler) CurrentRound() int {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.err != nil {
+		return 0
+	}
+	return int(h.currentRound.Number())
+}
+
+// TotalRounds returns the number of rounds of the protocol.
+func (h *MultiHandler) TotalRounds() int {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return int(h.currentRound.FinalRoundNumber())
+}
+
 // Listen returns a channel with outgoing messages that must be sent to other parties.
 // The message received should be _reliably_ broadcast if msg.Broadcast is true.
-// The channel is closed when either an error occurs or the protocol detects an error.
+//
+// The channel is owned by the handler, and must not be closed by the caller.
+// The handler closes it exactly once, when the final round completes, when the protocol aborts, or on Stop,
+// after the last message, which alerts the other parties in case of an abort.
+// Messages sent before that remain readable, so a caller draining the channel until it is closed
+// receives every message and then exits, whichever way the protocol ends.
 func (h *MultiHandler) Listen() <-chan *Message {
 	h.mtx.Lock()
 	defer h.mtx.Unlock()
@@ -89,42 +186,22 @@ func (h *MultiHandler) Listen() <-chan *Message {
 
 // CanAccept returns true if the message is designated for this protocol protocol execution.
 func (h *MultiHandler) CanAccept(msg *Message) bool {
+	return h.CheckMessage(msg) == nil
+}
+
+// CheckMessage returns the reason why msg cannot be accepted, or nil if CanAccept would return true.
+func (h *MultiHandler) CheckMessage(msg *Message) error {
 	r := h.currentRound
 	if msg == nil {
-		return false
-	}
-	// are we the intended recipient
-	if !msg.IsFor(r.SelfID()) {
-		return false
-	}
-	// is the protocol ID correct
-	if msg.Protocol != r.ProtocolID() {
-		return false
-	}
-	// check for same SSID
-	if !bytes.Equal(msg.SSID, r.SSID()) {
-		return false
-	}
-	// do we know the sender
-	if !r.PartyIDs().Contains(msg.From) {
-		return false
+		return ErrNoData
 	}
-
-	// data is cannot be nil
-	if msg.Data == nil {
-		return false
-	}
-
-	// check if message for unexpected round
-	if msg.RoundNumber > r.FinalRoundNumber() {
-		return false
+	if err := msg.checkSession(r); err != nil {
+		return err
 	}
-
 	if msg.RoundNumber < r.Number() && msg.RoundNumber > 0 {
-		return false
+		return ErrUnexpectedRound
 	}
-
-	return true
+	return nil
 }
 
 // Accept tries to process the given message. If an abort occurs, the channel returned by Listen() is closed,
@@ -136,7 +213,12 @@ func (h *MultiHandler) Accept(msg *Message) {
 	defer h.mtx.Unlock()
 
 	// exit early if the message is bad, or if we are already done
-	if !h.CanAccept(msg) || h.err != nil || h.result != nil || h.duplicate(msg) {
+	if !h.CanAccept(msg) || h.err != nil || h.result != nil {
+		return
+	}
+
+	// drop messages from parties exceeding the limits, before doing any more work
+	if !h.limiter.allow(msg) || h.duplicate(msg) {
 		return
 	}
 
@@ -179,7 +261,10 @@ func (h *MultiHandler) verifyBroadcastMessage(msg *Message) error {
 	}
 
 	// store the broadcast message for this round
-	if err = r.(round.BroadcastRound).StoreBroadcastMessage(roundMsg); err != nil {
+	done := h.profile.StartPhase("verify")
+	err = r.(round.BroadcastRound).StoreBroadcastMessage(roundMsg)
+	done()
+	if err != nil {
 		return fmt.Errorf("round %d: %w", r.Number(), err)
 	}
 
@@ -218,6 +303,15 @@ func (h *MultiHandler) verifyMessage(msg *Message) error {
 		return err
 	}
 
+	// with a pool, the message is verified together with the others of the round, see verifyPending
+	if h.pool != nil {
+		h.pending[msg.From] = roundMsg
+		return nil
+	}
+
+	done := h.profile.StartPhase("verify")
+	defer done()
+
 	// verify message for round
 	if err = r.VerifyMessage(roundMsg); err != nil {
 		return fmt.Errorf("round %d: %w", r.Number(), err)
@@ -239,10 +333,22 @@ func (h *MultiHandler) finalize() {
 		h.abort(errors.New("broadcast verification failed"))
 		return
 	}
+	if culprits, err := h.verifyPending(); err != nil {
+		h.abort(err, culprits...)
+		return
+	}
+
+	// abort instead of finalizing the round with the output of a failing generator
+	if err := sample.HealthCheck(); err != nil {
+		h.abort(err, h.currentRound.SelfID())
+		return
+	}
 
 	out := make(chan *round.Message, h.currentRound.N()+1)
 	// since we pass a large enough channel, we should never get an error
-	r, err := h.currentRound.Finalize(out)
+	done := h.profile.StartPhase("finalize")
+	r, err := finalizeRound(h.currentRound, out)
+	done()
 	close(out)
 	// either we got an error due to some problem on our end (sampling etc)
 	// or the new round is nil (should not happen)
@@ -270,6 +376,10 @@ func (h *MultiHandler) finalize() {
 		if msg.Broadcast {
 			h.store(msg)
 		}
+		// in a session with a single party, there is no one to send the message to
+		if len(r.OtherPartyIDs()) == 0 {
+			continue
+		}
 		h.out <- msg
 	}
 
@@ -280,6 +390,9 @@ func (h *MultiHandler) finalize() {
 	}
 	h.rounds[roundNumber] = r
 	h.currentRound = r
+	if roundNumber != 0 {
+		h.profile.NextRound(roundNumber)
+	}
 
 	// either we get the current round, the next one, or one of the two final ones
 	switch R := r.(type) {
@@ -294,6 +407,7 @@ func (h *MultiHandler) finalize() {
 		return
 	default:
 	}
+	h.startTimer(roundNumber)
 
 	if _, ok := r.(round.BroadcastRound); ok {
 		// handle queued broadcast messages, which will then check the subsequent normal message
@@ -325,7 +439,78 @@ func (h *MultiHandler) finalize() {
 	h.finalize()
 }
 
+// finalizeRound returns r.Finalize(out).
+//
+// The sampling functions panic if the RandSource fails during Finalize, in which case this returns
+// the error wrapping sample.ErrUnhealthy, so that the protocol aborts instead of crashing. Other panics are not recovered.
+func finalizeRound(r round.Session, out chan<- *round.Message) (next round.Session, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			e, ok := recovered.(error)
+			if !ok || !errors.Is(e, sample.ErrUnhealthy) {
+				panic(recovered)
+			}
+			next, err = nil, e
+		}
+	}()
+	return r.Finalize(out)
+}
+
+// verifyPending verifies the messages of the current round deferred by verifyMessage concurrently,
+// and then stores them in the round in the order of their senders.
+//
+// If some messages are invalid, it returns the error of the first of them, with all their senders as culprits.
+func (h *MultiHandler) verifyPending() ([]party.ID, error) {
+	if len(h.pending) == 0 {
+		return nil, nil
+	}
+	r := h.currentRound
+	pending := h.pending
+	h.pending = map[party.ID]round.Message{}
+	ids := make([]party.ID, 0, len(pending))
+	for id := range pending {
+		ids = append(ids, id)
+	}
+	senders := party.NewIDSlice(ids)
+
+	done := h.profile.StartPhase("verify")
+	defer done()
+
+	// VerifyMessage does not modify the round, and can therefore run concurrently
+	results := h.pool.Parallelize(len(senders), func(i int) interface{} {
+		return r.VerifyMessage(pending[senders[i]])
+	})
+	var (
+		culprits []party.ID
+		firstErr error
+	)
+	for i, result := range results {
+		if err, ok := result.(error); ok && err != nil {
+			culprits = append(culprits, senders[i])
+			if firstErr == nil {
+				firstErr = fmt.Errorf("round %d: party %s: %w", r.Number(), senders[i], err)
+			}
+		}
+	}
+	if firstErr != nil {
+		return culprits, firstErr
+	}
+
+	for _, id := range senders {
+		if err := r.StoreMessage(pending[id]); err != nil {
+			return []party.ID{id}, fmt.Errorf("round %d: %w", r.Number(), err)
+		}
+	}
+	return nil, nil
+}
+
+// abort ends the protocol with err, or with the result if err is nil, and closes h.out.
+// It must be called once, since the handler stops processing messages once h.err or h.result is set.
 func (h *MultiHandler) abort(err error, culprits ...party.ID) {
+	h.profile.Finish()
+	if h.timer != nil {
+		h.timer.Stop()
+	}
 	if err != nil {
 		h.err = &Error{
 			Culprits: culprits,
@@ -345,13 +530,60 @@ func (h *MultiHandler) abort(err error, culprits ...party.ID) {
 	close(h.out)
 }
 
+// startTimer replaces the timer of the previous round by one which aborts the protocol
+// if the messages of round number are not all received within its timeout.
+func (h *MultiHandler) startTimer(number round.Number) {
+	if h.timer != nil {
+		h.timer.Stop()
+	}
+	timeout := h.limiter.limits.roundTimeout(number)
+	if timeout <= 0 {
+		return
+	}
+	h.timer = time.AfterFunc(timeout, func() {
+		h.mtx.Lock()
+		defer h.mtx.Unlock()
+		// the timer may fire while the round is being completed
+		if h.err != nil || h.result != nil || h.currentRound.Number() != number {
+			return
+		}
+		h.abort(fmt.Errorf("round %d: timed out after %s", number, timeout), h.missing()...)
+	})
+}
+
+// missing returns the parties from whom a message of the current round has not been received yet.
+func (h *MultiHandler) missing() []party.ID {
+	r := h.currentRound
+	number := r.Number()
+	_, broadcast := r.(round.BroadcastRound)
+	var ids []party.ID
+	for _, id := range r.OtherPartyIDs() {
+		if (broadcast && h.broadcast[number] != nil && h.broadcast[number][id] == nil) ||
+			(expectsNormalMessage(r) && h.messages[number] != nil && h.messages[number][id] == nil) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 // Stop cancels the current execution of the protocol, and alerts the other users.
+// Afterwards, the handler sends no more messages and ignores the ones it receives,
+// and Result returns an error. It does nothing if the protocol has already finished.
 func (h *MultiHandler) Stop() {
-	if h.err != nil || h.result != nil {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.err == nil && h.result == nil {
 		h.abort(errors.New("aborted by user"), h.currentRound.SelfID())
 	}
 }
 
+// Flagged returns the sorted list of parties whose messages were dropped for exceeding the handler's Limits.
+func (h *MultiHandler) Flagged() party.IDSlice {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return h.limiter.flaggedIDs()
+}
+
 func expectsNormalMessage(r round.Session) bool {
 	return r.MessageContent() != nil
 }
@@ -445,8 +677,13 @@ func getRoundMessage(msg *Message, r round.Session) (round.Message, error) {
 		content = r.MessageContent()
 	}
 
+	// reject malformed messages before unmarshalling them
+	if err := checkSchema(msg, r); err != nil {
+		return round.Message{}, err
+	}
+
 	// unmarshal message
-	if err := cbor.Unmarshal(msg.Data, content); err != nil {
+	if err := unmarshalContent(msg.Data, content); err != nil {
 		return round.Message{}, fmt.Errorf("failed to unmarshal: %w", err)
 	}
 	roundMsg := round.Message{
@@ -458,6 +695,28 @@ func getRoundMessage(msg *Message, r round.Session) (round.Message, error) {
 	return roundMsg, nil
 }
 
+// checkSchema checks the content of msg against the Schema declared by r, if it implements round.SchemaRound.
+func checkSchema(msg *Message, r round.Session) error {
+	s, ok := r.(round.SchemaRound)
+	if !ok {
+		return nil
+	}
+	return s.MessageSchema(msg.Broadcast).Check(msg.Data)
+}
+
+// unmarshalContent decodes data into content.
+//
+// Some malformed inputs make the decoder panic, for instance a null value for a field holding a curve.Point,
+// so the panic is recovered and returned as an error instead.
+func unmarshalContent(data []byte, content round.Content) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid content: %v", r)
+		}
+	}()
+	return cbor.Unmarshal(data, content)
+}
+
 // checkBroadcastHash is run after receivedAll() and checks whether all provided verification hashes are correct.
 func (h *MultiHandler) checkBroadcastHash() bool {
 	number := h.currentRound.Number()
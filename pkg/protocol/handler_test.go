@@ -0,0 +1,323 @@
+package protocol_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/example"
+)
+
+// drain returns all messages currently queued in the handler's out channel.
+func drain(h protocol.Handler) []*protocol.Message {
+	var msgs []*protocol.Message
+	for {
+		select {
+		case msg, ok := <-h.Listen():
+			if !ok {
+				return msgs
+			}
+			msgs = append(msgs, msg)
+		default:
+			return msgs
+		}
+	}
+}
+
+func TestMultiHandlerStop(t *testing.T) {
+	partyIDs := test.PartyIDs(2)
+	h, err := protocol.NewMultiHandler(example.StartXOR(partyIDs[0], partyIDs), nil)
+	require.NoError(t, err)
+	drain(h)
+
+	h.Stop()
+	msgs := drain(h)
+	require.Len(t, msgs, 1, "the other parties should be alerted")
+	assert.Zero(t, msgs[0].RoundNumber)
+	_, ok := <-h.Listen()
+	assert.False(t, ok, "the handler should not send any more messages")
+	_, err = h.Result()
+	assert.ErrorContains(t, err, "aborted by user")
+
+	// stopping twice is harmless
+	h.Stop()
+}
+
+// forward delivers the messages of handlers[from] to the other handlers until its channel is closed.
+func forward(wg *sync.WaitGroup, from party.ID, handlers map[party.ID]protocol.Handler) {
+	defer wg.Done()
+	for msg := range handlers[from].Listen() {
+		for id, h := range handlers {
+			if id != from && msg.IsFor(id) {
+				h.Accept(msg)
+			}
+		}
+	}
+}
+
+// waitOrFail fails the test if the goroutines of wg do not all exit.
+func waitOrFail(t *testing.T, wg *sync.WaitGroup) {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("a goroutine draining the out channel did not exit")
+	}
+}
+
+func TestHandlerClosesOut(t *testing.T) {
+	partyIDs := test.PartyIDs(2)
+	newHandlers := map[string]func() map[party.ID]protocol.Handler{
+		"multi": func() map[party.ID]protocol.Handler {
+			handlers := make(map[party.ID]protocol.Handler, len(partyIDs))
+			for _, id := range partyIDs {
+				h, err := protocol.NewMultiHandler(example.StartXOR(id, partyIDs), nil)
+				require.NoError(t, err)
+				handlers[id] = h
+			}
+			return handlers
+		},
+		"two party": func() map[party.ID]protocol.Handler {
+			handlers := make(map[party.ID]protocol.Handler, len(partyIDs))
+			for i, id := range partyIDs {
+				h, err := protocol.NewTwoPartyHandler(example.StartXOR(id, partyIDs), nil, i == 0)
+				require.NoError(t, err)
+				handlers[id] = h
+			}
+			return handlers
+		},
+	}
+	for name, newHandlers := range newHandlers {
+		t.Run(name+"/success", func(t *testing.T) {
+			handlers := newHandlers()
+			var wg sync.WaitGroup
+			wg.Add(len(partyIDs))
+			for _, id := range partyIDs {
+				go forward(&wg, id, handlers)
+			}
+			waitOrFail(t, &wg)
+			for _, h := range handlers {
+				_, err := h.Result()
+				assert.NoError(t, err)
+				// stopping a finished protocol does not close the channel again
+				h.Stop()
+			}
+		})
+		t.Run(name+"/abort", func(t *testing.T) {
+			handlers := newHandlers()
+			handlers[partyIDs[1]].Stop()
+			var wg sync.WaitGroup
+			wg.Add(len(partyIDs))
+			for _, id := range partyIDs {
+				go forward(&wg, id, handlers)
+			}
+			// the other party closes its channel too, either on the abort message,
+			// or with a result if the message queued before Stop reached it first
+			waitOrFail(t, &wg)
+			_, err := handlers[partyIDs[1]].Result()
+			assert.ErrorContains(t, err, "aborted by user")
+			for _, h := range handlers {
+				h.Stop()
+			}
+		})
+	}
+}
+
+func TestCurrentRoundAbort(t *testing.T) {
+	partyIDs := test.PartyIDs(2)
+	multi, err := protocol.NewMultiHandler(example.StartXOR(partyIDs[0], partyIDs), nil)
+	require.NoError(t, err)
+	twoParty, err := protocol.NewTwoPartyHandler(example.StartXOR(partyIDs[0], partyIDs), nil, true)
+	require.NoError(t, err)
+	for name, h := range map[string]interface {
+		protocol.Handler
+		CurrentRound() int
+		Stop()
+	}{"multi": multi, "two party": twoParty} {
+		assert.Equal(t, 2, h.CurrentRound(), name)
+		h.Stop()
+		assert.Zero(t, h.CurrentRound(), "%s: an aborted protocol has no current round", name)
+	}
+}
+
+func TestMultiHandlerSingleParty(t *testing.T) {
+	partyIDs := test.PartyIDs(1)
+	h, err := protocol.NewMultiHandler(example.StartXOR(partyIDs[0], partyIDs), nil)
+	require.NoError(t, err, "a session of a single party should not block on its own messages")
+	assert.Empty(t, drain(h), "there is no one to send messages to")
+	_, err = h.Result()
+	assert.NoError(t, err)
+}
+
+func TestMultiHandlerLimits(t *testing.T) {
+	partyIDs := test.PartyIDs(3)
+	self, honest, flooder := partyIDs[0], partyIDs[1], partyIDs[2]
+
+	h, err := protocol.NewMultiHandlerWithLimits(example.StartXOR(self, partyIDs), nil, protocol.Limits{
+		MaxMessagesPerRound: 1,
+		MaxMessageSize:      64,
+	})
+	require.NoError(t, err)
+
+	outgoing := make(map[party.ID]*protocol.Message, len(partyIDs))
+	for _, id := range []party.ID{honest, flooder} {
+		other, err := protocol.NewMultiHandler(example.StartXOR(id, partyIDs), nil)
+		require.NoError(t, err)
+		msgs := drain(other)
+		require.Len(t, msgs, 1)
+		outgoing[id] = msgs[0]
+	}
+
+	// the flooder sends an oversized message, followed by too many copies of its valid message.
+	oversized := *outgoing[flooder]
+	oversized.Data = make([]byte, 65)
+	h.Accept(&oversized)
+	assert.Equal(t, party.IDSlice{flooder}, h.Flagged())
+
+	for i := 0; i < 3; i++ {
+		m := *outgoing[flooder]
+		h.Accept(&m)
+	}
+	assert.Equal(t, party.IDSlice{flooder}, h.Flagged())
+
+	// the honest party is unaffected
+	h.Accept(outgoing[honest])
+	assert.Equal(t, party.IDSlice{flooder}, h.Flagged())
+
+	// only the excess messages were dropped, so the protocol still completes
+	_, err = h.Result()
+	assert.NoError(t, err)
+}
+
+func TestMultiHandlerLimitsHonest(t *testing.T) {
+	partyIDs := test.PartyIDs(3)
+
+	handlers := make(map[party.ID]*protocol.MultiHandler, len(partyIDs))
+	for _, id := range partyIDs {
+		h, err := protocol.NewMultiHandlerWithLimits(example.StartXOR(id, partyIDs), nil, protocol.Limits{
+			MaxMessagesPerRound: 1,
+			MaxMessageSize:      64,
+		})
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+
+	var msgs []*protocol.Message
+	for _, id := range partyIDs {
+		msgs = append(msgs, drain(handlers[id])...)
+	}
+	for _, msg := range msgs {
+		for id, h := range handlers {
+			if msg.IsFor(id) {
+				h.Accept(msg)
+			}
+		}
+	}
+
+	for _, h := range handlers {
+		_, err := h.Result()
+		assert.NoError(t, err)
+		assert.Empty(t, h.Flagged())
+	}
+}
+
+func TestMultiHandlerCheckMessage(t *testing.T) {
+	partyIDs := test.PartyIDs(3)
+	self, other := partyIDs[0], partyIDs[1]
+
+	h, err := protocol.NewMultiHandler(example.StartXOR(self, partyIDs), nil)
+	require.NoError(t, err)
+	sender, err := protocol.NewMultiHandler(example.StartXOR(other, partyIDs), nil)
+	require.NoError(t, err)
+	msgs := drain(sender)
+	require.Len(t, msgs, 1)
+	valid := msgs[0]
+	require.NoError(t, valid.Validate())
+	require.NoError(t, h.CheckMessage(valid))
+
+	tests := []struct {
+		name   string
+		modify func(*protocol.Message)
+		err    error
+	}{
+		{"no sender", func(m *protocol.Message) { m.From = "" }, protocol.ErrNoSender},
+		{"sent to sender", func(m *protocol.Message) { m.To = m.From }, protocol.ErrSentToSelf},
+		{"broadcast with recipient", func(m *protocol.Message) { m.Broadcast, m.To = true, self }, protocol.ErrBroadcastTo},
+		{"no data", func(m *protocol.Message) { m.Data = nil }, protocol.ErrNoData},
+		{"wrong recipient", func(m *protocol.Message) { m.To = partyIDs[2] }, protocol.ErrWrongRecipient},
+		{"wrong protocol", func(m *protocol.Message) { m.Protocol = "other" }, protocol.ErrWrongProtocol},
+		{"wrong session", func(m *protocol.Message) { m.SSID = append([]byte{0}, m.SSID...) }, protocol.ErrWrongSession},
+		{"unknown sender", func(m *protocol.Message) { m.From = "z" }, protocol.ErrUnknownSender},
+		{"round after the last", func(m *protocol.Message) { m.RoundNumber = 3 }, protocol.ErrUnexpectedRound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := *valid
+			tt.modify(&m)
+			assert.ErrorIs(t, h.CheckMessage(&m), tt.err)
+			assert.False(t, h.CanAccept(&m))
+		})
+	}
+
+	// the first round is finalized on creation, so h only accepts messages for round 2
+	m := *valid
+	m.RoundNumber = 1
+	assert.ErrorIs(t, h.CheckMessage(&m), protocol.ErrUnexpectedRound)
+}
+
+func TestMultiHandlerRoundTimeout(t *testing.T) {
+	partyIDs := test.PartyIDs(3)
+	self, late := partyIDs[0], partyIDs[2]
+
+	start := time.Now()
+	h, err := protocol.NewMultiHandlerWithLimits(example.StartXOR(self, partyIDs), nil, protocol.Limits{
+		RoundTimeout: 50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	other, err := protocol.NewMultiHandler(example.StartXOR(partyIDs[1], partyIDs), nil)
+	require.NoError(t, err)
+	for _, msg := range drain(other) {
+		h.Accept(msg)
+	}
+
+	// the last party never sends its message
+	for range h.Listen() {
+	}
+	assert.Less(t, time.Since(start), 5*time.Second, "a fast round should fail fast")
+	_, err = h.Result()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "round 2: timed out after 50ms")
+	var protocolErr protocol.Error
+	require.ErrorAs(t, err, &protocolErr)
+	assert.Equal(t, []party.ID{late}, protocolErr.Culprits)
+}
+
+func TestMultiHandlerRoundTimeoutOverride(t *testing.T) {
+	partyIDs := test.PartyIDs(2)
+	self, slow := partyIDs[0], partyIDs[1]
+
+	// round 2 is given more time than the default
+	h, err := protocol.NewMultiHandlerWithLimits(example.StartXOR(self, partyIDs), nil, protocol.Limits{
+		RoundTimeout:  50 * time.Millisecond,
+		RoundTimeouts: map[int]time.Duration{2: 10 * time.Second},
+	})
+	require.NoError(t, err)
+	other, err := protocol.NewMultiHandler(example.StartXOR(slow, partyIDs), nil)
+	require.NoError(t, err)
+
+	time.Sleep(200 * time.Millisecond)
+	for _, msg := range drain(other) {
+		h.Accept(msg)
+	}
+	_, err = h.Result()
+	assert.NoError(t, err, "a slow round should be given its own budget")
+}
@@ -0,0 +1,96 @@
+package protocol
+
+import (
+	"time"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// Limits restricts the amount of work a MultiHandler is willing to perform on behalf of a single party.
+//
+// The checks are applied to incoming messages before they are unmarshalled or verified by the current round,
+// so that a misbehaving party cannot force us to perform expensive operations such as ZK proof verification.
+// Messages exceeding the limits are dropped, and the sender is flagged (see MultiHandler.Flagged).
+// The protocol itself is not aborted, and messages from other parties are processed as usual.
+//
+// Limits may also bound the time the handler waits for the messages of each round.
+// Unlike the checks above, an expired timeout aborts the protocol, with the parties
+// whose messages are missing as culprits.
+//
+// A value of 0 for any field disables the corresponding check.
+type Limits struct {
+	// MaxMessagesPerRound is the maximum number of messages accepted from a single party for a given round.
+	// Note that an honest party may send both a broadcast and a p2p message in the same round,
+	// so this value should be at least 2 for most protocols.
+	MaxMessagesPerRound int
+	// MaxMessageSize is the maximum length in bytes of the Data field of a message.
+	MaxMessageSize int
+	// RoundTimeout is the maximum time the handler waits for the messages of a round, starting when it enters that round.
+	RoundTimeout time.Duration
+	// RoundTimeouts overrides RoundTimeout for the given round numbers, so that a round in which the other parties
+	// perform expensive operations, such as generating Paillier primes, can be given more time than a simple broadcast.
+	// If it is set while RoundTimeout is 0, the other rounds use DefaultRoundTimeout.
+	RoundTimeouts map[int]time.Duration
+}
+
+// DefaultRoundTimeout is the timeout of the rounds missing from Limits.RoundTimeouts, when Limits.RoundTimeout is 0.
+const DefaultRoundTimeout = time.Minute
+
+// roundTimeout returns the time to wait for the messages of round number, or 0 if there is no timeout.
+func (l Limits) roundTimeout(number round.Number) time.Duration {
+	if timeout, ok := l.RoundTimeouts[int(number)]; ok {
+		return timeout
+	}
+	if l.RoundTimeout == 0 && len(l.RoundTimeouts) > 0 {
+		return DefaultRoundTimeout
+	}
+	return l.RoundTimeout
+}
+
+// rateLimiter keeps track of the number of messages received from each party in each round.
+type rateLimiter struct {
+	limits   Limits
+	received map[round.Number]map[party.ID]int
+	flagged  map[party.ID]struct{}
+}
+
+func newRateLimiter(limits Limits) *rateLimiter {
+	return &rateLimiter{
+		limits:   limits,
+		received: map[round.Number]map[party.ID]int{},
+		flagged:  map[party.ID]struct{}{},
+	}
+}
+
+// allow records msg and returns true if it satisfies the limits.
+// If it does not, the sender is flagged.
+func (l *rateLimiter) allow(msg *Message) bool {
+	if max := l.limits.MaxMessageSize; max > 0 && len(msg.Data) > max {
+		l.flagged[msg.From] = struct{}{}
+		return false
+	}
+
+	if max := l.limits.MaxMessagesPerRound; max > 0 {
+		q, ok := l.received[msg.RoundNumber]
+		if !ok {
+			q = map[party.ID]int{}
+			l.received[msg.RoundNumber] = q
+		}
+		q[msg.From]++
+		if q[msg.From] > max {
+			l.flagged[msg.From] = struct{}{}
+			return false
+		}
+	}
+	return true
+}
+
+// flaggedIDs returns a sorted slice of all parties who exceeded the limits.
+func (l *rateLimiter) flaggedIDs() party.IDSlice {
+	ids := make([]party.ID, 0, len(l.flagged))
+	for id := range l.flagged {
+		ids = append(ids, id)
+	}
+	return party.NewIDSlice(ids)
+}
@@ -1,6 +1,8 @@
 package protocol
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 
 	"github.com/fxamacker/cbor/v2"
@@ -29,6 +31,66 @@ type Message struct {
 	BroadcastVerification []byte
 }
 
+// Errors returned by Message.Validate and CheckMessage, when the routing metadata of a message is invalid.
+var (
+	ErrNoSender        = errors.New("protocol: message has no sender")
+	ErrSentToSelf      = errors.New("protocol: message is addressed to its sender")
+	ErrBroadcastTo     = errors.New("protocol: broadcast message has a recipient")
+	ErrNoData          = errors.New("protocol: message has no data")
+	ErrWrongRecipient  = errors.New("protocol: message is not addressed to this party")
+	ErrWrongProtocol   = errors.New("protocol: message belongs to a different protocol")
+	ErrWrongSession    = errors.New("protocol: message belongs to a different session")
+	ErrUnknownSender   = errors.New("protocol: message sender is not a participant")
+	ErrUnexpectedRound = errors.New("protocol: message is for an unexpected round")
+)
+
+// Validate checks that the routing metadata of m is consistent, without any knowledge of the protocol.
+//
+// This can be used by a relay, which forwards messages according to From, To and Broadcast.
+func (m *Message) Validate() error {
+	if m.From == "" {
+		return ErrNoSender
+	}
+	if m.To == m.From {
+		return ErrSentToSelf
+	}
+	if m.Broadcast && m.To != "" {
+		return ErrBroadcastTo
+	}
+	if m.Data == nil {
+		return ErrNoData
+	}
+	return nil
+}
+
+// checkSession returns an error if m cannot be delivered to r, according to its routing metadata.
+func (m *Message) checkSession(r round.Session) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	// are we the intended recipient
+	if !m.IsFor(r.SelfID()) {
+		return ErrWrongRecipient
+	}
+	// is the protocol ID correct
+	if m.Protocol != r.ProtocolID() {
+		return ErrWrongProtocol
+	}
+	// check for same SSID
+	if !bytes.Equal(m.SSID, r.SSID()) {
+		return ErrWrongSession
+	}
+	// do we know the sender
+	if !r.PartyIDs().Contains(m.From) {
+		return ErrUnknownSender
+	}
+	// check if message for unexpected round
+	if m.RoundNumber > r.FinalRoundNumber() {
+		return ErrUnexpectedRound
+	}
+	return nil
+}
+
 // String implements fmt.Stringer.
 func (m Message) String() string {
 	return fmt.Sprintf("message: round %d, from: %s, to %v, protocol: %s", m.RoundNumber, m.From, m.To, m.Protocol)
@@ -97,7 +159,7 @@ func (m *Message) MarshalBinary() ([]byte, error) {
 func (m *Message) UnmarshalBinary(data []byte) error {
 	deserialized := m.toMarshallable()
 	if err := cbor.Unmarshal(data, deserialized); err != nil {
-		return nil
+		return err
 	}
 	m.SSID = deserialized.SSID
 	m.From = deserialized.From
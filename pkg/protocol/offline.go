@@ -0,0 +1,165 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+)
+
+// SessionCodec saves and restores the rounds of a single protocol execution,
+// so that a party can run it offline, for instance on an air-gapped machine.
+type SessionCodec interface {
+	// Marshal serializes session, which is the current round of the protocol, or its output.
+	Marshal(session round.Session) ([]byte, error)
+	// Unmarshal restores a session serialized by Marshal.
+	Unmarshal(data []byte) (round.Session, error)
+}
+
+// offlineState is everything a party needs to resume a protocol between two rounds.
+type offlineState struct {
+	// Session is the current round, serialized by the SessionCodec.
+	Session []byte
+	// BroadcastHash is the hash of the messages broadcast in the previous round,
+	// which is checked against incoming messages and included in outgoing ones.
+	BroadcastHash []byte
+	// Messages are the messages stored for the current and later rounds,
+	// including this party's own broadcast message.
+	Messages []*Message
+}
+
+// StartOffline starts a protocol for a party which is driven entirely through bytes,
+// without a live connection to the other parties.
+//
+// It returns the serialized state of the party, and the messages it sends in the first round.
+// The state must then be given to ProcessRound, together with the messages received from the other parties.
+func StartOffline(create StartFunc, sessionID []byte, codec SessionCodec) (state []byte, outbound []*Message, err error) {
+	h, err := NewMultiHandler(create, sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return saveOffline(h, codec)
+}
+
+// ProcessRound restores the party saved in state, delivers the inbound messages, and returns its new state,
+// together with the messages it sends in response.
+//
+// inbound should contain the messages of the current round, and may contain messages of later rounds,
+// which are kept in the new state until they are needed. ProcessRound can therefore be called again
+// with the new state, once more messages are available. When the protocol is done,
+// the result can be obtained from the final state with OfflineResult.
+func ProcessRound(codec SessionCodec, state []byte, inbound []*Message) (newState []byte, outbound []*Message, err error) {
+	h, err := restoreOffline(codec, state)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, msg := range inbound {
+		h.Accept(msg)
+	}
+	if h.err != nil {
+		return nil, nil, *h.err
+	}
+	return saveOffline(h, codec)
+}
+
+// OfflineResult returns the result of the protocol saved in state,
+// or an error if the protocol is not finished.
+func OfflineResult(codec SessionCodec, state []byte) (interface{}, error) {
+	h, err := restoreOffline(codec, state)
+	if err != nil {
+		return nil, err
+	}
+	return h.Result()
+}
+
+// saveOffline serializes the state of h, and returns it with the messages h sent.
+func saveOffline(h *MultiHandler, codec SessionCodec) ([]byte, []*Message, error) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	var outbound []*Message
+	for done := false; !done; {
+		select {
+		case msg, ok := <-h.out:
+			if !ok || msg.RoundNumber == 0 {
+				done = true
+				break
+			}
+			outbound = append(outbound, msg)
+		default:
+			done = true
+		}
+	}
+	if h.err != nil {
+		return nil, nil, *h.err
+	}
+
+	r := h.currentRound
+	session, err := codec.Marshal(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("protocol: failed to save round: %w", err)
+	}
+	s := offlineState{
+		Session:       session,
+		BroadcastHash: h.broadcastHashes[r.Number()-1],
+	}
+	for number := r.Number(); number <= r.FinalRoundNumber() && r.Number() != 0; number++ {
+		for _, msg := range h.broadcast[number] {
+			if msg != nil {
+				s.Messages = append(s.Messages, msg)
+			}
+		}
+		for _, msg := range h.messages[number] {
+			if msg != nil {
+				s.Messages = append(s.Messages, msg)
+			}
+		}
+	}
+	data, err := cbor.Marshal(s)
+	if err != nil {
+		return nil, nil, fmt.Errorf("protocol: failed to save state: %w", err)
+	}
+	return data, outbound, nil
+}
+
+// restoreOffline returns a MultiHandler in the state saved by saveOffline.
+func restoreOffline(codec SessionCodec, data []byte) (*MultiHandler, error) {
+	var s offlineState
+	if err := cbor.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("protocol: invalid state: %w", err)
+	}
+	r, err := codec.Unmarshal(s.Session)
+	if err != nil {
+		return nil, fmt.Errorf("protocol: failed to restore round: %w", err)
+	}
+	if output, ok := r.(*round.Output); ok {
+		return &MultiHandler{currentRound: r, result: output.Result}, nil
+	}
+	if _, ok := r.(*round.Abort); ok {
+		return nil, errors.New("protocol: cannot restore an aborted session")
+	}
+
+	h, err := NewMultiHandler(func([]byte) (round.Session, error) { return r, nil }, nil)
+	if err != nil {
+		return nil, err
+	}
+	h.mtx.Lock()
+	if s.BroadcastHash != nil {
+		h.broadcastHashes[r.Number()-1] = s.BroadcastHash
+	}
+	var received []*Message
+	for _, msg := range s.Messages {
+		if msg.From == r.SelfID() {
+			// our own messages are stored for the broadcast hash, but not processed
+			h.store(msg)
+		} else {
+			received = append(received, msg)
+		}
+	}
+	h.mtx.Unlock()
+	for _, msg := range received {
+		h.Accept(msg)
+	}
+	return h, nil
+}
@@ -0,0 +1,189 @@
+package protocol_test
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
+)
+
+// proofRound1 sends a public key with a Schnorr proof of knowledge of its secret to all other parties.
+// If cheat is true, the proof is for another public key.
+type proofRound1 struct {
+	*round.Helper
+	cheat bool
+}
+
+func (r *proofRound1) VerifyMessage(round.Message) error { return nil }
+func (r *proofRound1) StoreMessage(round.Message) error  { return nil }
+func (r *proofRound1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	secret := sample.Scalar(rand.Reader, r.Group())
+	public := secret.ActOnBase()
+	proof := zksch.NewProof(r.HashForID(r.SelfID()), public, secret, nil)
+	if r.cheat {
+		public = public.Add(r.Group().NewBasePoint())
+	}
+	if err := r.SendMessage(out, &proofMessage{Public: public, Proof: proof}, ""); err != nil {
+		return r, err
+	}
+	return &proofRound2{proofRound1: r, received: map[party.ID]curve.Point{r.SelfID(): public}}, nil
+}
+func (proofRound1) MessageContent() round.Content { return nil }
+func (proofRound1) Number() round.Number          { return 1 }
+
+type proofMessage struct {
+	Public curve.Point
+	Proof  *zksch.Proof
+}
+
+func (proofMessage) RoundNumber() round.Number { return 2 }
+
+type proofRound2 struct {
+	*proofRound1
+	received map[party.ID]curve.Point
+}
+
+func (r *proofRound2) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*proofMessage)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if !body.Proof.Verify(r.HashForID(msg.From), body.Public, nil) {
+		return errors.New("invalid proof")
+	}
+	return nil
+}
+func (r *proofRound2) StoreMessage(msg round.Message) error {
+	r.received[msg.From] = msg.Content.(*proofMessage).Public
+	return nil
+}
+func (r *proofRound2) Finalize(chan<- *round.Message) (round.Session, error) {
+	return r.ResultRound(len(r.received)), nil
+}
+func (r *proofRound2) MessageContent() round.Content {
+	return &proofMessage{Public: r.Group().NewPoint(), Proof: zksch.EmptyProof(r.Group())}
+}
+func (proofRound2) Number() round.Number { return 2 }
+
+func startProof(selfID party.ID, partyIDs []party.ID, cheat bool) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		helper, err := round.NewSession(round.Info{
+			ProtocolID:       "test/proof",
+			FinalRoundNumber: 2,
+			SelfID:           selfID,
+			PartyIDs:         partyIDs,
+			Threshold:        len(partyIDs) - 1,
+			Group:            curve.Secp256k1{},
+		}, sessionID, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &proofRound1{Helper: helper, cheat: cheat}, nil
+	}
+}
+
+// runProof runs the proof protocol between all parties, using a parallel handler,
+// and returns the handlers once they are done.
+func runProof(t *testing.T, partyIDs party.IDSlice, pl *pool.Pool) map[party.ID]protocol.Handler {
+	handlers := make(map[party.ID]protocol.Handler, len(partyIDs))
+	for _, id := range partyIDs {
+		h, err := protocol.NewParallelMultiHandler(startProof(id, partyIDs, false), []byte("session"), protocol.Limits{}, pl)
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	var wg sync.WaitGroup
+	wg.Add(len(partyIDs))
+	for _, id := range partyIDs {
+		go forward(&wg, id, handlers)
+	}
+	waitOrFail(t, &wg)
+	return handlers
+}
+
+// proofMessages returns the first round messages of all parties but partyIDs[0].
+// Since the proofs only depend on the session ID, they can be given to any handler of partyIDs[0].
+func proofMessages(t testing.TB, partyIDs party.IDSlice, cheaters party.IDSlice) []*protocol.Message {
+	var msgs []*protocol.Message
+	for _, id := range partyIDs[1:] {
+		h, err := protocol.NewMultiHandler(startProof(id, partyIDs, cheaters.Contains(id)), []byte("session"))
+		require.NoError(t, err)
+		msgs = append(msgs, drain(h)...)
+	}
+	return msgs
+}
+
+func TestParallelMultiHandler(t *testing.T) {
+	pl := pool.NewPool(4)
+	defer pl.TearDown()
+	partyIDs := test.PartyIDs(8)
+
+	for id, h := range runProof(t, partyIDs, pl) {
+		result, err := h.Result()
+		require.NoError(t, err, "party %s", id)
+		assert.Equal(t, len(partyIDs), result)
+	}
+
+	// all invalid proofs of the round should be blamed, not only the first one
+	cheaters := party.NewIDSlice([]party.ID{partyIDs[5], partyIDs[2]})
+	h, err := protocol.NewParallelMultiHandler(startProof(partyIDs[0], partyIDs, false), []byte("session"), protocol.Limits{}, pl)
+	require.NoError(t, err)
+	drain(h)
+	for _, msg := range proofMessages(t, partyIDs, cheaters) {
+		h.Accept(msg)
+	}
+	_, err = h.Result()
+	var protocolErr protocol.Error
+	require.ErrorAs(t, err, &protocolErr)
+	assert.Equal(t, []party.ID(cheaters), protocolErr.Culprits)
+	assert.ErrorContains(t, err, fmt.Sprintf("party %s: invalid proof", cheaters[0]))
+
+	_, err = protocol.NewParallelMultiHandler(startProof(partyIDs[0], partyIDs, false), nil, protocol.Limits{}, nil)
+	assert.Error(t, err)
+}
+
+// BenchmarkVerifyRound measures the time taken by a party to handle the messages of the other 31 parties
+// in the second round of the proof protocol, which verifies one Schnorr proof per message.
+func BenchmarkVerifyRound(b *testing.B) {
+	partyIDs := test.PartyIDs(32)
+	self := partyIDs[0]
+	msgs := proofMessages(b, partyIDs, nil)
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	for name, newHandler := range map[string]func() (*protocol.MultiHandler, error){
+		"serial": func() (*protocol.MultiHandler, error) {
+			return protocol.NewMultiHandler(startProof(self, partyIDs, false), []byte("session"))
+		},
+		"parallel": func() (*protocol.MultiHandler, error) {
+			return protocol.NewParallelMultiHandler(startProof(self, partyIDs, false), []byte("session"), protocol.Limits{}, pl)
+		},
+	} {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				h, err := newHandler()
+				require.NoError(b, err)
+				drain(h)
+				b.StartTimer()
+				for _, msg := range msgs {
+					h.Accept(msg)
+				}
+				if _, err = h.Result(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package protocol_test
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+// pointRound1 sends a random point to all other parties, as many protocols do in their first round.
+type pointRound1 struct {
+	*round.Helper
+}
+
+func (r *pointRound1) VerifyMessage(round.Message) error { return nil }
+func (r *pointRound1) StoreMessage(round.Message) error  { return nil }
+func (r *pointRound1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	point := sample.Scalar(rand.Reader, r.Group()).ActOnBase()
+	if err := r.SendMessage(out, &pointMessage{Point: point}, ""); err != nil {
+		return r, err
+	}
+	return &pointRound2{pointRound1: r, received: map[party.ID]curve.Point{r.SelfID(): point}}, nil
+}
+func (pointRound1) MessageContent() round.Content { return nil }
+func (pointRound1) Number() round.Number          { return 1 }
+
+type pointMessage struct {
+	Point curve.Point
+}
+
+func (pointMessage) RoundNumber() round.Number { return 2 }
+
+type pointRound2 struct {
+	*pointRound1
+	received map[party.ID]curve.Point
+}
+
+func (r *pointRound2) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*pointMessage)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.Point == nil || body.Point.IsIdentity() {
+		return round.ErrNilFields
+	}
+	return curve.CheckCurve(r.Group(), body.Point)
+}
+func (r *pointRound2) StoreMessage(msg round.Message) error {
+	r.received[msg.From] = msg.Content.(*pointMessage).Point
+	return nil
+}
+func (r *pointRound2) Finalize(chan<- *round.Message) (round.Session, error) {
+	return r.ResultRound(len(r.received)), nil
+}
+func (r *pointRound2) MessageContent() round.Content {
+	return &pointMessage{Point: r.Group().NewPoint()}
+}
+func (pointRound2) Number() round.Number { return 2 }
+
+func startPoint(selfID party.ID, partyIDs []party.ID) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		helper, err := round.NewSession(round.Info{
+			ProtocolID:       "test/point",
+			FinalRoundNumber: 2,
+			SelfID:           selfID,
+			PartyIDs:         partyIDs,
+			Threshold:        len(partyIDs) - 1,
+			Group:            curve.Secp256k1{},
+		}, sessionID, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &pointRound1{Helper: helper}, nil
+	}
+}
+
+func TestMultiHandlerInvalidPoint(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := test.PartyIDs(2)
+	self, attacker := partyIDs[0], partyIDs[1]
+
+	// an x-coordinate which is not on the curve, found by trying small values
+	offCurve := make([]byte, 33)
+	offCurve[0] = 2
+	for x := byte(1); ; x++ {
+		offCurve[32] = x
+		if group.NewPoint().UnmarshalBinary(offCurve) != nil {
+			break
+		}
+	}
+	identity, err := cbor.Marshal(map[string]interface{}{"Point": nil})
+	require.NoError(t, err)
+	notOnCurve, err := cbor.Marshal(map[string]interface{}{"Point": offCurve})
+	require.NoError(t, err)
+	wrongLength, err := cbor.Marshal(map[string]interface{}{"Point": offCurve[:20]})
+	require.NoError(t, err)
+
+	for name, data := range map[string][]byte{
+		"null":         identity,
+		"not on curve": notOnCurve,
+		"wrong length": wrongLength,
+	} {
+		t.Run(name, func(t *testing.T) {
+			h, err := protocol.NewMultiHandler(startPoint(self, partyIDs), []byte("session"))
+			require.NoError(t, err)
+			other, err := protocol.NewMultiHandler(startPoint(attacker, partyIDs), []byte("session"))
+			require.NoError(t, err)
+			msgs := drain(other)
+			require.Len(t, msgs, 1)
+
+			msg := *msgs[0]
+			msg.Data = data
+			require.NotPanics(t, func() { h.Accept(&msg) })
+
+			_, err = h.Result()
+			var protocolErr protocol.Error
+			require.ErrorAs(t, err, &protocolErr, "the invalid point should abort the protocol")
+			assert.Equal(t, []party.ID{attacker}, protocolErr.Culprits)
+		})
+	}
+}
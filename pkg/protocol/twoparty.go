@@ -1,13 +1,13 @@
 package protocol
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"sync"
 
 	"github.com/fxamacker/cbor/v2"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 )
 
 // TwoPartyHandler represents a restriction of the Handler for 2 party protocols.
@@ -53,14 +53,40 @@ func (h *TwoPartyHandler) Result() (interface{}, error) {
 	return nil, errors.New("protocol: not finished")
 }
 
+// CurrentRound returns the number of the round whose message the handler is waiting for,
+// or 0 once the protocol has finished or aborted.
+func (h *TwoPartyHandler) CurrentRound() int {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.err != nil {
+		return 0
+	}
+	return int(h.round.Number())
+}
+
+// TotalRounds returns the number of rounds of the protocol.
+func (h *TwoPartyHandler) TotalRounds() int {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return int(h.round.FinalRoundNumber())
+}
+
+// Listen returns a channel with outgoing messages that must be sent to the other party.
+//
+// As for MultiHandler.Listen, the channel is owned by the handler, which closes it exactly once,
+// when the protocol finishes, aborts, or is stopped.
 func (h *TwoPartyHandler) Listen() <-chan *Message {
 	h.mtx.Lock()
 	defer h.mtx.Unlock()
 	return h.out
 }
 
+// Stop cancels the current execution of the protocol, and alerts the other party.
+// It does nothing if the protocol has already finished.
 func (h *TwoPartyHandler) Stop() {
-	if h.err != nil || h.result != nil {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.err == nil && h.result == nil {
 		h.abort(errors.New("aborted by user"))
 	}
 }
@@ -69,6 +95,7 @@ func (h *TwoPartyHandler) String() string {
 	return fmt.Sprintf("party: %s, protocol: %s", h.round.SelfID(), h.round.ProtocolID())
 }
 
+// abort ends the protocol with err, or with the result if err is nil, and closes h.out.
 func (h *TwoPartyHandler) abort(err error) {
 	if err != nil {
 		h.err = err
@@ -96,6 +123,9 @@ func (h *TwoPartyHandler) canAdvance() bool {
 }
 
 func extractRoundMessage(r round.Session, msg *Message) (round.Message, error) {
+	if err := checkSchema(msg, r); err != nil {
+		return round.Message{}, err
+	}
 	content := r.MessageContent()
 	if err := cbor.Unmarshal(msg.Data, content); err != nil {
 		return round.Message{}, fmt.Errorf("failed to unmarshal message: %w", err)
@@ -137,9 +167,16 @@ func (h *TwoPartyHandler) advance() {
 			h.abort(err)
 			return
 		}
+		if err := sample.HealthCheck(); err != nil {
+			h.abort(err)
+			return
+		}
 		out := make(chan *round.Message, 1)
-		newRound, err := h.round.Finalize(out)
-		if err != nil || newRound == nil {
+		newRound, err := finalizeRound(h.round, out)
+		if err == nil && newRound == nil {
+			err = errors.New("protocol: round returned no next round")
+		}
+		if err != nil {
 			h.abort(err)
 			return
 		}
@@ -178,29 +215,15 @@ func (h *TwoPartyHandler) advance() {
 }
 
 func (h *TwoPartyHandler) CanAccept(msg *Message) bool {
-	r := h.round
+	return h.CheckMessage(msg) == nil
+}
+
+// CheckMessage returns the reason why msg cannot be accepted, or nil if CanAccept would return true.
+func (h *TwoPartyHandler) CheckMessage(msg *Message) error {
 	if msg == nil {
-		return false
-	}
-	if !msg.IsFor(r.SelfID()) {
-		return false
-	}
-	if msg.Protocol != r.ProtocolID() {
-		return false
-	}
-	if !bytes.Equal(msg.SSID, r.SSID()) {
-		return false
-	}
-	if !r.PartyIDs().Contains(msg.From) {
-		return false
-	}
-	if msg.Data == nil {
-		return false
-	}
-	if msg.RoundNumber > r.FinalRoundNumber() {
-		return false
+		return ErrNoData
 	}
-	return true
+	return msg.checkSession(h.round)
 }
 
 func (h *TwoPartyHandler) Accept(msg *Message) {
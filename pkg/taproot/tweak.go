@@ -0,0 +1,46 @@
+package taproot
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// Tweak returns the scalar t = hash_TapTweak(P ‖ merkleRoot) by which the internal key pk is tweaked
+// to obtain a Taproot output key.
+//
+// merkleRoot is the 32 byte root of the script tree, or empty for an output key without script path.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0341.mediawiki#constructing-and-spending-taproot-outputs
+func (pk PublicKey) Tweak(merkleRoot []byte) (*curve.Secp256k1Scalar, error) {
+	if len(merkleRoot) != 0 && len(merkleRoot) != 32 {
+		return nil, fmt.Errorf("taproot: merkle root of %d bytes, expected 32", len(merkleRoot))
+	}
+	if _, err := (curve.Secp256k1{}).LiftX(pk); err != nil {
+		return nil, fmt.Errorf("taproot: invalid internal key: %w", err)
+	}
+	t := new(curve.Secp256k1Scalar)
+	if err := t.UnmarshalBinary(TaggedHash("TapTweak", pk, merkleRoot)); err != nil {
+		return nil, errors.New("taproot: tweak is not smaller than the order of the curve")
+	}
+	return t, nil
+}
+
+// TweakedKey returns the Taproot output key Q = P + t⋅G, where P is the internal key pk,
+// and t is given by Tweak with the same merkleRoot.
+//
+// Signatures under the output key are produced with the secret key of P tweaked by t,
+// for instance with keygen.TaprootConfig.Tweak for a threshold key.
+func (pk PublicKey) TweakedKey(merkleRoot []byte) (PublicKey, error) {
+	t, err := pk.Tweak(merkleRoot)
+	if err != nil {
+		return nil, err
+	}
+	P, _ := curve.Secp256k1{}.LiftX(pk)
+	Q := P.Add(t.ActOnBase()).(*curve.Secp256k1Point)
+	if Q.IsIdentity() {
+		return nil, errors.New("taproot: tweaked key is the identity")
+	}
+	return PublicKey(Q.XBytes()), nil
+}
@@ -0,0 +1,70 @@
+package taproot
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+func TestTweakedKey(t *testing.T) {
+	// test vectors from https://github.com/bitcoin/bips/blob/master/bip-0341/wallet-test-vectors.json
+	for _, v := range []struct{ internal, merkleRoot, tweak, output string }{
+		{
+			internal: "d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d",
+			tweak:    "b86e7be8f39bab32a6f2c0443abbc210f0edac0e2c53d501b36b64437d9c6c70",
+			output:   "53a1f6e454df1aa2776a2814a721372d6258050de330b3c6d10ee8f4e0dda343",
+		},
+		{
+			internal:   "187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27",
+			merkleRoot: "5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21",
+			tweak:      "cbd8679ba636c1110ea247542cfbd964131a6be84f873f7f3b62a777528ed001",
+			output:     "147c9c57132f6e7ecddba9800bb0c4449251c92a1e60371ee77557b6620f3ea3",
+		},
+	} {
+		internal, _ := hex.DecodeString(v.internal)
+		merkleRoot, _ := hex.DecodeString(v.merkleRoot)
+		tweak, err := PublicKey(internal).Tweak(merkleRoot)
+		require.NoError(t, err)
+		tweakBytes, _ := tweak.MarshalBinary()
+		assert.Equal(t, v.tweak, hex.EncodeToString(tweakBytes))
+		output, err := PublicKey(internal).TweakedKey(merkleRoot)
+		require.NoError(t, err)
+		assert.Equal(t, v.output, hex.EncodeToString(output))
+	}
+
+	_, pk, err := GenKey(rand.Reader)
+	require.NoError(t, err)
+	_, err = pk.TweakedKey(make([]byte, 31))
+	assert.Error(t, err, "the merkle root should be empty or 32 bytes")
+	_, err = PublicKey(make([]byte, 32)).TweakedKey(nil)
+	assert.Error(t, err, "the internal key should be a valid point")
+}
+
+func TestTweakedKeySignature(t *testing.T) {
+	sk, pk, err := GenKey(rand.Reader)
+	require.NoError(t, err)
+	merkleRoot := TaggedHash("TapBranch", []byte("scripts"))
+
+	// the secret key of the internal key P, which has an even y coordinate
+	d := new(curve.Secp256k1Scalar)
+	require.NoError(t, d.UnmarshalBinary(sk))
+	if !d.ActOnBase().(*curve.Secp256k1Point).HasEvenY() {
+		d.Negate()
+	}
+	tweak, err := pk.Tweak(merkleRoot)
+	require.NoError(t, err)
+	tweaked, err := d.Add(tweak).MarshalBinary()
+	require.NoError(t, err)
+
+	output, err := pk.TweakedKey(merkleRoot)
+	require.NoError(t, err)
+	m := TaggedHash("message", []byte("hello"))
+	sig, err := SecretKey(tweaked).Sign(rand.Reader, m)
+	require.NoError(t, err)
+	assert.True(t, output.Verify(sig, m))
+	assert.False(t, pk.Verify(sig, m), "the signature should not be valid under the internal key")
+}
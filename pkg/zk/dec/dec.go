@@ -1,8 +1,6 @@
 package zkdec
 
 import (
-	"crypto/rand"
-
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
@@ -73,11 +71,11 @@ func (p *Proof) IsValid(public Public) bool {
 func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private) *Proof {
 	N := public.Prover.N()
 	NModulus := public.Prover.Modulus()
-	alpha := sample.IntervalLEps(rand.Reader)
+	alpha := sample.IntervalLEps(sample.Reader)
 
-	mu := sample.IntervalLN(rand.Reader)
-	nu := sample.IntervalLEpsN(rand.Reader)
-	r := sample.UnitModN(rand.Reader, N)
+	mu := sample.IntervalLN(sample.Reader)
+	nu := sample.IntervalLEpsN(sample.Reader)
+	r := sample.UnitModN(sample.Reader, N)
 
 	gamma := group.NewScalar().SetNat(alpha.Mod(group.Order()))
 
@@ -0,0 +1,28 @@
+package zkenc
+
+import (
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// Backend creates and verifies proofs that a Paillier ciphertext encrypts a value in range.
+//
+// This allows a protocol to use an alternative implementation of the proof system,
+// for example with faster or batched verification, without modifying the protocol itself.
+type Backend interface {
+	NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private) *Proof
+	Verify(group curve.Curve, hash *hash.Hash, public Public, proof *Proof) bool
+}
+
+// DefaultBackend uses NewProof and Proof.Verify.
+var DefaultBackend Backend = defaultBackend{}
+
+type defaultBackend struct{}
+
+func (defaultBackend) NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private) *Proof {
+	return NewProof(group, hash, public, private)
+}
+
+func (defaultBackend) Verify(group curve.Curve, hash *hash.Hash, public Public, proof *Proof) bool {
+	return proof.Verify(group, hash, public)
+}
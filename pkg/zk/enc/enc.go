@@ -1,8 +1,6 @@
 package zkenc
 
 import (
-	"crypto/rand"
-
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
@@ -65,10 +63,10 @@ func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private
 	N := public.Prover.N()
 	NModulus := public.Prover.Modulus()
 
-	alpha := sample.IntervalLEps(rand.Reader)
-	r := sample.UnitModN(rand.Reader, N)
-	mu := sample.IntervalLN(rand.Reader)
-	gamma := sample.IntervalLEpsN(rand.Reader)
+	alpha := sample.IntervalLEps(sample.Reader)
+	r := sample.UnitModN(sample.Reader, N)
+	mu := sample.IntervalLN(sample.Reader)
+	gamma := sample.IntervalLEpsN(sample.Reader)
 
 	A := public.Prover.EncWithNonce(alpha, r)
 
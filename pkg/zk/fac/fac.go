@@ -1,8 +1,6 @@
 package zkfac
 
 import (
-	"crypto/rand"
-
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
@@ -41,14 +39,14 @@ func NewProof(private Private, hash *hash.Hash, public Public) *Proof {
 	Nhat := public.Aux.NArith()
 
 	// Figure 28, point 1.
-	alpha := sample.IntervalLEpsRootN(rand.Reader)
-	beta := sample.IntervalLEpsRootN(rand.Reader)
-	mu := sample.IntervalLN(rand.Reader)
-	nu := sample.IntervalLN(rand.Reader)
-	sigma := sample.IntervalLN2(rand.Reader)
-	r := sample.IntervalLEpsN2(rand.Reader)
-	x := sample.IntervalLEpsN(rand.Reader)
-	y := sample.IntervalLEpsN(rand.Reader)
+	alpha := sample.IntervalLEpsRootN(sample.Reader)
+	beta := sample.IntervalLEpsRootN(sample.Reader)
+	mu := sample.IntervalLN(sample.Reader)
+	nu := sample.IntervalLN(sample.Reader)
+	sigma := sample.IntervalLN2(sample.Reader)
+	r := sample.IntervalLEpsN2(sample.Reader)
+	x := sample.IntervalLEpsN(sample.Reader)
+	y := sample.IntervalLEpsN(sample.Reader)
 
 	pInt := new(saferith.Int).SetNat(private.P)
 	qInt := new(saferith.Int).SetNat(private.Q)
@@ -1,8 +1,6 @@
 package zklog
 
 import (
-	"crypto/rand"
-
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -59,8 +57,8 @@ func (p *Proof) IsValid() bool {
 }
 
 func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private) *Proof {
-	alpha := sample.Scalar(rand.Reader, group)
-	beta := sample.Scalar(rand.Reader, group)
+	alpha := sample.Scalar(sample.Reader, group)
+	beta := sample.Scalar(sample.Reader, group)
 
 	commitment := &Commitment{
 		A: alpha.ActOnBase(),   // A = α⋅G
@@ -1,7 +1,6 @@
 package zkmod
 
 import (
-	"crypto/rand"
 	"math/big"
 
 	"github.com/cronokirby/saferith"
@@ -162,7 +161,7 @@ func NewProof(hash *hash.Hash, private Private, public Public, pl *pool.Pool) *P
 	qMod := saferith.ModulusFromNat(q)
 	phiMod := saferith.ModulusFromNat(phi)
 	// W can be leaked so no need to make this sampling return a nat.
-	w := sample.QNR(rand.Reader, n)
+	w := sample.QNR(sample.Reader, n)
 
 	nInverse := new(saferith.Nat).ModInverse(n.Nat(), phiMod)
 
@@ -171,7 +170,8 @@ func NewProof(hash *hash.Hash, private Private, public Public, pl *pool.Pool) *P
 	ys, _ := challenge(hash, n, w.Big())
 
 	var rs [params.StatParam]Response
-	pl.Parallelize(params.StatParam, func(i int) interface{} {
+	// a panic would leave some responses empty, so it is raised here instead of sending a malformed proof
+	pool.Repanic(pl.Parallelize(params.StatParam, func(i int) interface{} {
 		y := ys[i]
 
 		// Z = y^{n⁻¹ (mod n)}
@@ -189,7 +189,7 @@ func NewProof(hash *hash.Hash, private Private, public Public, pl *pool.Pool) *P
 		}
 
 		return nil
-	})
+	}))
 
 	return &Proof{
 		W:         w.Big(),
@@ -252,7 +252,7 @@ func (p *Proof) Verify(public Public, hash *hash.Hash, pl *pool.Pool) bool {
 		return p.Responses[i].Verify(n, p.W, ys[i].Big())
 	})
 	for i := 0; i < len(verifications); i++ {
-		if !verifications[i].(bool) {
+		if ok, _ := verifications[i].(bool); !ok {
 			return false
 		}
 	}
@@ -1,8 +1,6 @@
 package zkmul
 
 import (
-	"crypto/rand"
-
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
@@ -72,9 +70,9 @@ func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private
 
 	prover := public.Prover
 
-	alpha := sample.IntervalLEps(rand.Reader)
-	r := sample.UnitModN(rand.Reader, N)
-	s := sample.UnitModN(rand.Reader, N)
+	alpha := sample.IntervalLEps(sample.Reader)
+	r := sample.UnitModN(sample.Reader, N)
+	s := sample.UnitModN(sample.Reader, N)
 
 	A := public.Y.Clone().Mul(prover, alpha)
 	A.Randomize(prover, r)
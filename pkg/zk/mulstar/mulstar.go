@@ -1,8 +1,6 @@
 package zkmulstar
 
 import (
-	"crypto/rand"
-
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
@@ -79,12 +77,12 @@ func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private
 
 	verifier := public.Verifier
 
-	alpha := sample.IntervalLEps(rand.Reader)
+	alpha := sample.IntervalLEps(sample.Reader)
 
-	r := sample.UnitModN(rand.Reader, N0)
+	r := sample.UnitModN(sample.Reader, N0)
 
-	gamma := sample.IntervalLEpsN(rand.Reader)
-	m := sample.IntervalLEpsN(rand.Reader)
+	gamma := sample.IntervalLEpsN(sample.Reader)
+	m := sample.IntervalLEpsN(sample.Reader)
 
 	A := public.C.Clone().Mul(verifier, alpha)
 	A.Randomize(verifier, r)
@@ -1,7 +1,6 @@
 package zkprm
 
 import (
-	"crypto/rand"
 	"io"
 	"math/big"
 
@@ -48,8 +47,8 @@ func NewProof(private Private, hash *hash.Hash, public Public, pl *pool.Pool) *P
 		as [params.StatParam]*saferith.Nat
 		As [params.StatParam]*big.Int
 	)
-	lockedRand := pool.NewLockedReader(rand.Reader)
-	pl.Parallelize(params.StatParam, func(i int) interface{} {
+	lockedRand := pool.NewLockedReader(sample.Reader)
+	pool.Repanic(pl.Parallelize(params.StatParam, func(i int) interface{} {
 		// aᵢ ∈ mod ϕ(N)
 		as[i] = sample.ModN(lockedRand, phi)
 
@@ -57,7 +56,7 @@ func NewProof(private Private, hash *hash.Hash, public Public, pl *pool.Pool) *P
 		As[i] = n.Exp(public.Aux.T(), as[i]).Big()
 
 		return nil
-	})
+	}))
 
 	es, _ := challenge(hash, public, As)
 	// Modular addition is not expensive enough to warrant parallelizing
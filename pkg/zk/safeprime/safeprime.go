@@ -0,0 +1,126 @@
+package zksafeprime
+
+import (
+	"math/big"
+
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+)
+
+// SmallFactorBound is the bound below which no odd prime may divide ϕ(N).
+const SmallFactorBound = arith.SmallFactorBound
+
+// Iterations is the number of challenges of a proof.
+//
+// If an odd prime r < SmallFactorBound divides ϕ(N), at most 1/r ⩽ 1/3 of the elements of ℤₙˣ have an e-th root,
+// so ⌈80/log₂(3)⌉ = 51 challenges give a statistical soundness of 80 bits.
+const Iterations = 51
+
+// exponent = e = ∏ r, for all odd primes r < SmallFactorBound.
+var exponent = arith.SmallPrimesProduct()
+
+type Public struct {
+	// N = p*q
+	N *saferith.Modulus
+}
+
+type Private struct {
+	// P, Q safe primes
+	P, Q *saferith.Nat
+	// Phi = ϕ(n) = (p-1)(q-1)
+	Phi *saferith.Nat
+}
+
+// Proof shows that gcd(e, ϕ(N)) = 1, where e is the product of all odd primes below SmallFactorBound.
+//
+// For safe primes p = 2p'+1 and q = 2q'+1, ϕ(N) = 4p'q' has no such factor, whereas p-1 is divisible
+// by 3 for half of all other primes. Together with a zkmod proof, which shows that (p-1)/2 and (q-1)/2 are odd,
+// this certifies that p' and q' have no prime factor below SmallFactorBound.
+// It does not prove that p' and q' are prime, since there is no efficient zero-knowledge proof of that.
+//
+// This is the e-th root certificate of Goldberg, Reyzin, Sagga and Baldimtsi,
+// "Efficient Noninteractive Certification of RSA Moduli and Beyond".
+type Proof struct {
+	// Roots[i] = xᵢ = yᵢ^{e⁻¹ mod ϕ(N)}
+	Roots [Iterations]*big.Int
+}
+
+func (p *Proof) IsValid(public Public) bool {
+	if p == nil {
+		return false
+	}
+	N := public.N.Big()
+	for _, x := range p.Roots {
+		if !arith.IsValidBigModN(N, x) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewProof generates a proof that gcd(e, ϕ(N)) = 1.
+//
+// With:
+//   - e = ∏ r for all odd primes r < SmallFactorBound
+//   - d = e⁻¹ mod ϕ(N)
+//   - xᵢ = yᵢᵈ (mod N) for i = 1, …, Iterations
+//
+// If p or q is not a safe prime, e is likely not invertible mod ϕ(N), and the proof will not verify.
+func NewProof(hash *hash.Hash, private Private, public Public, pl *pool.Pool) *Proof {
+	n := public.N
+	nModulus := arith.ModulusFromFactors(private.P, private.Q)
+	phiMod := saferith.ModulusFromNat(private.Phi)
+
+	e := new(saferith.Nat).SetBig(exponent, exponent.BitLen())
+	d := new(saferith.Nat).ModInverse(e.Mod(e, phiMod), phiMod)
+
+	ys, _ := challenge(hash, n)
+
+	var roots [Iterations]*big.Int
+	pool.Repanic(pl.Parallelize(Iterations, func(i int) interface{} {
+		roots[i] = nModulus.Exp(ys[i], d).Big()
+		return nil
+	}))
+	return &Proof{Roots: roots}
+}
+
+func (p *Proof) Verify(public Public, hash *hash.Hash, pl *pool.Pool) bool {
+	if !p.IsValid(public) {
+		return false
+	}
+	n := public.N.Big()
+	// N must be odd, and have no prime factor below SmallFactorBound
+	if n.Bit(0) == 0 || new(big.Int).GCD(nil, nil, n, exponent).Cmp(big.NewInt(1)) != 0 {
+		return false
+	}
+
+	// get [yᵢ] <- ℤₙ
+	ys, err := challenge(hash, public.N)
+	if err != nil {
+		return false
+	}
+	verifications := pl.Parallelize(Iterations, func(i int) interface{} {
+		// xᵢᵉ = yᵢ (mod N)
+		lhs := new(big.Int).Exp(p.Roots[i], exponent, n)
+		return lhs.Cmp(ys[i].Big()) == 0
+	})
+	for i := 0; i < len(verifications); i++ {
+		if ok, _ := verifications[i].(bool); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func challenge(hash *hash.Hash, n *saferith.Modulus) (es []*saferith.Nat, err error) {
+	err = hash.WriteAny(n)
+	es = make([]*saferith.Nat, Iterations)
+	var digest = hash.Digest()
+	for i := range es {
+		es[i] = sample.ModN(digest, n)
+	}
+	return
+}
@@ -0,0 +1,66 @@
+package zksafeprime
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/cronokirby/saferith"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/zk"
+)
+
+func TestSafePrime(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	sk := zk.ProverPaillierSecret
+	public := Public{N: sk.PublicKey.N()}
+	proof := NewProof(hash.New(), Private{
+		P:   sk.P(),
+		Q:   sk.Q(),
+		Phi: sk.Phi(),
+	}, public, pl)
+	assert.True(t, proof.Verify(public, hash.New(), pl))
+
+	out, err := cbor.Marshal(proof)
+	require.NoError(t, err, "failed to marshal proof")
+	proof2 := &Proof{}
+	require.NoError(t, cbor.Unmarshal(out, proof2), "failed to unmarshal proof")
+	assert.True(t, proof2.Verify(public, hash.New(), pl))
+
+	other := Public{N: zk.VerifierPaillierPublic.N()}
+	assert.False(t, proof.Verify(other, hash.New(), pl), "proof should be bound to N")
+
+	proof.Roots[0] = big.NewInt(1)
+	assert.False(t, proof.Verify(public, hash.New(), pl), "proof should have failed")
+}
+
+// nonSafePrime returns a prime p such that 3 divides p-1.
+func nonSafePrime(t *testing.T) *saferith.Nat {
+	for {
+		p, err := rand.Prime(rand.Reader, 1024)
+		require.NoError(t, err)
+		if new(big.Int).Mod(p, big.NewInt(3)).Int64() == 1 {
+			return new(saferith.Nat).SetBig(p, 1024)
+		}
+	}
+}
+
+func TestSafePrimeNotSafe(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	p, q := nonSafePrime(t), nonSafePrime(t)
+	n := saferith.ModulusFromNat(new(saferith.Nat).Mul(p, q, -1))
+	one := new(saferith.Nat).SetUint64(1)
+	phi := new(saferith.Nat).Mul(new(saferith.Nat).Sub(p, one, -1), new(saferith.Nat).Sub(q, one, -1), -1)
+
+	public := Public{N: n}
+	proof := NewProof(hash.New(), Private{P: p, Q: q, Phi: phi}, public, pl)
+	assert.False(t, proof.Verify(public, hash.New(), pl), "proof for non-safe primes should fail")
+}
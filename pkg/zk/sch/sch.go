@@ -1,7 +1,6 @@
 package zksch
 
 import (
-	"crypto/rand"
 	"io"
 
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
@@ -35,7 +34,7 @@ type Proof struct {
 func NewProof(hash *hash.Hash, public curve.Point, private curve.Scalar, gen curve.Point) *Proof {
 	group := private.Curve()
 
-	a := NewRandomness(rand.Reader, group, gen)
+	a := NewRandomness(sample.Reader, group, gen)
 	z := a.Prove(hash, public, private, gen)
 	return &Proof{
 		C: *a.Commitment(),
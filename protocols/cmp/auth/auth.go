@@ -0,0 +1,66 @@
+// Package auth implements a handshake in which the parties of a Config prove knowledge of their ECDSA shares.
+//
+// It is meant to run on a new connection, before any other protocol, so that a transport identity
+// is bound to a party of the Config, and impostors are rejected before they can take part in a session.
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+const (
+	protocolID                  = "cmp/auth"
+	protocolRounds round.Number = 2
+)
+
+// Start returns a session in which every party in parties proves knowledge of its share xᵢ,
+// with a Schnorr proof for the public share Xᵢ = Public[i].ECDSA of c.
+//
+// The proofs are bound to the session ID and to c, so they can't be replayed in another session.
+// A party whose proof does not verify is reported by the handler as the culprit of a protocol.Error.
+// Returns the party.IDSlice of authenticated parties if successful.
+func Start(c *config.Config, parties []party.ID, pl *pool.Pool) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		for _, id := range parties {
+			if _, ok := c.Public[id]; !ok {
+				return nil, fmt.Errorf("auth: party %s is not part of the config", id)
+			}
+		}
+		if c.ECDSA == nil || !c.ECDSA.ActOnBase().Equal(c.Public[c.ID].ECDSA) {
+			return nil, errors.New("auth: secret share does not match the public share")
+		}
+		helper, err := newSession(c, parties, sessionID, pl)
+		if err != nil {
+			return nil, err
+		}
+		return &round1{
+			Helper: helper,
+			config: c,
+		}, nil
+	}
+}
+
+// newSession returns the helper of a session for c, whose SSID only depends on the public data of c.
+func newSession(c *config.Config, parties []party.ID, sessionID []byte, pl *pool.Pool) (*round.Helper, error) {
+	info := round.Info{
+		ProtocolID:       protocolID,
+		FinalRoundNumber: protocolRounds,
+		SelfID:           c.ID,
+		PartyIDs:         parties,
+		// any subset of the parties can authenticate each other, regardless of the threshold
+		Threshold: 0,
+		Group:     c.Group,
+	}
+	helper, err := round.NewSession(info, sessionID, pl, c)
+	if err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+	return helper, nil
+}
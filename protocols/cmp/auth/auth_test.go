@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"crypto/rand"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+// startImpostor is Start for a party which knows the public data of c, but not its secret share.
+func startImpostor(c *config.Config, parties []party.ID, pl *pool.Pool) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		helper, err := newSession(c, parties, sessionID, pl)
+		if err != nil {
+			return nil, err
+		}
+		impostor := *c
+		impostor.ECDSA = sample.Scalar(rand.Reader, c.Group)
+		return &round1{Helper: helper, config: &impostor}, nil
+	}
+}
+
+func run(t *testing.T, configs map[party.ID]*config.Config, impostorID party.ID, parties party.IDSlice, pl *pool.Pool) map[party.ID]error {
+	n := test.NewNetwork(parties)
+	var mtx sync.Mutex
+	errs := make(map[party.ID]error, len(parties))
+	var wg sync.WaitGroup
+	wg.Add(len(parties))
+	for _, id := range parties {
+		go func(c *config.Config) {
+			defer wg.Done()
+			start := Start(c, parties, pl)
+			if c.ID == impostorID {
+				start = startImpostor(c, parties, pl)
+			}
+			h, err := protocol.NewMultiHandler(start, []byte("session"))
+			require.NoError(t, err)
+			test.HandlerLoop(c.ID, h, n)
+			r, err := h.Result()
+			if err == nil {
+				assert.Equal(t, parties, r)
+			}
+			mtx.Lock()
+			errs[c.ID] = err
+			mtx.Unlock()
+		}(configs[id])
+	}
+	wg.Wait()
+	return errs
+}
+
+func TestAuth(t *testing.T) {
+	group := curve.Secp256k1{}
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	configs, partyIDs := test.GenerateConfig(group, 4, 2, rand.Reader, pl)
+
+	for id, err := range run(t, configs, "", partyIDs[:2], pl) {
+		assert.NoError(t, err, "party %s should be authenticated", id)
+	}
+
+	errs := run(t, configs, "b", party.IDSlice{"a", "b", "c"}, pl)
+	for _, id := range []party.ID{"a", "c"} {
+		var blamed protocol.Error
+		require.True(t, errors.As(errs[id], &blamed), "party %s should reject the impostor", id)
+		assert.Equal(t, []party.ID{"b"}, blamed.Culprits)
+	}
+
+	_, err := Start(configs["a"], party.IDSlice{"a", "b", "e"}, pl)(nil)
+	assert.Error(t, err, "parties must be part of the config")
+	wrongShare := *configs["a"]
+	wrongShare.ECDSA = sample.Scalar(rand.Reader, group)
+	_, err = Start(&wrongShare, partyIDs, pl)(nil)
+	assert.Error(t, err, "the secret share should match the public share")
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+var _ round.Round = (*round1)(nil)
+
+type round1 struct {
+	*round.Helper
+
+	config *config.Config
+}
+
+// VerifyMessage implements round.Round.
+func (r *round1) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (r *round1) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - send πᵢ = zksch(xᵢ) for Xᵢ, bound to the session.
+func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	proof := zksch.NewProof(r.HashForID(r.SelfID()), r.config.Public[r.SelfID()].ECDSA, r.config.ECDSA, nil)
+	if err := r.SendMessage(out, &message2{Proof: proof}, ""); err != nil {
+		return r, err
+	}
+	return &round2{round1: r}, nil
+}
+
+// MessageContent implements round.Round.
+func (round1) MessageContent() round.Content { return nil }
+
+// Number implements round.Round.
+func (round1) Number() round.Number { return 1 }
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"errors"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
+)
+
+var _ round.Round = (*round2)(nil)
+
+type round2 struct {
+	*round1
+}
+
+type message2 struct {
+	// Proof = zksch(xⱼ) for Xⱼ
+	Proof *zksch.Proof
+}
+
+// VerifyMessage implements round.Round.
+//
+// - verify that the sender knows the share of its Public entry.
+func (r *round2) VerifyMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*message2)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if !body.Proof.Verify(r.HashForID(from), r.config.Public[from].ECDSA, nil) {
+		return errors.New("failed to prove knowledge of the ECDSA share")
+	}
+	return nil
+}
+
+// StoreMessage implements round.Round.
+func (r *round2) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - all proofs were verified, so every party is authenticated.
+func (r *round2) Finalize(chan<- *round.Message) (round.Session, error) {
+	return r.ResultRound(r.PartyIDs()), nil
+}
+
+// MessageContent implements round.Round.
+func (r *round2) MessageContent() round.Content {
+	return &message2{Proof: zksch.EmptyProof(r.Group())}
+}
+
+// RoundNumber implements round.Content.
+func (message2) RoundNumber() round.Number { return 2 }
+
+// Number implements round.Round.
+func (round2) Number() round.Number { return 2 }
@@ -1,22 +1,33 @@
 package cmp
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/auth"
 	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
 	"github.com/taurusgroup/multi-party-sig/protocols/cmp/keygen"
 	"github.com/taurusgroup/multi-party-sig/protocols/cmp/presign"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/rid"
 	"github.com/taurusgroup/multi-party-sig/protocols/cmp/sign"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
 )
 
 // Config represents the stored state of a party who participated in a successful `Keygen` protocol.
 // It contains secret key material and should be safely stored.
 type Config = config.Config
 
+// PublicConfig is the public key of a Config together with its chain key, see Config.PublicConfig.
+// It contains no secret key material.
+type PublicConfig = config.PublicConfig
+
 // EmptyConfig creates an empty Config with a fixed group, ready for unmarshalling.
 //
 // This needs to be used for unmarshalling, otherwise the points on the curve can't
@@ -44,6 +55,72 @@ func Keygen(group curve.Curve, selfID party.ID, participants []party.ID, thresho
 	return keygen.Start(info, pl, nil)
 }
 
+// KeygenWithPrimeSource is like Keygen, but the Paillier primes of this party are taken from `source`,
+// for instance a paillier.PrimePool of pre-generated safe primes, or an HSM, instead of being generated on the fly.
+// The primes are checked with paillier.ValidatePrime before they are used.
+// Returns *cmp.Config if successful.
+func KeygenWithPrimeSource(group curve.Curve, selfID party.ID, participants []party.ID, threshold int, source paillier.PrimeSource, pl *pool.Pool) protocol.StartFunc {
+	info := round.Info{
+		ProtocolID:       "cmp/keygen-threshold",
+		FinalRoundNumber: keygen.Rounds,
+		SelfID:           selfID,
+		PartyIDs:         participants,
+		Threshold:        threshold,
+		Group:            group,
+	}
+	return keygen.StartWithPrimeSource(info, pl, nil, source)
+}
+
+// KeygenWithSeed is like Keygen, but all the secrets of this party, including its Paillier primes, are derived from seed,
+// so that a keygen in which every party uses the same seed as before gives the same Config.
+//
+// This is INSECURE: anyone who knows seed learns the secret share of this party.
+// It must only be used for reproducible tests, never in production.
+// Returns *cmp.Config if successful.
+func KeygenWithSeed(group curve.Curve, selfID party.ID, participants []party.ID, threshold int, seed []byte) protocol.StartFunc {
+	info := round.Info{
+		ProtocolID:       "cmp/keygen-threshold",
+		FinalRoundNumber: keygen.Rounds,
+		SelfID:           selfID,
+		PartyIDs:         participants,
+		Threshold:        threshold,
+		Group:            group,
+	}
+	return keygen.StartWithSeed(info, seed)
+}
+
+// KeygenWithSafePrimeProofs is like Keygen, but every party also proves that its Paillier modulus is made of safe primes.
+// The proofs are verified during the protocol, and stored in the Public data of the Config,
+// so that they can be checked again later with Config.VerifySafePrimes.
+// Returns *cmp.Config if successful.
+func KeygenWithSafePrimeProofs(group curve.Curve, selfID party.ID, participants []party.ID, threshold int, pl *pool.Pool) protocol.StartFunc {
+	info := round.Info{
+		ProtocolID:       "cmp/keygen-safe-prime-threshold",
+		FinalRoundNumber: keygen.Rounds,
+		SelfID:           selfID,
+		PartyIDs:         participants,
+		Threshold:        threshold,
+		Group:            group,
+	}
+	return keygen.StartWithSafePrimeProofs(info, pl)
+}
+
+// KeygenSchnorr is like Keygen, but skips the generation of the Paillier and Pedersen parameters, which is
+// the most expensive part of Keygen. The resulting Config is SchnorrOnly: it can be used with SignSchnorr,
+// Refresh and LowerThreshold, but not with the ECDSA signing protocols, which rely on Paillier encryption.
+// Returns *cmp.Config if successful.
+func KeygenSchnorr(group curve.Curve, selfID party.ID, participants []party.ID, threshold int, pl *pool.Pool) protocol.StartFunc {
+	info := round.Info{
+		ProtocolID:       "cmp/keygen-schnorr-threshold",
+		FinalRoundNumber: keygen.Rounds,
+		SelfID:           selfID,
+		PartyIDs:         participants,
+		Threshold:        threshold,
+		Group:            group,
+	}
+	return keygen.StartSchnorrOnly(info, pl)
+}
+
 // Refresh allows the parties to refresh all existing cryptographic keys from a previously generated Config.
 // The group's ECDSA public key remains the same, but any previous shares are rendered useless.
 // Returns *cmp.Config if successful.
@@ -59,12 +136,180 @@ func Refresh(config *Config, pl *pool.Pool) protocol.StartFunc {
 	return keygen.Start(info, pl, config)
 }
 
+// RefreshAux allows the parties to rotate their ElGamal, Paillier and Pedersen keys from a previously generated Config.
+// Unlike Refresh, the ECDSA shares of all parties remain the same.
+// Returns *cmp.Config if successful.
+func RefreshAux(config *Config, pl *pool.Pool) protocol.StartFunc {
+	info := round.Info{
+		ProtocolID:       "cmp/refresh-aux-threshold",
+		FinalRoundNumber: keygen.Rounds,
+		SelfID:           config.ID,
+		PartyIDs:         config.PartyIDs(),
+		Threshold:        config.Threshold,
+		Group:            config.Group,
+	}
+	return keygen.StartAuxRefresh(info, pl, config)
+}
+
+// LowersThreshold must be passed to LowerThreshold, to acknowledge that fewer parties will be able to sign.
+type LowersThreshold bool
+
+// IUnderstandThisLowersTheThreshold is the only value of LowersThreshold accepted by LowerThreshold.
+const IUnderstandThisLowersTheThreshold LowersThreshold = true
+
+// LowerThreshold reshares the key of a previously generated Config with `newThreshold`, which must be lower
+// than the current threshold. All parties of the Config must take part.
+// The group's ECDSA public key and chain key remain the same, and all other keys are refreshed.
+//
+// Lowering the threshold weakens the scheme, since fewer corrupted parties suffice to recover the secret key.
+// Returns *cmp.Config if successful.
+func LowerThreshold(config *Config, newThreshold int, acknowledge LowersThreshold, pl *pool.Pool) protocol.StartFunc {
+	if acknowledge != IUnderstandThisLowersTheThreshold {
+		return func([]byte) (round.Session, error) {
+			return nil, errors.New("cmp.LowerThreshold: lowering the threshold must be acknowledged")
+		}
+	}
+	info := round.Info{
+		ProtocolID:       "cmp/reshare-lower-threshold",
+		FinalRoundNumber: keygen.Rounds,
+		SelfID:           config.ID,
+		PartyIDs:         config.PartyIDs(),
+		Threshold:        newThreshold,
+		Group:            config.Group,
+	}
+	return keygen.StartLowerThreshold(info, pl, config)
+}
+
+// RotateRID lets all the parties of `config` agree on a new random RID, to renew the domain separation
+// of the sessions which use the config, without resharing the key. All parties of the Config must take part.
+// The ECDSA shares, auxiliary keys and chain key remain the same.
+// Returns *cmp.Config if successful.
+func RotateRID(config *Config, pl *pool.Pool) protocol.StartFunc {
+	return rid.Start(config, pl)
+}
+
+// Authenticate lets the given `parties` prove to each other that they hold the ECDSA shares of `config`,
+// with a Schnorr proof for their public share. It should be run on a new connection before any other protocol,
+// so that a party which can't prove its share is rejected as the culprit of a *protocol.Error before round 1.
+// Returns party.IDSlice, the authenticated parties, if successful.
+func Authenticate(config *Config, parties []party.ID, pl *pool.Pool) protocol.StartFunc {
+	return auth.Start(config, parties, pl)
+}
+
 // Sign generates an ECDSA signature for `messageHash` among the given `signers`.
 // Returns *ecdsa.Signature if successful.
 func Sign(config *Config, signers []party.ID, messageHash []byte, pl *pool.Pool) protocol.StartFunc {
 	return sign.StartSign(config, signers, messageHash, pl)
 }
 
+// RestartSign returns a new signing session for `messageHash` among `signers`, which replaces the session of h,
+// for instance because a signer dropped out. Substitutes may replace the parties that dropped out.
+// When the session is started, it first checks that `signers` can still sign with config, and then stops h,
+// which alerts the other signers of the stopped session. They must also start the new session,
+// while the substitutes start it with Sign. h may be nil if the old session is already stopped.
+//
+// The presigning material of a session depends on its set of signers, and its nonces must never be reused,
+// so it is discarded with h and the new session starts from the first round.
+// A fresh session ID should be used, so that messages of the stopped session are rejected.
+// Returns *ecdsa.Signature if successful.
+func RestartSign(h *protocol.MultiHandler, config *Config, signers []party.ID, messageHash []byte, pl *pool.Pool) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		if err := config.CheckSigners(party.NewIDSlice(signers)); err != nil {
+			return nil, fmt.Errorf("cmp: restart: %w", err)
+		}
+		if h != nil {
+			h.Stop()
+		}
+		return Sign(config, signers, messageHash, pl)(sessionID)
+	}
+}
+
+// SignSchnorr generates a BIP-340 Schnorr signature for `messageHash` among the given `signers`,
+// with the Frost signing protocol.
+// Since it does not use the Paillier and Pedersen parameters, it accepts any Config, including a SchnorrOnly one.
+//
+// The group must be secp256k1. BIP-340 keys are x-only, and the signature verifies under the x coordinate
+// of the public key: if its y coordinate is odd, the shares of its negation, which has the same x coordinate,
+// are used instead, as in the Frost taproot keygen. The config itself is not modified.
+// Returns taproot.Signature if successful.
+func SignSchnorr(config *Config, signers []party.ID, messageHash []byte) protocol.StartFunc {
+	publicKey, ok := config.PublicPoint().(*curve.Secp256k1Point)
+	if !ok {
+		return func([]byte) (round.Session, error) {
+			return nil, errors.New("cmp: Schnorr signing requires secp256k1")
+		}
+	}
+	negate := !publicKey.HasEvenY()
+	privateShare := config.Group.NewScalar().Set(config.ECDSA)
+	if negate {
+		privateShare.Negate()
+	}
+	verificationShares := make(map[party.ID]*curve.Secp256k1Point, len(config.Public))
+	for j, public := range config.Public {
+		share := public.ECDSA
+		if negate {
+			share = share.Negate()
+		}
+		verificationShares[j] = share.(*curve.Secp256k1Point)
+	}
+	return frost.SignTaproot(&frost.TaprootConfig{
+		ID:                 config.ID,
+		Threshold:          config.Threshold,
+		PrivateShare:       privateShare.(*curve.Secp256k1Scalar),
+		PublicKey:          publicKey.XBytes(),
+		ChainKey:           config.ChainKey,
+		VerificationShares: verificationShares,
+	}, signers, messageHash)
+}
+
+// SignCodec returns a protocol.SessionCodec for the session started by Sign with the same arguments and `sessionID`.
+// It allows an offline party to take part in the signing with protocol.StartOffline and protocol.ProcessRound,
+// by exchanging serialized messages and keeping only its serialized state between rounds.
+func SignCodec(config *Config, signers []party.ID, messageHash, sessionID []byte, pl *pool.Pool) protocol.SessionCodec {
+	return sign.NewCodec(config, signers, messageHash, sessionID, pl)
+}
+
+// SignWithContext generates an ECDSA signature for `messageHash` among the given `signers`,
+// and binds the session to `context`, which all signers must agree on.
+// Returns *sign.ContextSignature if successful.
+func SignWithContext(config *Config, signers []party.ID, messageHash, context []byte, pl *pool.Pool) protocol.StartFunc {
+	return sign.StartSignWithContext(config, signers, messageHash, context, pl)
+}
+
+// SignScalar generates an ECDSA signature for the message `m`, already reduced to a scalar, among the given `signers`.
+// The caller is responsible for deriving `m` from a hash of the message, see sign.StartSignScalar.
+// Returns *ecdsa.Signature if successful.
+func SignScalar(config *Config, signers []party.ID, m curve.Scalar, pl *pool.Pool) protocol.StartFunc {
+	return sign.StartSignScalar(config, signers, m, pl)
+}
+
+// SignLowS is the same as Sign, but the signature is normalized so that s ≤ n/2, as required by Bitcoin and Ethereum.
+// Returns *ecdsa.Signature if successful.
+func SignLowS(config *Config, signers []party.ID, messageHash []byte, pl *pool.Pool) protocol.StartFunc {
+	return sign.StartSignLowS(config, signers, messageHash, pl)
+}
+
+// SignSighash generates an ECDSA signature for the Bitcoin signature hash of `input`, which commits to both
+// the serialized transaction and its sighash type, see sign.SighashInput.Digest. The signature is low-s.
+// Returns *ecdsa.Signature if successful.
+func SignSighash(config *Config, signers []party.ID, input *sign.SighashInput, pl *pool.Pool) protocol.StartFunc {
+	return sign.StartSignSighash(config, signers, input, pl)
+}
+
+// SignAccountable is the same as Sign, but every signer also attests to its participation.
+// The certificate of the result names the signers, and can be checked by an auditor with QuorumCertificate.Verify.
+// Returns *sign.AccountableSignature if successful.
+func SignAccountable(config *Config, signers []party.ID, messageHash []byte, pl *pool.Pool) protocol.StartFunc {
+	return sign.StartSignAccountable(config, signers, messageHash, pl)
+}
+
+// SignBatch generates an ECDSA signature for each hash in `messageHashes` among the given `signers`,
+// in a single protocol execution. Each signature uses an independent nonce.
+// Returns []*ecdsa.Signature if successful, in the same order as `messageHashes`.
+func SignBatch(config *Config, signers []party.ID, messageHashes [][]byte, pl *pool.Pool) protocol.StartFunc {
+	return presign.StartSignBatch(config, signers, messageHashes, pl)
+}
+
 // Presign generates a preprocessed signature that does not depend on the message being signed.
 // When the message becomes available, the same participants can efficiently combine their shares
 // to produce a full signature with the PresignOnline protocol.
@@ -79,3 +324,46 @@ func Presign(config *Config, signers []party.ID, pl *pool.Pool) protocol.StartFu
 func PresignOnline(config *Config, preSignature *ecdsa.PreSignature, messageHash []byte, pl *pool.Pool) protocol.StartFunc {
 	return presign.StartPresignOnline(config, preSignature, messageHash, pl)
 }
+
+// PresignOnlineWithStore is like PresignOnline, but records `preSignature` in `store` before signing,
+// and fails if it was already used, for instance before a restart of the process.
+// presign.NewFileUsedStore returns a store backed by a file.
+func PresignOnlineWithStore(config *Config, preSignature *ecdsa.PreSignature, messageHash []byte, store presign.UsedStore, pl *pool.Pool) protocol.StartFunc {
+	return presign.StartPresignOnlineWithStore(config, preSignature, messageHash, store, pl)
+}
+
+// PresignAdaptor generates a preprocessed signature bound to the adaptor point T = t⋅G,
+// for instance to lock one side of an atomic swap.
+// Note: the AdaptorPreSignatures should be treated as secret key material.
+// Returns *ecdsa.AdaptorPreSignature if successful.
+func PresignAdaptor(config *Config, signers []party.ID, T curve.Point, pl *pool.Pool) protocol.StartFunc {
+	return presign.StartPresignAdaptor(config, signers, T, pl)
+}
+
+// PresignAdaptorOnline generates an adaptor signature for `messageHash` given a preprocessed `AdaptorPreSignature`.
+// It becomes a valid ECDSA signature with ecdsa.Adapt and t, and t can be recovered from that signature with ecdsa.Extract.
+// Returns *ecdsa.AdaptorSignature if successful.
+func PresignAdaptorOnline(config *Config, preSignature *ecdsa.AdaptorPreSignature, messageHash []byte, store presign.UsedStore, pl *pool.Pool) protocol.StartFunc {
+	return presign.StartPresignAdaptorOnline(config, preSignature, messageHash, store, pl)
+}
+
+// Combine returns a Config for the sum of the keys of `a` and `b`, which were generated by independent keygens
+// between the same parties, with the same threshold. Its public key is a.PublicPoint() + b.PublicPoint(),
+// and its auxiliary parameters are taken from `a`, see config.Combine.
+// All parties must pass their configs in the same order.
+func Combine(a, b *Config) (*Config, error) {
+	return config.Combine(a, b)
+}
+
+// VerifyWithDerivedKey verifies an ECDSA signature `sig` for `messageHash`, made by the key at the BIP32 `path`
+// relative to `root`, such as a Config derived with Config.DeriveBIP32Path.
+//
+// Only the public key and chain key of the root Config are needed, so this can be used by services holding no share.
+// An error is returned if `path` is invalid, or if it can't be derived from `root`.
+func VerifyWithDerivedKey(root *PublicConfig, path string, messageHash []byte, sig *ecdsa.Signature) (bool, error) {
+	child, err := root.DeriveBIP32Path(path)
+	if err != nil {
+		return false, err
+	}
+	return sig.Verify(child.PublicKey, messageHash), nil
+}
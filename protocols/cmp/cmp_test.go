@@ -2,18 +2,26 @@ package cmp
 
 import (
 	"crypto/rand"
+	"errors"
 	"math"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/taurusgroup/multi-party-sig/internal/test"
 	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/taproot"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
 )
 
 func do(t *testing.T, id party.ID, ids []party.ID, threshold int, message []byte, pl *pool.Pool, n *test.Network, wg *sync.WaitGroup) {
@@ -86,6 +94,54 @@ func TestCMP(t *testing.T) {
 	wg.Wait()
 }
 
+// TestUUIDParties runs the protocols with IDs derived from random UUIDs.
+func TestUUIDParties(t *testing.T) {
+	N := 3
+	message := []byte("hello")
+
+	partyIDs := make(party.IDSlice, 0, N)
+	for i := 0; i < N; i++ {
+		uuid := make([]byte, 16)
+		_, err := rand.Read(uuid)
+		require.NoError(t, err)
+		id, err := party.IDFromBytes(uuid)
+		require.NoError(t, err)
+		partyIDs = append(partyIDs, id)
+	}
+	partyIDs = party.NewIDSlice(partyIDs)
+	n := test.NewNetwork(partyIDs)
+
+	var wg sync.WaitGroup
+	wg.Add(N)
+	for _, id := range partyIDs {
+		pl := pool.NewPool(3)
+		defer pl.TearDown()
+		go do(t, id, partyIDs, N-1, message, pl, n, &wg)
+	}
+	wg.Wait()
+}
+
+func TestKeygenRejectsCollidingIDs(t *testing.T) {
+	// both IDs are mapped to the same interpolation point
+	partyIDs := party.NewIDSlice([]party.ID{"a", "\x00a", "b"})
+	_, err := protocol.NewMultiHandler(Keygen(curve.Secp256k1{}, "b", partyIDs, 1, nil), nil)
+	assert.Error(t, err)
+}
+
+// TestSingleParty runs the protocols for the degenerate 1-of-1 setup, in which the single party holds the whole key.
+func TestSingleParty(t *testing.T) {
+	message := []byte("hello")
+	partyIDs := test.PartyIDs(1)
+	n := test.NewNetwork(partyIDs)
+
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go do(t, partyIDs[0], partyIDs, 0, message, pl, n, &wg)
+	wg.Wait()
+}
+
 func TestStart(t *testing.T) {
 	group := curve.Secp256k1{}
 	N := 6
@@ -161,3 +217,763 @@ func TestStart(t *testing.T) {
 		})
 	}
 }
+
+func TestRecoverAndResplit(t *testing.T) {
+	group := curve.Secp256k1{}
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	configs, partyIDs := test.GenerateConfig(group, 3, 1, rand.Reader, pl)
+	publicKey := configs[partyIDs[0]].PublicPoint()
+	shares := []*Config{configs[partyIDs[0]], configs[partyIDs[2]]}
+	newParties := []party.ID{"x", "y", "z"}
+
+	_, err := RecoverAndResplit(shares, newParties, 1, false, pl)
+	assert.Error(t, err, "recovery should require an acknowledgement")
+	_, err = RecoverAndResplit(shares[:1], newParties, 1, IUnderstandThisReconstructsTheSecret, pl)
+	assert.Error(t, err, "recovery should require threshold+1 shares")
+
+	newConfigs, err := RecoverAndResplit(shares, newParties, 1, IUnderstandThisReconstructsTheSecret, pl)
+	require.NoError(t, err)
+	require.Len(t, newConfigs, len(newParties))
+	for _, c := range newConfigs {
+		assert.True(t, publicKey.Equal(c.PublicPoint()), "the public key should not change")
+		assert.NotEqual(t, shares[0].Paillier.N().Big(), c.Paillier.N().Big(), "Paillier keys should be fresh")
+	}
+	assert.NotSame(t, newConfigs["x"].Public["y"], newConfigs["z"].Public["y"], "configs should not share their public data")
+
+	message := []byte("hello")
+	signers := []party.ID{"x", "z"}
+	n := test.NewNetwork(signers)
+	var wg sync.WaitGroup
+	wg.Add(len(signers))
+	for _, id := range signers {
+		go func(c *Config) {
+			defer wg.Done()
+			h, err := protocol.NewMultiHandler(Sign(c, signers, message, pl), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(c.ID, h, n)
+			r, err := h.Result()
+			require.NoError(t, err)
+			require.IsType(t, &ecdsa.Signature{}, r)
+			assert.True(t, r.(*ecdsa.Signature).Verify(publicKey, message))
+		}(newConfigs[id])
+	}
+	wg.Wait()
+}
+
+func TestCombine(t *testing.T) {
+	group := curve.Secp256k1{}
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	first, partyIDs := test.GenerateConfig(group, 3, 1, rand.Reader, pl)
+	second, _ := test.GenerateConfig(group, 3, 1, rand.Reader, pl)
+	publicKey := first[partyIDs[0]].PublicPoint().Add(second[partyIDs[0]].PublicPoint())
+
+	combined := make(map[party.ID]*Config, len(partyIDs))
+	for _, id := range partyIDs {
+		c, err := Combine(first[id], second[id])
+		require.NoError(t, err)
+		assert.True(t, publicKey.Equal(c.PublicPoint()), "the public key should be the sum")
+		assert.Equal(t, first[id].Paillier, c.Paillier, "the auxiliary parameters should be taken from the first config")
+		assert.Equal(t, config.OperationCombine, c.History[len(c.History)-1].Operation)
+		combined[id] = c
+	}
+	assert.NoError(t, combined[partyIDs[0]].RID.Validate())
+	assert.Equal(t, combined[partyIDs[0]].ChainKey, combined[partyIDs[1]].ChainKey, "all parties should have the same chain key")
+
+	_, err := Combine(first[partyIDs[0]], second[partyIDs[1]])
+	assert.Error(t, err, "configs of different parties should not be combined")
+	other, _ := test.GenerateConfig(group, 3, 2, rand.Reader, pl)
+	_, err = Combine(first[partyIDs[0]], other[partyIDs[0]])
+	assert.EqualError(t, err, "config: combined configs have thresholds 1 and 2")
+
+	message := []byte("hello")
+	signers := []party.ID{partyIDs[0], partyIDs[2]}
+	n := test.NewNetwork(signers)
+	var wg sync.WaitGroup
+	wg.Add(len(signers))
+	for _, id := range signers {
+		go func(c *Config) {
+			defer wg.Done()
+			h, err := protocol.NewMultiHandler(Sign(c, signers, message, pl), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(c.ID, h, n)
+			r, err := h.Result()
+			require.NoError(t, err)
+			require.IsType(t, &ecdsa.Signature{}, r)
+			assert.True(t, r.(*ecdsa.Signature).Verify(publicKey, message), "the combined config should sign for the sum of the keys")
+		}(combined[id])
+	}
+	wg.Wait()
+}
+
+func TestDealerSplit(t *testing.T) {
+	group := curve.Secp256k1{}
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	secret := sample.Scalar(rand.Reader, group)
+	partyIDs := []party.ID{"a", "b", "c"}
+
+	_, err := DealerSplit(secret, partyIDs, 1, false, pl)
+	assert.Error(t, err, "splitting should require an acknowledgement")
+	_, err = DealerSplit(group.NewScalar(), partyIDs, 1, IUnderstandTheDealerKnowsTheSecret, pl)
+	assert.Error(t, err, "a zero secret should be rejected")
+	_, err = DealerSplit(secret, partyIDs, 3, IUnderstandTheDealerKnowsTheSecret, pl)
+	assert.Error(t, err, "the threshold should be smaller than the number of parties")
+	// both IDs are mapped to the same interpolation point
+	_, err = DealerSplit(secret, []party.ID{"a", "\x00a", "b"}, 1, IUnderstandTheDealerKnowsTheSecret, pl)
+	assert.ErrorContains(t, err, "same interpolation point")
+
+	configs, err := DealerSplit(secret, partyIDs, 1, IUnderstandTheDealerKnowsTheSecret, pl)
+	require.NoError(t, err)
+	require.Len(t, configs, len(partyIDs))
+	publicKey := secret.ActOnBase()
+	for _, c := range configs {
+		require.NoError(t, c.Validate())
+		assert.True(t, publicKey.Equal(c.PublicPoint()), "the public key should be secret⋅G")
+	}
+
+	message := []byte("hello")
+	signers := []party.ID{"a", "c"}
+	n := test.NewNetwork(signers)
+	var wg sync.WaitGroup
+	wg.Add(len(signers))
+	for _, id := range signers {
+		go func(c *Config) {
+			defer wg.Done()
+			h, err := protocol.NewMultiHandler(Sign(c, signers, message, pl), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(c.ID, h, n)
+			r, err := h.Result()
+			require.NoError(t, err)
+			require.IsType(t, &ecdsa.Signature{}, r)
+			assert.True(t, r.(*ecdsa.Signature).Verify(publicKey, message))
+		}(configs[id])
+	}
+	wg.Wait()
+}
+
+func TestRefreshAux(t *testing.T) {
+	group := curve.Secp256k1{}
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	N := 2
+	configs, partyIDs := test.GenerateConfig(group, N, N-1, rand.Reader, pl)
+	publicKey := configs[partyIDs[0]].PublicPoint()
+	message := []byte("hello")
+
+	n := test.NewNetwork(partyIDs)
+	var wg sync.WaitGroup
+	wg.Add(N)
+	for _, id := range partyIDs {
+		go func(old *Config) {
+			defer wg.Done()
+			h, err := protocol.NewMultiHandler(RefreshAux(old, pl), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(old.ID, h, n)
+			r, err := h.Result()
+			require.NoError(t, err)
+			require.IsType(t, &Config{}, r)
+			c := r.(*Config)
+
+			assert.True(t, publicKey.Equal(c.PublicPoint()), "the public key should not change")
+			assert.True(t, old.ECDSA.Equal(c.ECDSA), "the ECDSA share should not change")
+			for _, j := range partyIDs {
+				assert.True(t, old.Public[j].ECDSA.Equal(c.Public[j].ECDSA), "the public ECDSA shares should not change")
+				assert.NotEqual(t, old.Public[j].Paillier.N().Big(), c.Public[j].Paillier.N().Big(), "Paillier keys should be rotated")
+			}
+			assert.NotEqual(t, old.Paillier.N().Big(), c.Paillier.N().Big(), "Paillier keys should be rotated")
+
+			h, err = protocol.NewMultiHandler(Sign(c, partyIDs, message, pl), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(c.ID, h, n)
+			signResult, err := h.Result()
+			require.NoError(t, err)
+			require.IsType(t, &ecdsa.Signature{}, signResult)
+			assert.True(t, signResult.(*ecdsa.Signature).Verify(publicKey, message))
+		}(configs[id])
+	}
+	wg.Wait()
+}
+
+func TestRotateRID(t *testing.T) {
+	group := curve.Secp256k1{}
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	N := 3
+	configs, partyIDs := test.GenerateConfig(group, N, N-1, rand.Reader, pl)
+	publicKey := configs[partyIDs[0]].PublicPoint()
+	message := []byte("hello")
+
+	n := test.NewNetwork(partyIDs)
+	var mtx sync.Mutex
+	rids := make(map[party.ID][]byte, N)
+	var wg sync.WaitGroup
+	wg.Add(N)
+	for _, id := range partyIDs {
+		go func(old *Config) {
+			defer wg.Done()
+			h, err := protocol.NewMultiHandler(RotateRID(old, pl), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(old.ID, h, n)
+			r, err := h.Result()
+			require.NoError(t, err)
+			require.IsType(t, &Config{}, r)
+			c := r.(*Config)
+			assert.NotEqual(t, old.RID, c.RID, "the RID should be rotated")
+			assert.True(t, publicKey.Equal(c.PublicPoint()), "the public key should not change")
+			mtx.Lock()
+			rids[c.ID] = c.RID
+			mtx.Unlock()
+
+			h, err = protocol.NewMultiHandler(Sign(c, partyIDs, message, pl), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(c.ID, h, n)
+			signResult, err := h.Result()
+			require.NoError(t, err)
+			require.IsType(t, &ecdsa.Signature{}, signResult)
+			assert.True(t, signResult.(*ecdsa.Signature).Verify(publicKey, message))
+		}(configs[id])
+	}
+	wg.Wait()
+	for _, id := range partyIDs {
+		assert.Equal(t, rids[partyIDs[0]], rids[id], "all parties should agree on the new RID")
+	}
+}
+
+func TestLowerThreshold(t *testing.T) {
+	group := curve.Secp256k1{}
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	N := 3
+	configs, partyIDs := test.GenerateConfig(group, N, N-1, rand.Reader, pl)
+	publicKey := configs[partyIDs[0]].PublicPoint()
+
+	_, err := LowerThreshold(configs[partyIDs[0]], 1, false, pl)(nil)
+	assert.Error(t, err, "lowering the threshold should require an acknowledgement")
+	_, err = LowerThreshold(configs[partyIDs[0]], N-1, IUnderstandThisLowersTheThreshold, pl)(nil)
+	assert.Error(t, err, "the threshold should be lowered")
+
+	newConfigs := make(map[party.ID]*Config, N)
+	var mtx sync.Mutex
+	n := test.NewNetwork(partyIDs)
+	var wg sync.WaitGroup
+	wg.Add(N)
+	for _, id := range partyIDs {
+		go func(old *Config) {
+			defer wg.Done()
+			h, err := protocol.NewMultiHandler(LowerThreshold(old, 1, IUnderstandThisLowersTheThreshold, pl), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(old.ID, h, n)
+			r, err := h.Result()
+			require.NoError(t, err)
+			require.IsType(t, &Config{}, r)
+			mtx.Lock()
+			newConfigs[old.ID] = r.(*Config)
+			mtx.Unlock()
+		}(configs[id])
+	}
+	wg.Wait()
+
+	for _, c := range newConfigs {
+		assert.Equal(t, 1, c.Threshold)
+		assert.True(t, publicKey.Equal(c.PublicPoint()), "the public key should not change")
+		assert.NoError(t, c.Validate())
+		require.NotEmpty(t, c.History)
+		last := c.History[len(c.History)-1]
+		assert.Equal(t, config.OperationReshare, last.Operation)
+		assert.Equal(t, configs[c.ID].RID, last.ParentRID, "the history should reference the parent config")
+		assert.Equal(t, 1, last.Threshold)
+	}
+
+	// a quorum of the new size signs
+	message := []byte("hello")
+	signers := partyIDs[:2]
+	n = test.NewNetwork(signers)
+	wg.Add(len(signers))
+	for _, id := range signers {
+		go func(c *Config) {
+			defer wg.Done()
+			h, err := protocol.NewMultiHandler(Sign(c, signers, message, pl), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(c.ID, h, n)
+			r, err := h.Result()
+			require.NoError(t, err)
+			require.IsType(t, &ecdsa.Signature{}, r)
+			assert.True(t, r.(*ecdsa.Signature).Verify(publicKey, message))
+		}(newConfigs[id])
+	}
+	wg.Wait()
+}
+
+func TestRestartSign(t *testing.T) {
+	group := curve.Secp256k1{}
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	N := 3
+	configs, partyIDs := test.GenerateConfig(group, N, 1, rand.Reader, pl)
+	publicKey := configs[partyIDs[0]].PublicPoint()
+	message := []byte("hello")
+	self, dropped, substitute := partyIDs[0], partyIDs[1], partyIDs[2]
+
+	// dropped never sends its first message, so the session of self stalls in round 1
+	stalled, err := protocol.NewMultiHandler(Sign(configs[self], party.IDSlice{self, dropped}, message, pl), []byte("session 1"))
+	require.NoError(t, err)
+	// the messages of round 1 were sent, but dropped never answers
+	for len(stalled.Listen()) > 0 {
+		<-stalled.Listen()
+	}
+
+	// a set of signers which can't sign is rejected, and the old session is kept
+	_, err = RestartSign(stalled, configs[self], party.IDSlice{self}, message, pl)([]byte("session 2"))
+	assert.Error(t, err, "a single signer is not enough for threshold 1")
+	assert.NotZero(t, stalled.CurrentRound(), "the stalled session should not be stopped")
+
+	signers := party.IDSlice{self, substitute}
+	start := RestartSign(stalled, configs[self], signers, message, pl)
+
+	n := test.NewNetwork(signers)
+	var wg sync.WaitGroup
+	wg.Add(len(signers))
+	for _, id := range signers {
+		create := start
+		if id == substitute {
+			create = Sign(configs[id], signers, message, pl)
+		}
+		go func(id party.ID, create protocol.StartFunc) {
+			defer wg.Done()
+			h, err := protocol.NewMultiHandler(create, []byte("session 2"))
+			require.NoError(t, err)
+			test.HandlerLoop(id, h, n)
+			r, err := h.Result()
+			require.NoError(t, err)
+			require.IsType(t, &ecdsa.Signature{}, r)
+			assert.True(t, r.(*ecdsa.Signature).Verify(publicKey, message))
+		}(id, create)
+	}
+	wg.Wait()
+
+	_, err = stalled.Result()
+	assert.Error(t, err, "the stalled session should be aborted")
+	for msg := range stalled.Listen() {
+		assert.Zero(t, msg.RoundNumber, "the stalled session should only send an abort")
+	}
+}
+
+func TestKeygenWithSafePrimeProofs(t *testing.T) {
+	group := curve.Secp256k1{}
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	N := 2
+	partyIDs := test.PartyIDs(N)
+
+	n := test.NewNetwork(partyIDs)
+	var wg sync.WaitGroup
+	wg.Add(N)
+	for _, id := range partyIDs {
+		go func(id party.ID) {
+			defer wg.Done()
+			h, err := protocol.NewMultiHandler(KeygenWithSafePrimeProofs(group, id, partyIDs, N-1, pl), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(id, h, n)
+			r, err := h.Result()
+			require.NoError(t, err)
+			require.IsType(t, &Config{}, r)
+			c := r.(*Config)
+			assert.NoError(t, c.VerifySafePrimes(pl))
+
+			data, err := cbor.Marshal(c)
+			require.NoError(t, err)
+			decoded := EmptyConfig(group)
+			require.NoError(t, cbor.Unmarshal(data, decoded))
+			assert.NoError(t, decoded.VerifySafePrimes(pl), "the proofs should survive marshalling")
+
+			// the proofs are bound to the party which generated them
+			other := partyIDs[0]
+			if other == id {
+				other = partyIDs[1]
+			}
+			decoded.Public[other].SafePrime = decoded.Public[id].SafePrime
+			assert.Error(t, decoded.VerifySafePrimes(pl))
+		}(id)
+	}
+	wg.Wait()
+}
+
+func TestKeygenSchnorr(t *testing.T) {
+	group := curve.Secp256k1{}
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	N, T := 3, 1
+	partyIDs := test.PartyIDs(N)
+
+	configs := make(map[party.ID]*Config, N)
+	var mtx sync.Mutex
+	n := test.NewNetwork(partyIDs)
+	var wg sync.WaitGroup
+	wg.Add(N)
+	for _, id := range partyIDs {
+		go func(id party.ID) {
+			defer wg.Done()
+			h, err := protocol.NewMultiHandler(KeygenSchnorr(group, id, partyIDs, T, pl), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(id, h, n)
+			r, err := h.Result()
+			require.NoError(t, err)
+			require.IsType(t, &Config{}, r)
+			mtx.Lock()
+			configs[id] = r.(*Config)
+			mtx.Unlock()
+		}(id)
+	}
+	wg.Wait()
+	publicKey := configs[partyIDs[0]].PublicPoint()
+	for _, c := range configs {
+		// no Paillier key was generated
+		assert.True(t, c.SchnorrOnly())
+		assert.Nil(t, c.Paillier)
+		assert.NoError(t, c.Validate())
+		assert.True(t, publicKey.Equal(c.PublicPoint()))
+		for _, j := range partyIDs {
+			assert.Nil(t, c.Public[j].Paillier)
+			assert.Nil(t, c.Public[j].Pedersen)
+		}
+
+		data, err := cbor.Marshal(c)
+		require.NoError(t, err)
+		decoded := EmptyConfig(group)
+		require.NoError(t, cbor.Unmarshal(data, decoded))
+		assert.True(t, decoded.SchnorrOnly())
+		assert.Empty(t, c.Diff(decoded))
+
+		_, err = Sign(c, partyIDs, []byte("hello"), pl)(nil)
+		assert.Error(t, err, "ECDSA signing requires Paillier keys")
+		_, err = Presign(c, partyIDs, pl)(nil)
+		assert.Error(t, err, "ECDSA presigning requires Paillier keys")
+	}
+
+	message := []byte("hello")
+	signers := partyIDs[:T+1]
+	// the shares of -X, whose y coordinate has the other parity, are found by negating all shares
+	negated := make(map[party.ID]*Config, N)
+	for id, c := range configs {
+		public := make(map[party.ID]*config.Public, N)
+		for j, p := range c.Public {
+			copied := *p
+			copied.ECDSA = p.ECDSA.Negate()
+			public[j] = &copied
+		}
+		copied := *c
+		copied.ECDSA = group.NewScalar().Set(c.ECDSA).Negate()
+		copied.Public = public
+		negated[id] = &copied
+	}
+
+	// the signature verifies under the x coordinate of the key, whatever the parity of its y coordinate
+	xOnly := taproot.PublicKey(publicKey.(*curve.Secp256k1Point).XBytes())
+	for _, configs := range []map[party.ID]*Config{configs, negated} {
+		n = test.NewNetwork(signers)
+		wg.Add(len(signers))
+		for _, id := range signers {
+			go func(c *Config) {
+				defer wg.Done()
+				h, err := protocol.NewMultiHandler(SignSchnorr(c, signers, message), nil)
+				require.NoError(t, err)
+				test.HandlerLoop(c.ID, h, n)
+				r, err := h.Result()
+				require.NoError(t, err)
+				require.IsType(t, taproot.Signature{}, r)
+				assert.True(t, xOnly.Verify(r.(taproot.Signature), message))
+			}(configs[id])
+		}
+		wg.Wait()
+	}
+}
+
+func TestProfiledSign(t *testing.T) {
+	group := curve.Secp256k1{}
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	configs, partyIDs := test.GenerateConfig(group, 2, 1, rand.Reader, pl)
+	message := []byte("hello")
+
+	n := test.NewNetwork(partyIDs)
+	var wg sync.WaitGroup
+	wg.Add(len(partyIDs))
+	for _, id := range partyIDs {
+		go func(c *Config) {
+			defer wg.Done()
+			h, err := protocol.NewProfiledMultiHandler(Sign(c, partyIDs, message, pl), nil, protocol.Limits{})
+			require.NoError(t, err)
+			test.HandlerLoop(c.ID, h, n)
+			_, err = h.Result()
+			require.NoError(t, err)
+
+			profile := h.Profile()
+			require.NotNil(t, profile)
+			require.Len(t, profile.Rounds, 5, "one timing per round")
+			var sum time.Duration
+			for i, r := range profile.Rounds {
+				assert.EqualValues(t, i+1, r.Round)
+				sum += r.Duration
+			}
+			assert.InDelta(t, profile.Total, sum, float64(time.Millisecond), "rounds should add up to the total")
+			for _, name := range []string{"verify", "finalize", "paillier encryption", "zkenc proofs", "mta", "mta verification"} {
+				assert.NotZero(t, profile.Phase(name), "missing phase %s", name)
+			}
+		}(configs[id])
+	}
+	wg.Wait()
+
+	h, err := protocol.NewMultiHandler(Sign(configs[partyIDs[0]], partyIDs, message, pl), nil)
+	require.NoError(t, err)
+	assert.Nil(t, h.Profile(), "profiling should be disabled by default")
+}
+
+func TestUpdatePublic(t *testing.T) {
+	group := curve.Secp256k1{}
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	configs, partyIDs := test.GenerateConfig(group, 3, 1, rand.Reader, pl)
+	rotated := partyIDs[1]
+
+	// the rotating party generates new auxiliary parameters, and every party updates its config
+	paillierSecret := paillier.NewSecretKey(pl)
+	pedersenPublic, _ := paillierSecret.GeneratePedersen()
+	public := *configs[rotated].Public[rotated]
+	public.Paillier = paillierSecret.PublicKey
+	public.Pedersen = pedersenPublic
+
+	updated := make(map[party.ID]*Config, len(configs))
+	for id, c := range configs {
+		if id == rotated {
+			withSecret := *c
+			withSecret.Paillier = paillierSecret
+			c = &withSecret
+		}
+		u, err := c.UpdatePublic(rotated, &public)
+		require.NoError(t, err)
+		updated[id] = u
+	}
+	assert.NotEqual(t, configs[partyIDs[0]].Public[rotated].Paillier.N().Big(), updated[partyIDs[0]].Public[rotated].Paillier.N().Big(),
+		"the original config should not change")
+
+	_, err := configs[partyIDs[0]].UpdatePublic(rotated, &config.Public{
+		ECDSA:    configs[partyIDs[0]].Public[partyIDs[0]].ECDSA,
+		ElGamal:  public.ElGamal,
+		Paillier: public.Paillier,
+		Pedersen: public.Pedersen,
+	})
+	assert.Error(t, err, "the ECDSA share should not change")
+
+	message := []byte("hello")
+	signers := partyIDs[:2]
+	publicKey := configs[partyIDs[0]].PublicPoint()
+	n := test.NewNetwork(signers)
+	var wg sync.WaitGroup
+	wg.Add(len(signers))
+	for _, id := range signers {
+		go func(c *Config) {
+			defer wg.Done()
+			h, err := protocol.NewMultiHandler(Sign(c, signers, message, pl), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(c.ID, h, n)
+			r, err := h.Result()
+			require.NoError(t, err)
+			assert.True(t, r.(*ecdsa.Signature).Verify(publicKey, message))
+		}(updated[id])
+	}
+	wg.Wait()
+}
+
+func TestVerifyWithDerivedKey(t *testing.T) {
+	group := curve.Secp256k1{}
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	configs, partyIDs := test.GenerateConfig(group, 2, 1, rand.Reader, pl)
+	root := configs[partyIDs[0]].PublicConfig()
+	path := "m/44/0/7"
+	message := []byte("hello")
+
+	n := test.NewNetwork(partyIDs)
+	var wg sync.WaitGroup
+	wg.Add(len(partyIDs))
+	for _, id := range partyIDs {
+		go func(c *Config) {
+			defer wg.Done()
+			child, err := c.DeriveBIP32Path(path)
+			require.NoError(t, err)
+			h, err := protocol.NewMultiHandler(Sign(child, partyIDs, message, pl), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(c.ID, h, n)
+			r, err := h.Result()
+			require.NoError(t, err)
+			sig := r.(*ecdsa.Signature)
+
+			ok, err := VerifyWithDerivedKey(root, path, message, sig)
+			require.NoError(t, err)
+			assert.True(t, ok, "signature should verify with the derived public key")
+			ok, err = VerifyWithDerivedKey(root, "m/44/0/8", message, sig)
+			require.NoError(t, err)
+			assert.False(t, ok, "signature should not verify with a sibling key")
+			_, err = VerifyWithDerivedKey(root, "m/44'/0/7", message, sig)
+			assert.Error(t, err, "hardened paths can't be derived")
+		}(configs[id])
+	}
+	wg.Wait()
+}
+
+func TestForSigners(t *testing.T) {
+	group := curve.Secp256k1{}
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	configs, partyIDs := test.GenerateConfig(group, 4, 1, rand.Reader, pl)
+	signers := partyIDs[2:]
+	message := []byte("hello")
+	publicKey := configs[partyIDs[0]].PublicPoint()
+
+	_, err := configs[partyIDs[0]].ForSigners(signers)
+	assert.Error(t, err, "signers should include the owner of the config")
+	_, err = configs[signers[0]].ForSigners(signers[:1])
+	assert.Error(t, err, "signers should be more than the threshold")
+
+	n := test.NewNetwork(signers)
+	var wg sync.WaitGroup
+	wg.Add(len(signers))
+	for _, id := range signers {
+		go func(c *Config) {
+			defer wg.Done()
+			minimized, err := c.ForSigners(signers)
+			require.NoError(t, err)
+			assert.Len(t, minimized.Public, len(signers))
+			assert.True(t, publicKey.Equal(minimized.PublicPoint()), "the public key should not change")
+			full, _ := c.MarshalBinary()
+			small, _ := minimized.MarshalBinary()
+			assert.Less(t, len(small), len(full))
+
+			h, err := protocol.NewMultiHandler(Sign(minimized, signers, message, pl), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(c.ID, h, n)
+			r, err := h.Result()
+			require.NoError(t, err)
+			assert.True(t, r.(*ecdsa.Signature).Verify(publicKey, message))
+		}(configs[id])
+	}
+	wg.Wait()
+}
+
+// unhealthySource is a RandSource which fails its health check once broken is set,
+// or once it has been read from failAfter times, if failAfter is positive.
+type unhealthySource struct {
+	broken           bool
+	reads, failAfter int64
+}
+
+func (s *unhealthySource) Read(p []byte) (int, error) {
+	atomic.AddInt64(&s.reads, 1)
+	return rand.Read(p)
+}
+func (s *unhealthySource) HealthCheck() error {
+	if s.broken || (s.failAfter > 0 && atomic.LoadInt64(&s.reads) >= s.failAfter) {
+		return errors.New("repetition count test failed")
+	}
+	return nil
+}
+
+func TestUnhealthyRandSource(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+	configs, partyIDs := test.GenerateConfig(group, 2, 1, rand.Reader, pl)
+
+	source := &unhealthySource{}
+	defer sample.SetRandSource(sample.SetRandSource(source))
+
+	source.broken = true
+	_, err := protocol.NewMultiHandler(Keygen(group, partyIDs[0], partyIDs, 1, pl), nil)
+	assert.ErrorIs(t, err, sample.ErrUnhealthy, "keygen should not start with an unhealthy source")
+	_, err = protocol.NewMultiHandler(Sign(configs[partyIDs[0]], partyIDs, []byte("hello"), pl), nil)
+	assert.ErrorIs(t, err, sample.ErrUnhealthy, "sign should not start with an unhealthy source")
+
+	// the source fails during the protocol
+	source.broken = false
+	handlers := make(map[party.ID]*protocol.MultiHandler, len(partyIDs))
+	for _, id := range partyIDs {
+		h, err := protocol.NewMultiHandler(Sign(configs[id], partyIDs, []byte("hello"), pl), nil)
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	source.broken = true
+	test.Deliver(handlers, nil)
+	for _, id := range partyIDs {
+		_, err := handlers[id].Result()
+		assert.ErrorIs(t, err, sample.ErrUnhealthy, "sign should abort when the source becomes unhealthy")
+	}
+
+	// the source fails inside Finalize, while sampling the Paillier primes of the first round of keygen
+	source.broken = false
+	source.reads, source.failAfter = 0, 2
+	h, err := protocol.NewMultiHandler(Keygen(group, partyIDs[0], partyIDs, 1, pl), nil)
+	require.NoError(t, err)
+	_, err = h.Result()
+	assert.ErrorIs(t, err, sample.ErrUnhealthy, "keygen should abort instead of hanging or panicking")
+}
+
+// runCheckingRounds delivers the messages between the handlers until they all finish,
+// and checks that the round each handler reports only moves forward.
+func runCheckingRounds(t *testing.T, handlers map[party.ID]*protocol.MultiHandler) {
+	seen := make(map[party.ID][]int, len(handlers))
+	for id, h := range handlers {
+		seen[id] = []int{h.CurrentRound()}
+	}
+	test.Deliver(handlers, func(_, to party.ID, msg *protocol.Message) {
+		handlers[to].Accept(msg)
+		if r := handlers[to].CurrentRound(); r != seen[to][len(seen[to])-1] {
+			seen[to] = append(seen[to], r)
+		}
+	})
+	for id, h := range handlers {
+		_, err := h.Result()
+		require.NoError(t, err)
+		total := h.TotalRounds()
+		expected := make([]int, 0, total)
+		for r := 2; r <= total; r++ {
+			expected = append(expected, r)
+		}
+		expected = append(expected, 0)
+		assert.Equal(t, expected, seen[id], "party %s should go through every round in order", id)
+	}
+}
+
+func TestCurrentRound(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+	partyIDs := test.PartyIDs(3)
+
+	handlers := make(map[party.ID]*protocol.MultiHandler, len(partyIDs))
+	for _, id := range partyIDs {
+		h, err := protocol.NewMultiHandler(Keygen(group, id, partyIDs, 1, pl), nil)
+		require.NoError(t, err)
+		assert.Equal(t, 2, h.CurrentRound(), "the first round needs no messages")
+		assert.Equal(t, 5, h.TotalRounds())
+		handlers[id] = h
+	}
+	runCheckingRounds(t, handlers)
+
+	configs := make(map[party.ID]*config.Config, len(partyIDs))
+	for _, id := range partyIDs {
+		r, err := handlers[id].Result()
+		require.NoError(t, err)
+		configs[id] = r.(*config.Config)
+	}
+
+	signers := partyIDs[:2]
+	handlers = make(map[party.ID]*protocol.MultiHandler, len(signers))
+	for _, id := range signers {
+		h, err := protocol.NewMultiHandler(Sign(configs[id], signers, []byte("hello"), pl), nil)
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	runCheckingRounds(t, handlers)
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"errors"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/bitcoin"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/taproot"
+)
+
+// P2WPKHAddress returns the Bitcoin P2WPKH address of the public key of c, for the human-readable part hrp,
+// such as bitcoin.MainnetHRP or bitcoin.TestnetHRP.
+func (c *Config) P2WPKHAddress(hrp string) (string, error) {
+	return c.PublicConfig().P2WPKHAddress(hrp)
+}
+
+// P2TRAddress returns the Bitcoin P2TR address, without script path, whose internal key is the public key of c.
+//
+// As required by BIP-341, the internal key is the x-only encoding of the public key, and the address
+// commits to the output key obtained by tweaking it. Spending requires a Schnorr signature for the output key.
+func (c *Config) P2TRAddress(hrp string) (string, error) {
+	return c.PublicConfig().P2TRAddress(hrp)
+}
+
+// P2WPKHAddress returns the Bitcoin P2WPKH address of p.PublicKey, as in Config.P2WPKHAddress.
+//
+// Combined with DeriveBIP32Path, this gives the addresses of the children of a Config.
+func (p *PublicConfig) P2WPKHAddress(hrp string) (string, error) {
+	publicKey, err := p.secp256k1PublicKey()
+	if err != nil {
+		return "", err
+	}
+	return bitcoin.P2WPKHAddress(hrp, publicKey)
+}
+
+// P2TRAddress returns the Bitcoin P2TR address whose internal key is p.PublicKey, as in Config.P2TRAddress.
+func (p *PublicConfig) P2TRAddress(hrp string) (string, error) {
+	publicKey, err := p.secp256k1PublicKey()
+	if err != nil {
+		return "", err
+	}
+	return bitcoin.P2TRAddress(hrp, taproot.PublicKey(publicKey.XBytes()), nil)
+}
+
+func (p *PublicConfig) secp256k1PublicKey() (*curve.Secp256k1Point, error) {
+	publicKey, ok := p.PublicKey.(*curve.Secp256k1Point)
+	if !ok {
+		return nil, errors.New("config: Bitcoin addresses require secp256k1")
+	}
+	return publicKey, nil
+}
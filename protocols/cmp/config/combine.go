@@ -0,0 +1,86 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// Combine returns a config for the sum of the keys shared in a and b,
+// which were generated independently for the same parties, with the same threshold.
+//
+// The shares and public shares of a and b are added, so that the public key of the result is
+// a.PublicPoint() + b.PublicPoint(). The ElGamal, Paillier and Pedersen keys are taken from a,
+// and the RID and chain key are the XOR of those of a and b, so that every party obtains
+// the same public data when combining its own configs in the same order.
+func Combine(a, b *Config) (*Config, error) {
+	if a == nil || b == nil {
+		return nil, errors.New("config: cannot combine a nil config")
+	}
+	if a.Group == nil || b.Group == nil || a.Group.Name() != b.Group.Name() {
+		return nil, errors.New("config: combined configs must be over the same group")
+	}
+	if a.ID != b.ID {
+		return nil, fmt.Errorf("config: combined configs belong to different parties %s and %s", a.ID, b.ID)
+	}
+	if a.Threshold != b.Threshold {
+		return nil, fmt.Errorf("config: combined configs have thresholds %d and %d", a.Threshold, b.Threshold)
+	}
+	if len(a.Public) != len(b.Public) {
+		return nil, errors.New("config: combined configs must have the same parties")
+	}
+	for j := range a.Public {
+		if _, ok := b.Public[j]; !ok {
+			return nil, fmt.Errorf("config: party %s is missing from the second config", j)
+		}
+	}
+	if err := a.RID.Validate(); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if err := b.RID.Validate(); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if err := checkChainKey(a.ChainKey); err != nil {
+		return nil, err
+	}
+	if err := checkChainKey(b.ChainKey); err != nil {
+		return nil, err
+	}
+	if a.ECDSA == nil || b.ECDSA == nil {
+		return nil, errors.New("config: missing secret keys")
+	}
+
+	public := make(map[party.ID]*Public, len(a.Public))
+	for j, p := range a.Public {
+		public[j] = &Public{
+			ECDSA:     p.ECDSA.Add(b.Public[j].ECDSA),
+			ElGamal:   p.ElGamal,
+			Paillier:  p.Paillier,
+			Pedersen:  p.Pedersen,
+			SafePrime: p.SafePrime,
+		}
+	}
+	rid := a.RID.Copy()
+	rid.XOR(b.RID)
+	chainKey := types.RID(a.ChainKey).Copy()
+	chainKey.XOR(b.ChainKey)
+
+	combined := &Config{
+		Group:     a.Group,
+		ID:        a.ID,
+		Threshold: a.Threshold,
+		ECDSA:     a.Group.NewScalar().Set(a.ECDSA).Add(b.ECDSA),
+		ElGamal:   a.ElGamal,
+		Paillier:  a.Paillier,
+		RID:       rid,
+		ChainKey:  chainKey,
+		Public:    public,
+		History:   NextHistory(a, OperationCombine, a.Threshold),
+	}
+	if err := combined.Validate(); err != nil {
+		return nil, err
+	}
+	return combined, nil
+}
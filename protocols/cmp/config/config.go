@@ -7,21 +7,45 @@ import (
 	"math"
 
 	"github.com/taurusgroup/multi-party-sig/internal/bip32"
-	"github.com/taurusgroup/multi-party-sig/internal/params"
 	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/pedersen"
+	zksafeprime "github.com/taurusgroup/multi-party-sig/pkg/zk/safeprime"
 )
 
+// maxPartyCount is the largest number of parties accepted by Config.Validate and by the keygen protocol.
+//
+// The work done by each party grows quadratically with the number of parties,
+// so this prevents a node from being overwhelmed by an unreasonably large party set.
+const maxPartyCount = 1024
+
+// CheckPartyCount returns an error if n parties are more than Config.Validate and the keygen protocol accept.
+func CheckPartyCount(n int) error {
+	if n > maxPartyCount {
+		return fmt.Errorf("config: %d parties exceeds the maximum of %d", n, maxPartyCount)
+	}
+	return nil
+}
+
+// ErrIdentityPublicKey is returned when the public key of a config is the identity,
+// for instance because the contributions of the parties to a keygen cancel out.
+var ErrIdentityPublicKey = errors.New("config: public key is the identity")
+
 // Config contains all necessary cryptographic keys necessary to generate a signature.
 // It also represents the `SSID` after having performed a keygen/refresh operation.
 // where SSID = (𝔾, t, n, P₁, …, Pₙ, (X₁, Y₁, N₁, s₁, t₁), …, (Xₙ, Yₙ, Nₙ, sₙ, tₙ)).
 //
 // To unmarshal this struct, EmptyConfig should be called first with a specific group,
 // before using cbor.Unmarshal with that struct.
+//
+// A Config is safe for concurrent use by multiple goroutines, as long as none of them modifies it.
+// The methods of Config never modify it. The configs they return, for instance by Derive,
+// may share some of its keys, which must then be treated as read-only as well.
 type Config struct {
 	// Group returns the Elliptic Curve Group associated with this config.
 	Group curve.Curve
@@ -35,6 +59,7 @@ type Config struct {
 	// ElGamal is this party's yᵢ used for ElGamal.
 	ElGamal curve.Scalar
 	// Paillier is this party's Paillier decryption key.
+	// It is nil for a config generated for Schnorr signatures only, see SchnorrOnly.
 	Paillier *paillier.SecretKey
 	// RID is a 32 byte random identifier generated for this config
 	RID types.RID
@@ -42,6 +67,9 @@ type Config struct {
 	ChainKey types.RID
 	// Public maps party.ID to public. It contains all public information associated to a party.
 	Public map[party.ID]*Public
+	// History is the append-only list of operations which led to this config, starting with its keygen.
+	// It is empty for configs created before it was recorded, and is not part of the SSID.
+	History []HistoryEntry
 }
 
 // Public holds public information for a party.
@@ -54,11 +82,30 @@ type Public struct {
 	Paillier *paillier.PublicKey
 	// Pedersen is this party's public Pedersen parameters.
 	Pedersen *pedersen.Parameters
+	// SafePrime is a proof that Paillier is made of safe primes, see Config.VerifySafePrimes.
+	// It is only generated on request, and is not part of the SSID.
+	SafePrime *zksafeprime.Proof
+}
+
+// SchnorrOnly returns true if c was generated without Paillier and Pedersen parameters.
+//
+// Such a config is lighter to generate and store, but it can only be used by protocols which do not
+// rely on Paillier encryption, such as Schnorr signing. The CMP ECDSA signing protocols reject it.
+func (c *Config) SchnorrOnly() bool {
+	return c.Paillier == nil
+}
+
+// HasSecret returns true if c contains the secret ECDSA share of its party.
+//
+// It returns false for a config holding only public data, for instance one unmarshalled by mistake
+// from an export of the public parameters, which cannot be used to sign.
+func (c *Config) HasSecret() bool {
+	return c.ECDSA != nil && !c.ECDSA.IsZero()
 }
 
 // PublicPoint returns the group's public ECC point.
 func (c *Config) PublicPoint() curve.Point {
-	sum := c.Group.NewPoint()
+	sum := curve.Identity(c.Group)
 	partyIDs := make([]party.ID, 0, len(c.Public))
 	for j := range c.Public {
 		partyIDs = append(partyIDs, j)
@@ -80,6 +127,14 @@ func (c *Config) PartyIDs() party.IDSlice {
 }
 
 // WriteTo implements io.WriterTo interface.
+//
+// This is the data hashed into the SSID of every protocol using c, so the layout must not change:
+//   - t as a big-endian uint32,
+//   - the number of parties as a big-endian uint64, followed by the sorted IDs,
+//   - the RID,
+//   - for each party in the same order, Public.WriteTo.
+//
+// All integers are written in big-endian order.
 func (c *Config) WriteTo(w io.Writer) (total int64, err error) {
 	if c == nil {
 		return 0, io.ErrUnexpectedEOF
@@ -125,12 +180,164 @@ func (c *Config) Domain() string {
 	return "CMP Config"
 }
 
+// ChainKeyLength is the length in bytes of Config.ChainKey, which is used as the chain code of BIP32 derivations.
+const ChainKeyLength = 32
+
+// checkChainKey returns an error if chainKey does not have ChainKeyLength bytes.
+func checkChainKey(chainKey []byte) error {
+	if len(chainKey) != ChainKeyLength {
+		return fmt.Errorf("config: expected %d bytes for chain key, found %d", ChainKeyLength, len(chainKey))
+	}
+	return nil
+}
+
+// Validate checks that c is consistent: the number of parties is accepted by CheckPartyCount,
+// the threshold is valid for the number of parties, the chain key has ChainKeyLength bytes,
+// the public data of every party is valid and matches the secret keys of c,
+// and no two parties share the same ECDSA public share or Paillier modulus.
+//
+// If c is SchnorrOnly, no party may have Paillier or Pedersen parameters.
+func (c *Config) Validate() error {
+	if c == nil || c.Group == nil {
+		return errors.New("config: missing group")
+	}
+	if err := CheckPartyCount(len(c.Public)); err != nil {
+		return err
+	}
+	if !ValidThreshold(c.Threshold, len(c.Public)) {
+		return fmt.Errorf("config: threshold %d is invalid", c.Threshold)
+	}
+	if err := checkChainKey(c.ChainKey); err != nil {
+		return err
+	}
+	if c.ECDSA == nil || c.ElGamal == nil {
+		return errors.New("config: missing secret keys")
+	}
+	if c.ECDSA.IsZero() || c.ElGamal.IsZero() {
+		return errors.New("config: ECDSA or ElGamal secret key is zero")
+	}
+	if err := curve.CheckCurve(c.Group, c.ECDSA, c.ElGamal); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	schnorrOnly := c.SchnorrOnly()
+	ecdsaOwners := make(map[string]party.ID, len(c.Public))
+	paillierOwners := make(map[string]party.ID, len(c.Public))
+	for _, j := range c.PartyIDs() {
+		public := c.Public[j]
+		if err := public.Validate(c.Group); err != nil {
+			return fmt.Errorf("config: party %s: %w", j, err)
+		}
+		if (public.Paillier == nil) != schnorrOnly {
+			return fmt.Errorf("config: party %s: Paillier parameters do not match this config", j)
+		}
+
+		ecdsa, err := public.ECDSA.MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("config: party %s: %w", j, err)
+		}
+		if other, ok := ecdsaOwners[string(ecdsa)]; ok {
+			return fmt.Errorf("config: parties %s and %s have the same ECDSA public share", other, j)
+		}
+		ecdsaOwners[string(ecdsa)] = j
+
+		if schnorrOnly {
+			continue
+		}
+		n := string(public.Paillier.N().Bytes())
+		if other, ok := paillierOwners[n]; ok {
+			return fmt.Errorf("config: parties %s and %s have the same Paillier modulus", other, j)
+		}
+		paillierOwners[n] = j
+	}
+
+	if err := polynomial.CheckInterpolationDomain(c.Group, c.PartyIDs()); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	if c.PublicPoint().IsIdentity() {
+		return ErrIdentityPublicKey
+	}
+
+	public, ok := c.Public[c.ID]
+	if !ok {
+		return errors.New("config: no public data for this party")
+	}
+	if !c.ECDSA.ActOnBase().Equal(public.ECDSA) || !c.ElGamal.ActOnBase().Equal(public.ElGamal) ||
+		(!schnorrOnly && c.Paillier.N().Nat().Eq(public.Paillier.N().Nat()) != 1) {
+		return errors.New("config: public data does not match the secret keys of this party")
+	}
+	return nil
+}
+
+// Validate checks that all fields of p are set, that its points belong to group and are not the identity,
+// and that its Paillier and Pedersen parameters are well formed and use the same modulus.
+// The Paillier and Pedersen parameters may both be nil, for a SchnorrOnly config.
+func (p *Public) Validate(group curve.Curve) error {
+	if p == nil || p.ECDSA == nil || p.ElGamal == nil || (p.Paillier == nil) != (p.Pedersen == nil) {
+		return errors.New("public: missing fields")
+	}
+	if err := curve.CheckCurve(group, p.ECDSA, p.ElGamal); err != nil {
+		return fmt.Errorf("public: %w", err)
+	}
+	if p.ECDSA.IsIdentity() || p.ElGamal.IsIdentity() {
+		return errors.New("public: ECDSA or ElGamal public key is identity")
+	}
+	if p.Paillier == nil {
+		return nil
+	}
+	if err := paillier.ValidateN(p.Paillier.N()); err != nil {
+		return fmt.Errorf("public: %w", err)
+	}
+	if err := pedersen.ValidateParameters(p.Pedersen.N(), p.Pedersen.S(), p.Pedersen.T()); err != nil {
+		return fmt.Errorf("public: %w", err)
+	}
+	// the Pedersen parameters are generated from the Paillier key, so checking that
+	// the Paillier moduli are distinct across parties also covers the Pedersen ones
+	if p.Pedersen.N().Nat().Eq(p.Paillier.N().Nat()) != 1 {
+		return errors.New("public: Pedersen and Paillier moduli differ")
+	}
+	return nil
+}
+
+// UpdatePublic returns a copy of c in which the Public entry of party id is replaced by newPublic,
+// for instance after that party refreshed its auxiliary Paillier and Pedersen parameters.
+//
+// newPublic must be valid and keep the same ECDSA share. The other entries are shared with c.
+// When id is this party, the secret keys of c must already match newPublic.
+func (c *Config) UpdatePublic(id party.ID, newPublic *Public) (*Config, error) {
+	old, ok := c.Public[id]
+	if !ok {
+		return nil, fmt.Errorf("config: party %s: not in the party set", id)
+	}
+	if err := newPublic.Validate(c.Group); err != nil {
+		return nil, fmt.Errorf("config: party %s: %w", id, err)
+	}
+	if !newPublic.ECDSA.Equal(old.ECDSA) {
+		return nil, fmt.Errorf("config: party %s: the ECDSA share cannot be updated", id)
+	}
+
+	updated := *c
+	updated.Public = make(map[party.ID]*Public, len(c.Public))
+	for j, p := range c.Public {
+		updated.Public[j] = p
+	}
+	updated.Public[id] = newPublic
+	if err := updated.Validate(); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
 // Domain implements hash.WriterToWithDomain.
 func (Public) Domain() string {
 	return "Public Data"
 }
 
 // WriteTo implements io.WriterTo interface.
+//
+// It writes the compressed points Xⱼ and Yⱼ, the Paillier modulus Nⱼ in big-endian order,
+// and the Pedersen parameters (Nⱼ, sⱼ, tⱼ), each as a big-endian integer left-padded to params.BytesIntModN bytes.
+// The Paillier and Pedersen parameters are omitted if they are nil, for a SchnorrOnly config.
 func (p *Public) WriteTo(w io.Writer) (total int64, err error) {
 	if p == nil {
 		return 0, io.ErrUnexpectedEOF
@@ -157,6 +364,10 @@ func (p *Public) WriteTo(w io.Writer) (total int64, err error) {
 		return
 	}
 
+	if p.Paillier == nil {
+		return
+	}
+
 	n64, err := p.Paillier.WriteTo(w)
 	total += n64
 	if err != nil {
@@ -172,34 +383,100 @@ func (p *Public) WriteTo(w io.Writer) (total int64, err error) {
 	return
 }
 
-// CanSign returns true if the given _sorted_ list of signers is
-// a valid subset of the original parties of size > t,
-// and includes self.
-func (c *Config) CanSign(signers party.IDSlice) bool {
-	if !ValidThreshold(c.Threshold, len(signers)) {
-		return false
-	}
+// UnknownSignersError is returned by CheckSigners when some of the signers are not parties of the Config.
+type UnknownSignersError struct {
+	// Unknown lists the requested signers which are not in Config.Public, in the order they were given.
+	Unknown party.IDSlice
+}
+
+func (e *UnknownSignersError) Error() string {
+	return fmt.Sprintf("config: signers [%s] are not parties of the config", e.Unknown)
+}
 
+// CheckSigners returns an error describing why the given _sorted_ list of signers can't sign with c.
+// It must be a valid subset of the original parties of size > t, and include self.
+//
+// If some signers are not parties of c, the error is an *UnknownSignersError listing all of them.
+func (c *Config) CheckSigners(signers party.IDSlice) error {
 	// check for duplicates
 	if !signers.Valid() {
-		return false
+		return errors.New("config: signers must be sorted and without duplicates")
+	}
+
+	// check that the signers are a subset of the original parties
+	var unknown party.IDSlice
+	for _, j := range signers {
+		if _, ok := c.Public[j]; !ok {
+			unknown = append(unknown, j)
+		}
+	}
+	if len(unknown) > 0 {
+		return &UnknownSignersError{Unknown: unknown}
 	}
 
 	if !signers.Contains(c.ID) {
+		return fmt.Errorf("config: signers do not include self (%s)", c.ID)
+	}
+
+	if !ValidThreshold(c.Threshold, len(signers)) {
+		return fmt.Errorf("config: %d signers is not enough for threshold %d", len(signers), c.Threshold)
+	}
+	return nil
+}
+
+// CanSign returns true if the given _sorted_ list of signers is
+// a valid subset of the original parties of size > t,
+// and includes self. See CheckSigners for the reason it can't.
+func (c *Config) CanSign(signers party.IDSlice) bool {
+	return c.CheckSigners(signers) == nil
+}
+
+// MinSigners returns the minimum number of parties required to produce a signature, namely Threshold+1.
+func (c *Config) MinSigners() int {
+	return c.Threshold + 1
+}
+
+// MaxParties returns the number of parties that took part in the key generation.
+func (c *Config) MaxParties() int {
+	return len(c.Public)
+}
+
+// IsSufficientQuorum returns true if signers can produce a signature with this Config.
+//
+// Unlike CanSign, signers does not need to be sorted.
+func (c *Config) IsSufficientQuorum(signers []party.ID) bool {
+	if len(signers) < c.MinSigners() || len(signers) > c.MaxParties() {
 		return false
 	}
+	return c.CanSign(party.NewIDSlice(signers))
+}
 
-	// check that the signers are a subset of the original parties,
-	// that it includes self, and that the size is > t.
+// ForSigners returns a copy of c which only keeps the Public entries of signers,
+// for instance to send a smaller config to a signer with limited bandwidth or storage.
+//
+// The public key and the secret keys are unchanged, so the result can sign with exactly these signers.
+// Since the session hash depends on the Public entries, all signers must use a config minimized for the same set.
+// It can't be used for a Refresh, which requires all parties.
+func (c *Config) ForSigners(signers party.IDSlice) (*Config, error) {
+	signers = party.NewIDSlice(signers)
+	if err := c.CheckSigners(signers); err != nil {
+		return nil, err
+	}
+	minimized := *c
+	minimized.Public = make(map[party.ID]*Public, len(signers))
 	for _, j := range signers {
-		if _, ok := c.Public[j]; !ok {
-			return false
-		}
+		minimized.Public[j] = c.Public[j]
 	}
-
-	return true
+	if err := minimized.Validate(); err != nil {
+		return nil, err
+	}
+	return &minimized, nil
 }
 
+// ValidThreshold returns true if t is a valid threshold for n parties, that is 0 ⩽ t < n.
+//
+// The degenerate case n = 1, t = 0 is valid: the single party then holds the whole secret key,
+// and the protocols run without exchanging any messages.
 func ValidThreshold(t, n int) bool {
 	if t < 0 || t > math.MaxUint32 {
 		return false
@@ -220,9 +497,11 @@ func (c *Config) Derive(adjust curve.Scalar, newChainKey []byte) (*Config, error
 	if len(newChainKey) <= 0 {
 		newChainKey = c.ChainKey
 	}
-	if len(newChainKey) != params.SecBytes {
-		return nil, fmt.Errorf("expecte %d bytes for chain key, found %d", params.SecBytes, len(newChainKey))
+	if err := checkChainKey(newChainKey); err != nil {
+		return nil, err
 	}
+	// the derived config must not alias the chain key of c or of the caller
+	newChainKey = types.RID(newChainKey).Copy()
 	// We need to add the scalar we've derived to the underlying secret,
 	// for which it's sufficient to simply add it to each share. This means adding
 	// scalar * G to each verification share as well.
@@ -231,10 +510,11 @@ func (c *Config) Derive(adjust curve.Scalar, newChainKey []byte) (*Config, error
 	public := make(map[party.ID]*Public, len(c.Public))
 	for k, v := range c.Public {
 		public[k] = &Public{
-			ECDSA:    v.ECDSA.Add(adjustG),
-			ElGamal:  v.ElGamal,
-			Paillier: v.Paillier,
-			Pedersen: v.Pedersen,
+			ECDSA:     v.ECDSA.Add(adjustG),
+			ElGamal:   v.ElGamal,
+			Paillier:  v.Paillier,
+			Pedersen:  v.Pedersen,
+			SafePrime: v.SafePrime,
 		}
 	}
 
@@ -248,9 +528,68 @@ func (c *Config) Derive(adjust curve.Scalar, newChainKey []byte) (*Config, error
 		RID:       c.RID,
 		ChainKey:  newChainKey,
 		Public:    public,
+		History:   NextHistory(c, OperationDerive, c.Threshold),
 	}, nil
 }
 
+// WithRID returns a copy of c in which the RID is replaced by rid, and all keys are kept.
+//
+// The RID must be the same for all parties, and should be agreed upon with a protocol such as cmp.RotateRID.
+func (c *Config) WithRID(rid types.RID) (*Config, error) {
+	if err := rid.Validate(); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	public := make(map[party.ID]*Public, len(c.Public))
+	for k, v := range c.Public {
+		public[k] = &Public{
+			ECDSA:     v.ECDSA,
+			ElGamal:   v.ElGamal,
+			Paillier:  v.Paillier,
+			Pedersen:  v.Pedersen,
+			SafePrime: v.SafePrime,
+		}
+	}
+	var chainKey []byte
+	if c.ChainKey != nil {
+		chainKey = types.RID(c.ChainKey).Copy()
+	}
+	return &Config{
+		Group:     c.Group,
+		ID:        c.ID,
+		Threshold: c.Threshold,
+		ECDSA:     c.ECDSA,
+		ElGamal:   c.ElGamal,
+		Paillier:  c.Paillier,
+		RID:       rid.Copy(),
+		ChainKey:  chainKey,
+		Public:    public,
+		History:   NextHistory(c, OperationRotateRID, c.Threshold),
+	}, nil
+}
+
+// Namespace derives a sharing of a key identified by name, with its own chain key.
+//
+// This allows a single keygen to back several key families, for instance one per asset,
+// each of which can then be derived further with DeriveBIP32.
+// The public keys of different namespaces are unrelated for anyone who does not know the chain key of c.
+// Otherwise, as for unhardened BIP32 derivation, they can be computed from the public key of c.
+func (c *Config) Namespace(name string) (*Config, error) {
+	if err := checkChainKey(c.ChainKey); err != nil {
+		return nil, err
+	}
+	h := hash.New(
+		&hash.BytesWithDomain{TheDomain: "CMP Namespace Chain Key", Bytes: c.ChainKey},
+		&hash.BytesWithDomain{TheDomain: "CMP Namespace", Bytes: []byte(name)},
+	)
+	digest := h.Digest()
+	adjust := sample.Scalar(digest, c.Group)
+	newChainKey := make([]byte, ChainKeyLength)
+	if _, err := io.ReadFull(digest, newChainKey); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	return c.Derive(adjust, newChainKey)
+}
+
 // DeriveBIP32 derives a sharing of the ith child of the consortium signing key.
 //
 // This function uses unhardened derivation, deriving a key without including the
@@ -266,6 +605,9 @@ func (c *Config) DeriveBIP32(i uint32) (*Config, error) {
 	if !ok {
 		return nil, errors.New("DeriveBIP32 must be called with secp256k1")
 	}
+	if err := checkChainKey(c.ChainKey); err != nil {
+		return nil, err
+	}
 	scalar, newChainKey, err := bip32.DeriveScalar(publicPoint, c.ChainKey, i)
 	if err != nil {
 		return nil, err
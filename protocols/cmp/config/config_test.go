@@ -0,0 +1,816 @@
+package config
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/cronokirby/saferith"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/bitcoin"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pedersen"
+	"github.com/taurusgroup/multi-party-sig/pkg/taproot"
+	"golang.org/x/crypto/sha3"
+)
+
+func TestConfig_IsSufficientQuorum(t *testing.T) {
+	ids := party.IDSlice{"a", "b", "c", "d"}
+	c := &Config{
+		Group:     curve.Secp256k1{},
+		ID:        "a",
+		Threshold: 2,
+		Public:    map[party.ID]*Public{},
+	}
+	for _, id := range ids {
+		c.Public[id] = &Public{}
+	}
+
+	assert.Equal(t, 3, c.MinSigners())
+	assert.Equal(t, 4, c.MaxParties())
+
+	assert.False(t, c.IsSufficientQuorum([]party.ID{"a", "b"}), "threshold signers should not be enough")
+	assert.True(t, c.IsSufficientQuorum([]party.ID{"c", "a", "b"}), "threshold+1 signers should be enough")
+	assert.True(t, c.IsSufficientQuorum(ids), "all parties should be enough")
+
+	assert.False(t, c.IsSufficientQuorum([]party.ID{"b", "c", "d"}), "self must be included")
+	assert.False(t, c.IsSufficientQuorum([]party.ID{"a", "b", "e"}), "unknown party")
+	assert.False(t, c.IsSufficientQuorum([]party.ID{"a", "b", "b"}), "duplicates")
+	assert.False(t, c.IsSufficientQuorum([]party.ID{"a", "b", "c", "d", "e"}), "more signers than parties")
+
+	assert.NoError(t, c.CheckSigners(party.IDSlice{"a", "b", "c"}))
+	var unknown *UnknownSignersError
+	err := c.CheckSigners(party.IDSlice{"a", "e", "f"})
+	require.ErrorAs(t, err, &unknown)
+	assert.Equal(t, party.IDSlice{"e", "f"}, unknown.Unknown)
+	assert.EqualError(t, err, "config: signers [e, f] are not parties of the config")
+	assert.EqualError(t, c.CheckSigners(party.IDSlice{"b", "c", "d"}), "config: signers do not include self (a)")
+	assert.EqualError(t, c.CheckSigners(party.IDSlice{"a", "b"}), "config: 2 signers is not enough for threshold 2")
+	assert.EqualError(t, c.CheckSigners(party.IDSlice{"b", "a", "c"}), "config: signers must be sorted and without duplicates")
+}
+
+func TestConfig_PartyMembershipProof(t *testing.T) {
+	group := curve.Secp256k1{}
+	for _, n := range []int{1, 2, 5, 8} {
+		c := &Config{Group: group, Public: map[party.ID]*Public{}}
+		for i := 0; i < n; i++ {
+			id := party.ID(rune('a' + i))
+			c.Public[id] = &Public{
+				ECDSA:   sample.Scalar(rand.Reader, group).ActOnBase(),
+				ElGamal: sample.Scalar(rand.Reader, group).ActOnBase(),
+			}
+		}
+		root, err := c.PartySetRoot()
+		require.NoError(t, err)
+
+		for id, public := range c.Public {
+			proof, err := c.PartyMembershipProof(id)
+			require.NoError(t, err)
+			assert.True(t, VerifyPartyMembership(root, id, public, proof), "proof should verify for %s", id)
+
+			if len(proof.Steps) > 0 {
+				proof.Steps[0].Sibling[0] ^= 1
+				assert.False(t, VerifyPartyMembership(root, id, public, proof), "tampered proof should fail")
+				proof.Steps[0].Sibling[0] ^= 1
+			}
+			assert.False(t, VerifyPartyMembership(root, "z", public, proof), "proof should not verify for another ID")
+			other := &Public{ECDSA: group.NewBasePoint(), ElGamal: public.ElGamal}
+			assert.False(t, VerifyPartyMembership(root, id, other, proof), "proof should not verify for other data")
+		}
+
+		_, err = c.PartyMembershipProof("z")
+		assert.Error(t, err, "unknown party should have no proof")
+
+		// missing data can't be committed to
+		c.Public["a"] = nil
+		_, err = c.PartySetRoot()
+		assert.Error(t, err)
+		_, err = c.PartyMembershipProof("a")
+		assert.Error(t, err)
+	}
+}
+
+// testPrimes are fixed 1024 bit primes, so that tests do not need to generate Paillier keys.
+var testPrimes = []string{
+	"D08769E92F80F7FDFB85EC02AFFDAED0FDE2782070757F191DCDC4D108110AC1E31C07FC253B5F7B91C5D9F203AA0572D3F2062A3D2904C535C6ACCA7D5674E1C2640720E762C72B66931F483C2D910908CF02EA6723A0CBBB1016CA696C38FEAC59B31E40584C8141889A11F7A38F5B17811D11F42CD15B8470F11C6183802B",
+	"C21239C3484FC3C8409F40A9A22FABFFE26CA10C27506E3E017C2EC8C4B98D7A6D30DED0686869884BE9BAD27F5241B7313F73D19E9E4B384FABF9554B5BB4D517CBAC0268420C63D545612C9ADABEEDF20F94244E7F8F2080B0C675AC98D97C580D43375F999B1AC127EC580B89B2D302EF33DD5FD8474A241B0398F6088CA7",
+	"FD90167F42443623D284EA828FB13E374CBF73E16CC6755422B97640AB7FC77FDAF452B4F3A2E8472614EEE11CC8EAF48783CE2B4876A3BB72E9ACF248E86DAA5CE4D5A88E77352BCBA30A998CD8B0AD2414D43222E3BA56D82523E2073730F817695B34A4A26128D5E030A7307D3D04456DC512EBB8B53FDBD1DFC07662099B",
+	"DB531C32024A262A0DF9603E48C79E863F9539A82B8619480289EC38C3664CC63E3AC2C04888827559FFDBCB735A8D2F1D24BAF910643CE819452D95CAFFB686E6110057985E93605DE89E33B99C34140EF362117F975A5056BFF14A51C9CD16A4961BE1F02C081C7AD8B2A5450858023A157AFA3C3441E8E00941F8D33ED6B7",
+}
+
+// testPaillier returns the Paillier key made of testPrimes[i] and testPrimes[j].
+func testPaillier(i, j int) *paillier.SecretKey {
+	p, _ := new(saferith.Nat).SetHex(testPrimes[i])
+	q, _ := new(saferith.Nat).SetHex(testPrimes[j])
+	return paillier.NewSecretKeyFromPrimes(p, q)
+}
+
+// testPublic returns a valid Public with random ECDSA and ElGamal keys, and the Paillier key sk.
+func testPublic(group curve.Curve, sk *paillier.SecretKey) *Public {
+	s, t, _ := sample.Pedersen(rand.Reader, sk.Phi(), sk.N())
+	return &Public{
+		ECDSA:    sample.Scalar(rand.Reader, group).ActOnBase(),
+		ElGamal:  sample.Scalar(rand.Reader, group).ActOnBase(),
+		Paillier: sk.PublicKey,
+		Pedersen: pedersen.New(sk.Modulus(), s, t),
+	}
+}
+
+// testSharing returns the config of party "a" in a sharing of secret between "a", "b" and "c" with threshold 1.
+func testSharing(t *testing.T, group curve.Curve, secret curve.Scalar) *Config {
+	chainKey, err := types.NewRID(rand.Reader)
+	require.NoError(t, err)
+	f := polynomial.NewPolynomial(group, 1, secret)
+	sk := testPaillier(0, 1)
+	c := &Config{
+		Group:     group,
+		ID:        "a",
+		Threshold: 1,
+		ECDSA:     f.Evaluate(party.ID("a").Scalar(group)),
+		ChainKey:  chainKey,
+		Public:    map[party.ID]*Public{},
+	}
+	for _, id := range []party.ID{"a", "b", "c"} {
+		c.Public[id] = testPublic(group, sk)
+		c.Public[id].ECDSA = f.Evaluate(id.Scalar(group)).ActOnBase()
+	}
+	return c
+}
+
+// testConfig returns a valid config of party "a" among "a", "b" and "c" with threshold 1,
+// whose keys are unrelated to each other.
+func testConfig(t *testing.T, group curve.Curve) *Config {
+	ids := party.IDSlice{"a", "b", "c"}
+	keys := []*paillier.SecretKey{testPaillier(0, 1), testPaillier(2, 3), testPaillier(0, 3)}
+	rid, err := types.NewRID(rand.Reader)
+	require.NoError(t, err)
+	chainKey, err := types.NewRID(rand.Reader)
+	require.NoError(t, err)
+	c := &Config{
+		Group:     group,
+		ID:        "a",
+		Threshold: 1,
+		ECDSA:     sample.Scalar(rand.Reader, group),
+		ElGamal:   sample.Scalar(rand.Reader, group),
+		Paillier:  keys[0],
+		RID:       rid,
+		ChainKey:  chainKey,
+		Public:    map[party.ID]*Public{},
+	}
+	for i, id := range ids {
+		c.Public[id] = testPublic(group, keys[i])
+	}
+	c.Public["a"].ECDSA = c.ECDSA.ActOnBase()
+	c.Public["a"].ElGamal = c.ElGamal.ActOnBase()
+	return c
+}
+
+func TestPublicBuilder(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+
+	b, err := NewPublicBuilder(group, ids)
+	require.NoError(t, err)
+
+	publics := make(map[party.ID]*Public, len(ids))
+	sk := testPaillier(0, 1)
+	for _, id := range ids {
+		publics[id] = testPublic(group, sk)
+	}
+
+	require.NoError(t, b.AddPublic("b", publics["b"]))
+	require.NoError(t, b.AddPublic("a", publics["a"]))
+	assert.Equal(t, party.IDSlice{"c"}, b.Missing())
+
+	_, err = b.Finalize()
+	assert.Error(t, err, "finalize should fail with a missing party")
+
+	assert.Error(t, b.AddPublic("a", publics["c"]), "duplicate ID should be rejected")
+	assert.Error(t, b.AddPublic("d", publics["c"]), "undeclared ID should be rejected")
+	assert.Error(t, b.AddPublic("c", &Public{}), "invalid entry should be rejected")
+	invalid := *publics["c"]
+	invalid.ECDSA = group.NewPoint()
+	assert.Error(t, b.AddPublic("c", &invalid), "identity point should be rejected")
+
+	require.NoError(t, b.AddPublic("c", publics["c"]))
+	public, err := b.Finalize()
+	require.NoError(t, err)
+	assert.Equal(t, publics, public)
+
+	merged, err := NewPublicBuilder(group, ids)
+	require.NoError(t, err)
+	require.NoError(t, merged.MergePublic(publics))
+	assert.Empty(t, merged.Missing())
+	assert.Error(t, merged.MergePublic(map[party.ID]*Public{"b": publics["b"]}), "duplicate ID should be rejected")
+}
+
+func TestAccumulatePublicShares(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c", "d"}
+	threshold := 2
+
+	// the public shares of a sharing of a random secret, as produced by a keygen
+	f := polynomial.NewPolynomial(group, threshold, sample.Scalar(rand.Reader, group))
+	c := &Config{Group: group, Threshold: threshold, Public: map[party.ID]*Public{}}
+	for _, id := range ids {
+		c.Public[id] = &Public{ECDSA: f.Evaluate(id.Scalar(group)).ActOnBase()}
+	}
+	expected := c.PublicPoint()
+
+	a, err := AccumulatePublicShares(group, ids, threshold)
+	require.NoError(t, err)
+	arrivals := []party.ID{"d", "b", "a", "c"}
+	for i, id := range arrivals {
+		if i < threshold+1 {
+			_, err = a.PublicPoint()
+			assert.Error(t, err, "the public key needs threshold+1 shares")
+		}
+		require.NoError(t, a.Add(id, c.Public[id].ECDSA))
+		assert.Equal(t, i >= threshold, a.Ready())
+		if a.Ready() {
+			publicPoint, err := a.PublicPoint()
+			require.NoError(t, err)
+			assert.True(t, expected.Equal(publicPoint), "the public key should not depend on the shares received")
+		}
+	}
+
+	assert.Error(t, a.Add("a", c.Public["a"].ECDSA), "duplicate ID should be rejected")
+	assert.Error(t, a.Add("e", c.Public["a"].ECDSA), "undeclared ID should be rejected")
+	b, err := AccumulatePublicShares(group, ids, threshold)
+	require.NoError(t, err)
+	assert.Error(t, b.Add("a", group.NewPoint()), "identity point should be rejected")
+	_, err = AccumulatePublicShares(group, ids, len(ids))
+	assert.Error(t, err, "threshold should be smaller than the number of parties")
+}
+
+func TestConfig_Validate(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	keys := []*paillier.SecretKey{testPaillier(0, 1), testPaillier(2, 3), testPaillier(0, 3)}
+
+	newConfig := func() *Config {
+		chainKey, err := types.NewRID(rand.Reader)
+		require.NoError(t, err)
+		c := &Config{
+			Group:     group,
+			ID:        "a",
+			Threshold: 1,
+			ECDSA:     sample.Scalar(rand.Reader, group),
+			ElGamal:   sample.Scalar(rand.Reader, group),
+			Paillier:  keys[0],
+			ChainKey:  chainKey,
+			Public:    map[party.ID]*Public{},
+		}
+		for i, id := range ids {
+			c.Public[id] = testPublic(group, keys[i])
+		}
+		c.Public["a"].ECDSA = c.ECDSA.ActOnBase()
+		c.Public["a"].ElGamal = c.ElGamal.ActOnBase()
+		return c
+	}
+	require.NoError(t, newConfig().Validate())
+
+	c := newConfig()
+	c.Public["c"].ECDSA = c.Public["b"].ECDSA
+	assert.EqualError(t, c.Validate(), "config: parties b and c have the same ECDSA public share")
+
+	c = newConfig()
+	c.Public["c"] = testPublic(group, keys[0])
+	assert.EqualError(t, c.Validate(), "config: parties a and c have the same Paillier modulus")
+
+	c = newConfig()
+	c.Public["c"].Paillier = c.Public["b"].Paillier
+	c.Public["c"].Pedersen = c.Public["b"].Pedersen
+	assert.EqualError(t, c.Validate(), "config: parties b and c have the same Paillier modulus")
+
+	// a copied Pedersen modulus is rejected even if the Paillier moduli are distinct
+	c = newConfig()
+	c.Public["c"].Pedersen = c.Public["b"].Pedersen
+	assert.EqualError(t, c.Validate(), "config: party c: public: Pedersen and Paillier moduli differ")
+
+	c = newConfig()
+	c.Public["b"].Paillier, c.Public["b"].Pedersen = nil, nil
+	assert.EqualError(t, c.Validate(), "config: party b: Paillier parameters do not match this config")
+	c.Paillier = nil
+	assert.EqualError(t, c.Validate(), "config: party a: Paillier parameters do not match this config")
+
+	c = newConfig()
+	c.ECDSA = sample.Scalar(rand.Reader, group)
+	assert.Error(t, c.Validate(), "secret share should match the public share")
+
+	c = newConfig()
+	c.ChainKey = c.ChainKey[:ChainKeyLength-1]
+	assert.EqualError(t, c.Validate(), "config: expected 32 bytes for chain key, found 31")
+	_, err := c.DeriveBIP32(0)
+	assert.EqualError(t, err, "config: expected 32 bytes for chain key, found 31", "derivation should check the chain key before BIP32")
+	c.ChainKey = nil
+	assert.EqualError(t, c.Validate(), "config: expected 32 bytes for chain key, found 0")
+
+	c = newConfig()
+	c.Threshold = 3
+	assert.Error(t, c.Validate(), "threshold should be smaller than the number of parties")
+
+	// shares of f(X) = a⋅X, whose Lagrange-weighted sum is f(0)⋅G = identity
+	c = newConfig()
+	a := sample.Scalar(rand.Reader, group)
+	for _, id := range ids {
+		share := group.NewScalar().Set(a).Mul(id.Scalar(group))
+		c.Public[id].ECDSA = share.ActOnBase()
+		if id == c.ID {
+			c.ECDSA = share
+		}
+	}
+	require.True(t, c.PublicPoint().IsIdentity())
+	assert.ErrorIs(t, c.Validate(), ErrIdentityPublicKey)
+
+	c = newConfig()
+	for len(c.Public) <= maxPartyCount {
+		c.Public[party.IDFromUint64(uint64(len(c.Public)))] = nil
+	}
+	assert.EqualError(t, c.Validate(), "config: 1025 parties exceeds the maximum of 1024")
+}
+
+func TestConfig_SecurityReport(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	keys := []*paillier.SecretKey{testPaillier(0, 1), testPaillier(2, 3), testPaillier(0, 3)}
+	newConfig := func() *Config {
+		c := &Config{Group: group, ID: "a", Threshold: 1, Public: map[party.ID]*Public{}}
+		for i, id := range ids {
+			c.Public[id] = testPublic(group, keys[i])
+		}
+		return c
+	}
+
+	r := newConfig().SecurityReport()
+	assert.Equal(t, &SecurityReport{
+		Curve:             "secp256k1",
+		CurveSecurityBits: 128,
+		PaillierBits:      2048,
+		Threshold:         1,
+		Parties:           3,
+	}, r)
+
+	// b uses a 1024 bit modulus, and c's ElGamal key is the identity
+	c := newConfig()
+	c.Threshold = 0
+	p, err := rand.Prime(rand.Reader, 512)
+	require.NoError(t, err)
+	q, err := rand.Prime(rand.Reader, 512)
+	require.NoError(t, err)
+	pNat := new(saferith.Nat).SetBig(p, p.BitLen())
+	qNat := new(saferith.Nat).SetBig(q, q.BitLen())
+	c.Public["b"] = testPublic(group, paillier.NewSecretKeyFromPrimes(pNat, qNat))
+	c.Public["c"].ElGamal = group.NewPoint()
+	r = c.SecurityReport()
+	assert.Equal(t, 1024, r.PaillierBits)
+	assert.Equal(t, []string{
+		"threshold 0: any single party can sign",
+		"party b: Paillier modulus has 1024 bits, below 2048",
+		"party c: public: ECDSA or ElGamal public key is identity",
+	}, r.Warnings)
+
+	c = newConfig()
+	c.Public["b"] = nil
+	r = c.SecurityReport()
+	assert.False(t, r.SafePrimeProofs)
+	assert.Equal(t, []string{"party b: no public data"}, r.Warnings)
+}
+
+// TestConfig_WriteToGolden locks the serialization used to hash a Config into the SSID of every protocol.
+// A change to this layout breaks compatibility with parties running older versions.
+func TestConfig_WriteToGolden(t *testing.T) {
+	group := curve.Secp256k1{}
+	scalar := func(x uint64) curve.Scalar {
+		return group.NewScalar().SetNat(new(saferith.Nat).SetUint64(x))
+	}
+	nat := func(x uint64) *saferith.Nat { return new(saferith.Nat).SetUint64(x) }
+
+	rid := make(types.RID, params.SecBytes)
+	for i := range rid {
+		rid[i] = byte(i)
+	}
+	skA, skB := testPaillier(0, 1), testPaillier(2, 3)
+	c := &Config{
+		Group:     group,
+		ID:        "a",
+		Threshold: 1,
+		RID:       rid,
+		Public: map[party.ID]*Public{
+			"b": {
+				ECDSA:    scalar(3).ActOnBase(),
+				ElGamal:  scalar(4).ActOnBase(),
+				Paillier: skB.PublicKey,
+				Pedersen: pedersen.New(skB.Modulus(), nat(7), nat(8)),
+			},
+			"a": {
+				ECDSA:    scalar(1).ActOnBase(),
+				ElGamal:  scalar(2).ActOnBase(),
+				Paillier: skA.PublicKey,
+				Pedersen: pedersen.New(skA.Modulus(), nat(5), nat(6)),
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	_, err := c.WriteTo(&buf)
+	require.NoError(t, err)
+	data := buf.Bytes()
+
+	// t as big-endian uint32, then the number of parties as big-endian uint64, followed by the sorted IDs
+	header := []byte{0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 2, 'a', 'b'}
+	require.True(t, bytes.HasPrefix(data, header), "unexpected header %x", data[:len(header)])
+	offset := len(header) + params.SecBytes
+	assert.Equal(t, []byte(rid), data[len(header):offset])
+
+	// for each party: Xⱼ and Yⱼ compressed, then Nⱼ, Nⱼ, sⱼ, tⱼ as big-endian integers of params.BytesIntModN bytes
+	partySize := 2*33 + 4*params.BytesIntModN
+	require.Len(t, data, offset+2*partySize)
+	a := data[offset : offset+partySize]
+	g, _ := group.NewBasePoint().MarshalBinary()
+	assert.Equal(t, g, a[:33])
+	pedersenA := a[66+params.BytesIntModN:]
+	assert.Equal(t, byte(5), pedersenA[2*params.BytesIntModN-1])
+	assert.Equal(t, byte(6), pedersenA[3*params.BytesIntModN-1])
+
+	digest := sha256.Sum256(data)
+	assert.Equal(t, "e52a4aadb93135075b84e6a28a7fdff3f47598f5d572cc7337e8064b3d04434e", hex.EncodeToString(digest[:]), "the serialization of Config has changed")
+}
+
+func TestConfig_Namespace(t *testing.T) {
+	group := curve.Secp256k1{}
+	secret := sample.Scalar(rand.Reader, group)
+	c := testSharing(t, group, secret)
+	require.True(t, secret.ActOnBase().Equal(c.PublicPoint()))
+
+	first, err := c.Namespace("BTC")
+	require.NoError(t, err)
+	second, err := c.Namespace("ETH")
+	require.NoError(t, err)
+	again, err := c.Namespace("BTC")
+	require.NoError(t, err)
+
+	assert.False(t, first.PublicPoint().Equal(c.PublicPoint()), "namespace should change the public key")
+	assert.False(t, first.PublicPoint().Equal(second.PublicPoint()), "namespaces should have independent keys")
+	assert.NotEqual(t, first.ChainKey, second.ChainKey, "namespaces should have independent chain keys")
+	assert.True(t, first.PublicPoint().Equal(again.PublicPoint()), "namespace should be deterministic")
+
+	for _, ns := range []*Config{first, second} {
+		assert.True(t, ns.ECDSA.ActOnBase().Equal(ns.Public[ns.ID].ECDSA), "share should match its public share")
+		firstChild, err := ns.DeriveBIP32(0)
+		require.NoError(t, err)
+		assert.False(t, firstChild.PublicPoint().Equal(ns.PublicPoint()))
+	}
+	firstChild, _ := first.DeriveBIP32(0)
+	secondChild, _ := second.DeriveBIP32(0)
+	assert.False(t, firstChild.PublicPoint().Equal(secondChild.PublicPoint()), "derivation should be scoped to the namespace")
+}
+
+// TestConfig_Concurrent uses a single Config from many goroutines, and should be run with -race.
+func TestConfig_Concurrent(t *testing.T) {
+	group := curve.Secp256k1{}
+	secret := sample.Scalar(rand.Reader, group)
+	c := testSharing(t, group, secret)
+	expected, err := c.DeriveBIP32(7)
+	require.NoError(t, err)
+	publicKey := secret.ActOnBase()
+	expectedData, err := c.MarshalBinary()
+	require.NoError(t, err)
+
+	const goroutines = 32
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !c.PublicPoint().Equal(publicKey) {
+				errs <- errors.New("wrong public key")
+				return
+			}
+			child, err := c.DeriveBIP32(7)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if !child.PublicPoint().Equal(expected.PublicPoint()) || !bytes.Equal(child.ChainKey, expected.ChainKey) {
+				errs <- errors.New("derivation is not deterministic")
+				return
+			}
+			publicChild, err := c.PublicConfig().DeriveBIP32(7)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if !publicChild.PublicKey.Equal(expected.PublicPoint()) {
+				errs <- errors.New("public derivation does not match")
+				return
+			}
+			data, err := c.MarshalBinary()
+			if err != nil {
+				errs <- err
+				return
+			}
+			if !bytes.Equal(data, expectedData) {
+				errs <- errors.New("serialization changed")
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	// the derived config must not alias the chain key of its parent
+	expected.ChainKey[0] ^= 1
+	child, err := c.DeriveBIP32(7)
+	require.NoError(t, err)
+	assert.NotEqual(t, expected.ChainKey, child.ChainKey)
+	same, err := c.Derive(group.NewScalar(), nil)
+	require.NoError(t, err)
+	same.ChainKey[0] ^= 1
+	assert.NotEqual(t, c.ChainKey, same.ChainKey, "Derive should copy the chain key")
+}
+
+func TestParseBIP32Path(t *testing.T) {
+	indices, err := ParseBIP32Path("m/0/17/2147483647")
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{0, 17, 1<<31 - 1}, indices)
+	indices, err = ParseBIP32Path("m")
+	require.NoError(t, err)
+	assert.Empty(t, indices)
+
+	for _, path := range []string{"", "0/1", "m/", "m/1//2", "m/-1", "m/x", "m/0'", "m/0h", "m/2147483648"} {
+		_, err = ParseBIP32Path(path)
+		assert.Error(t, err, "path %q should be rejected", path)
+	}
+}
+
+func TestConfig_Diff(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	keys := []*paillier.SecretKey{testPaillier(0, 1), testPaillier(2, 3), testPaillier(0, 3)}
+	public := make(map[party.ID]*Public, len(ids))
+	for i, id := range ids {
+		public[id] = testPublic(group, keys[i])
+	}
+	newConfig := func(id party.ID) *Config {
+		c := &Config{
+			Group:     group,
+			ID:        id,
+			Threshold: 1,
+			RID:       []byte{1, 2, 3},
+			ChainKey:  []byte{4, 5, 6},
+			Public:    map[party.ID]*Public{},
+		}
+		for j, p := range public {
+			copied := *p
+			c.Public[j] = &copied
+		}
+		return c
+	}
+
+	a, b := newConfig("a"), newConfig("b")
+	assert.Empty(t, a.Diff(b), "configs of different parties for the same group should not differ")
+
+	// party c has a different modulus, with the same S and T
+	other := keys[1]
+	b.Public["c"].Paillier = other.PublicKey
+	b.Public["c"].Pedersen = pedersen.New(other.Modulus(), public["c"].Pedersen.S(), public["c"].Pedersen.T())
+	assert.Equal(t, []string{"party c: N differs"}, a.Diff(b))
+
+	b = newConfig("b")
+	b.Threshold = 2
+	b.ChainKey = []byte{7}
+	b.Public["a"].ECDSA = public["b"].ECDSA
+	delete(b.Public, "c")
+	assert.Equal(t, []string{
+		"threshold: 1 != 2",
+		"chain key: 040506 != 07",
+		"party a: ECDSA share differs",
+		"party c: only in the first config",
+	}, a.Diff(b))
+}
+
+func TestConfig_History(t *testing.T) {
+	group := curve.Secp256k1{}
+	c := testConfig(t, group)
+	c.History = NextHistory(nil, OperationKeygen, 1)
+	rid := c.RID
+	require.NoError(t, c.Validate())
+
+	derived, err := c.Derive(sample.Scalar(rand.Reader, group), nil)
+	require.NoError(t, err)
+	require.Len(t, derived.History, 2)
+	assert.Equal(t, OperationKeygen, derived.History[0].Operation)
+	assert.Empty(t, derived.History[0].ParentRID)
+	assert.Equal(t, HistoryEntry{Operation: OperationDerive, ParentRID: rid, Threshold: 1}, derived.History[1])
+	assert.Len(t, c.History, 1, "the history of the parent should not change")
+
+	data, err := derived.MarshalBinary()
+	require.NoError(t, err)
+	decoded := EmptyConfig(group)
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, derived.History, decoded.History)
+
+	// the history only holds public data
+	history, err := cbor.Marshal(derived.History)
+	require.NoError(t, err)
+	for _, secret := range []curve.Scalar{derived.ECDSA, derived.ElGamal} {
+		secretData, err := secret.MarshalBinary()
+		require.NoError(t, err)
+		assert.False(t, bytes.Contains(history, secretData))
+	}
+	assert.False(t, bytes.Contains(history, derived.Paillier.P().Bytes()))
+}
+
+func TestConfig_PublicSummary(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c", "d"}
+	rid, err := types.NewRID(rand.Reader)
+	require.NoError(t, err)
+
+	sk := testPaillier(0, 1)
+	c := &Config{
+		Group:     group,
+		ID:        "a",
+		Threshold: 2,
+		RID:       rid,
+		Public:    map[party.ID]*Public{},
+	}
+	for _, id := range ids {
+		c.Public[id] = testPublic(group, sk)
+	}
+
+	data, err := c.PublicSummary()
+	require.NoError(t, err)
+	assert.Less(t, len(data), 128, "summary should not contain the per-party parameters")
+
+	summary, err := ParsePublicSummary(group, data)
+	require.NoError(t, err)
+	assert.True(t, c.PublicPoint().Equal(summary.PublicKey))
+	assert.Equal(t, c.Threshold, summary.Threshold)
+	assert.Equal(t, len(ids), summary.Parties)
+	assert.Equal(t, c.RID, summary.RID)
+
+	c.Threshold = len(ids)
+	data, err = c.PublicSummary()
+	require.NoError(t, err)
+	_, err = ParsePublicSummary(group, data)
+	assert.Error(t, err, "summary with an invalid threshold should be rejected")
+
+	_, err = ParsePublicSummary(group, data[:len(data)/2])
+	assert.Error(t, err, "truncated summary should be rejected")
+}
+
+func TestConfig_UnmarshalLargeModulus(t *testing.T) {
+	group := curve.Secp256k1{}
+	c := testConfig(t, group)
+	data, err := c.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, EmptyConfig(group).UnmarshalBinary(data))
+
+	// replaces the public data of party b
+	withPublic := func(modify func(pm map[string]cbor.RawMessage)) []byte {
+		var cm map[string]cbor.RawMessage
+		require.NoError(t, cbor.Unmarshal(data, &cm))
+		var ps []cbor.RawMessage
+		require.NoError(t, cbor.Unmarshal(cm["Public"], &ps))
+		var pm map[string]cbor.RawMessage
+		require.NoError(t, cbor.Unmarshal(ps[1], &pm))
+		modify(pm)
+		ps[1], err = cbor.Marshal(pm)
+		require.NoError(t, err)
+		cm["Public"], err = cbor.Marshal(ps)
+		require.NoError(t, err)
+		modified, err := cbor.Marshal(cm)
+		require.NoError(t, err)
+		return modified
+	}
+
+	huge := make([]byte, 100_000/8)
+	huge[0] = 0xff
+	for _, field := range []string{"N", "S"} {
+		modified := withPublic(func(pm map[string]cbor.RawMessage) {
+			pm[field], err = cbor.Marshal(huge)
+			require.NoError(t, err)
+		})
+		err = EmptyConfig(group).UnmarshalBinary(modified)
+		assert.ErrorIs(t, err, paillier.ErrPaillierLarge, "a 100,000-bit %s should be rejected", field)
+	}
+
+	modified := withPublic(func(pm map[string]cbor.RawMessage) {
+		pm["N"], err = cbor.Marshal(make([]byte, 256))
+		require.NoError(t, err)
+	})
+	assert.ErrorIs(t, EmptyConfig(group).UnmarshalBinary(modified), paillier.ErrPaillierNil, "a zero N should be rejected")
+}
+
+func TestConfig_EthereumAddress(t *testing.T) {
+	group := curve.Secp256k1{}
+
+	// addresses of the private keys 1, 2 and 3, as given by crypto.PubkeyToAddress in go-ethereum
+	expected := []string{
+		"7e5f4552091a69125d5dfcb7b8c2659029395bdf",
+		"2b5ad5c4795c026514f8317c7a215e218dccd6cf",
+		"6813eb9362372eef6200f3b1dbc3f819671cba69",
+	}
+	secret := group.NewScalar()
+	for _, address := range expected {
+		secret.Add(curve.ScalarOne(group))
+		c := testSharing(t, group, secret)
+
+		actual, err := c.EthereumAddress()
+		require.NoError(t, err)
+		assert.Equal(t, address, hex.EncodeToString(actual[:]))
+		public, err := c.PublicConfig().EthereumAddress()
+		require.NoError(t, err)
+		assert.Equal(t, actual, public)
+
+		// the derived shares add the same tweak to the secret, whose address is computed independently
+		derived, err := c.DeriveBIP32Path("m/44/60/0")
+		require.NoError(t, err)
+		tweak := group.NewScalar().Set(derived.ECDSA).Sub(c.ECDSA)
+		derivedSecret := group.NewScalar().Set(secret).Add(tweak)
+		require.True(t, derivedSecret.ActOnBase().Equal(derived.PublicPoint()))
+		secretBytes, err := derivedSecret.MarshalBinary()
+		require.NoError(t, err)
+		h := sha3.NewLegacyKeccak256()
+		_, _ = h.Write(secp256k1.PrivKeyFromBytes(secretBytes).PubKey().SerializeUncompressed()[1:])
+		derivedAddress, err := c.DeriveEthereumAddress("m/44/60/0")
+		require.NoError(t, err)
+		assert.Equal(t, h.Sum(nil)[12:], derivedAddress[:])
+		assert.NotEqual(t, actual, derivedAddress)
+	}
+
+	c := testSharing(t, group, secret)
+	_, err := c.DeriveEthereumAddress("m/0'")
+	assert.Error(t, err, "hardened derivation should be rejected")
+
+	_, err = (&PublicConfig{PublicKey: group.NewPoint(), ChainKey: c.ChainKey}).EthereumAddress()
+	assert.EqualError(t, err, "config: public key is the identity")
+}
+
+func TestConfig_BitcoinAddress(t *testing.T) {
+	group := curve.Secp256k1{}
+
+	// the private key 1, whose P2WPKH addresses are given in BIP-173
+	c := testSharing(t, group, curve.ScalarOne(group))
+
+	address, err := c.P2WPKHAddress(bitcoin.MainnetHRP)
+	require.NoError(t, err)
+	assert.Equal(t, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", address)
+	address, err = c.P2WPKHAddress(bitcoin.TestnetHRP)
+	require.NoError(t, err)
+	assert.Equal(t, "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", address)
+
+	address, err = c.P2TRAddress(bitcoin.MainnetHRP)
+	require.NoError(t, err)
+	G := group.NewBasePoint().(*curve.Secp256k1Point)
+	expected, err := bitcoin.P2TRAddress(bitcoin.MainnetHRP, taproot.PublicKey(G.XBytes()), nil)
+	require.NoError(t, err)
+	assert.Equal(t, expected, address)
+
+	// the private key at m/86'/1'/0'/0/0 for the mnemonic of the BIP-86 test vectors, and its testnet address
+	bip86, err := hex.DecodeString("dff1c8c2c016a572914b4c5adb8791d62b4768ae9d0a61be8ab94cf5038d7d90")
+	require.NoError(t, err)
+	secret := group.NewScalar()
+	require.NoError(t, secret.UnmarshalBinary(bip86))
+	address, err = testSharing(t, group, secret).P2TRAddress(bitcoin.TestnetHRP)
+	require.NoError(t, err)
+	assert.Equal(t, "tb1p8wpt9v4frpf3tkn0srd97pksgsxc5hs52lafxwru9kgeephvs7rqlqt9zj", address)
+
+	// the public derivation gives the addresses of the derived configs
+	derived, err := c.DeriveBIP32Path("m/0/5")
+	require.NoError(t, err)
+	child, err := c.PublicConfig().DeriveBIP32Path("m/0/5")
+	require.NoError(t, err)
+	for _, hrp := range []string{bitcoin.MainnetHRP, bitcoin.TestnetHRP} {
+		expected, err := derived.P2WPKHAddress(hrp)
+		require.NoError(t, err)
+		actual, err := child.P2WPKHAddress(hrp)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+
+		expected, err = derived.P2TRAddress(hrp)
+		require.NoError(t, err)
+		actual, err = child.P2TRAddress(hrp)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+		assert.Equal(t, hrp+"1p", actual[:len(hrp)+2])
+	}
+}
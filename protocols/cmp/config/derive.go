@@ -0,0 +1,110 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/taurusgroup/multi-party-sig/internal/bip32"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// PublicConfig holds the public key of a Config, together with its chain key.
+//
+// It contains no secret material, and allows services to compute the public keys of
+// the children of a Config obtained with DeriveBIP32, for instance to verify their signatures.
+type PublicConfig struct {
+	// PublicKey is the public key of the Config, as returned by Config.PublicPoint.
+	PublicKey curve.Point
+	// ChainKey is the chaining key value associated with this public key.
+	ChainKey []byte
+}
+
+// PublicConfig returns the public key of c together with its chain key.
+func (c *Config) PublicConfig() *PublicConfig {
+	chainKey := make([]byte, len(c.ChainKey))
+	copy(chainKey, c.ChainKey)
+	return &PublicConfig{
+		PublicKey: c.PublicPoint(),
+		ChainKey:  chainKey,
+	}
+}
+
+// DeriveBIP32 derives the public key of the ith child of p.
+//
+// The result has the public key of Config.DeriveBIP32(i), for any Config whose public key is p.
+func (p *PublicConfig) DeriveBIP32(i uint32) (*PublicConfig, error) {
+	publicPoint, ok := p.PublicKey.(*curve.Secp256k1Point)
+	if !ok {
+		return nil, errors.New("DeriveBIP32 must be called with secp256k1")
+	}
+	if err := checkChainKey(p.ChainKey); err != nil {
+		return nil, err
+	}
+	if i>>31 != 0 {
+		return nil, fmt.Errorf("config: index %d is hardened", i)
+	}
+	scalar, newChainKey, err := bip32.DeriveScalar(publicPoint, p.ChainKey, i)
+	if err != nil {
+		return nil, err
+	}
+	return &PublicConfig{
+		PublicKey: p.PublicKey.Add(scalar.ActOnBase()),
+		ChainKey:  newChainKey,
+	}, nil
+}
+
+// DeriveBIP32Path derives the public key at path, starting from p, as described by ParseBIP32Path.
+func (p *PublicConfig) DeriveBIP32Path(path string) (*PublicConfig, error) {
+	indices, err := ParseBIP32Path(path)
+	if err != nil {
+		return nil, err
+	}
+	child := p
+	for _, i := range indices {
+		if child, err = child.DeriveBIP32(i); err != nil {
+			return nil, err
+		}
+	}
+	return child, nil
+}
+
+// DeriveBIP32Path derives a sharing of the key at path, starting from c, as described by ParseBIP32Path.
+func (c *Config) DeriveBIP32Path(path string) (*Config, error) {
+	indices, err := ParseBIP32Path(path)
+	if err != nil {
+		return nil, err
+	}
+	child := c
+	for _, i := range indices {
+		if child, err = child.DeriveBIP32(i); err != nil {
+			return nil, err
+		}
+	}
+	return child, nil
+}
+
+// ParseBIP32Path parses a derivation path of the form "m/0/1/2" into its indices.
+//
+// Only unhardened derivation is possible without the secret key,
+// so an index marked as hardened with ' or h, or greater than 2³¹-1, returns an error.
+// The path "m" refers to the key itself, and returns no indices.
+func ParseBIP32Path(path string) ([]uint32, error) {
+	segments := strings.Split(path, "/")
+	if segments[0] != "m" {
+		return nil, fmt.Errorf("config: path %q does not start with m", path)
+	}
+	indices := make([]uint32, 0, len(segments)-1)
+	for _, segment := range segments[1:] {
+		if strings.HasSuffix(segment, "'") || strings.HasSuffix(segment, "h") {
+			return nil, fmt.Errorf("config: path %q: hardened index %s can't be derived from the public key", path, segment)
+		}
+		i, err := strconv.ParseUint(segment, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("config: path %q: invalid index %q", path, segment)
+		}
+		indices = append(indices, uint32(i))
+	}
+	return indices, nil
+}
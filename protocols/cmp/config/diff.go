@@ -0,0 +1,111 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// Diff returns a description of each public field for which c and other disagree,
+// or nil if they describe the same group state.
+//
+// Only the data shared by all parties is compared: the Group, Threshold, RID, ChainKey,
+// and the Public data of each party. The configs may therefore belong to different parties.
+func (c *Config) Diff(other *Config) []string {
+	var diffs []string
+	if c.Group.Name() != other.Group.Name() {
+		diffs = append(diffs, fmt.Sprintf("group: %s != %s", c.Group.Name(), other.Group.Name()))
+	}
+	if c.Threshold != other.Threshold {
+		diffs = append(diffs, fmt.Sprintf("threshold: %d != %d", c.Threshold, other.Threshold))
+	}
+	if !bytes.Equal(c.RID, other.RID) {
+		diffs = append(diffs, fmt.Sprintf("rid: %x != %x", []byte(c.RID), []byte(other.RID)))
+	}
+	if !bytes.Equal(c.ChainKey, other.ChainKey) {
+		diffs = append(diffs, fmt.Sprintf("chain key: %x != %x", []byte(c.ChainKey), []byte(other.ChainKey)))
+	}
+
+	ids := make([]party.ID, 0, len(c.Public)+len(other.Public))
+	for j := range c.Public {
+		ids = append(ids, j)
+	}
+	for j := range other.Public {
+		if _, ok := c.Public[j]; !ok {
+			ids = append(ids, j)
+		}
+	}
+	for _, j := range party.NewIDSlice(ids) {
+		p, ok := c.Public[j]
+		q, otherOk := other.Public[j]
+		switch {
+		case !otherOk:
+			diffs = append(diffs, fmt.Sprintf("party %s: only in the first config", j))
+		case !ok:
+			diffs = append(diffs, fmt.Sprintf("party %s: only in the second config", j))
+		default:
+			for _, field := range p.diff(q) {
+				diffs = append(diffs, fmt.Sprintf("party %s: %s", j, field))
+			}
+		}
+	}
+	return diffs
+}
+
+// diff returns the names of the fields of p and q which differ.
+func (p *Public) diff(q *Public) []string {
+	if p == nil || q == nil {
+		if p != q {
+			return []string{"missing public data"}
+		}
+		return nil
+	}
+	var diffs []string
+	if !equalPoints(p.ECDSA, q.ECDSA) {
+		diffs = append(diffs, "ECDSA share differs")
+	}
+	if !equalPoints(p.ElGamal, q.ElGamal) {
+		diffs = append(diffs, "ElGamal key differs")
+	}
+	if p.Paillier == nil || q.Paillier == nil || p.Pedersen == nil || q.Pedersen == nil {
+		if p.Paillier != q.Paillier || p.Pedersen != q.Pedersen {
+			diffs = append(diffs, "missing Paillier or Pedersen parameters")
+		}
+		return diffs
+	}
+	// the Paillier and Pedersen moduli are the same for a valid config, so a single difference is reported
+	if !equalModuli(p.Paillier.N(), q.Paillier.N()) || !equalModuli(p.Pedersen.N(), q.Pedersen.N()) {
+		diffs = append(diffs, "N differs")
+	}
+	if !equalNats(p.Pedersen.S(), q.Pedersen.S()) {
+		diffs = append(diffs, "Pedersen S differs")
+	}
+	if !equalNats(p.Pedersen.T(), q.Pedersen.T()) {
+		diffs = append(diffs, "Pedersen T differs")
+	}
+	return diffs
+}
+
+func equalPoints(a, b curve.Point) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(b)
+}
+
+func equalModuli(a, b *saferith.Modulus) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Nat().Eq(b.Nat()) == 1
+}
+
+func equalNats(a, b *saferith.Nat) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Eq(b) == 1
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"errors"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"golang.org/x/crypto/sha3"
+)
+
+// EthereumAddress returns the Ethereum address of the public key of c,
+// that is the last 20 bytes of Keccak256(x ‖ y), where (x, y) are the coordinates of the public key.
+//
+// It returns an error if c is not over secp256k1.
+func (c *Config) EthereumAddress() ([20]byte, error) {
+	return ethereumAddress(c.PublicPoint())
+}
+
+// EthereumAddress returns the Ethereum address of p.PublicKey, as in Config.EthereumAddress.
+func (p *PublicConfig) EthereumAddress() ([20]byte, error) {
+	return ethereumAddress(p.PublicKey)
+}
+
+// DeriveEthereumAddress returns the Ethereum address of the key at path, as described by ParseBIP32Path.
+//
+// This is the address of c.DeriveBIP32Path(path), computed from the public key only.
+func (c *Config) DeriveEthereumAddress(path string) ([20]byte, error) {
+	child, err := c.PublicConfig().DeriveBIP32Path(path)
+	if err != nil {
+		return [20]byte{}, err
+	}
+	return child.EthereumAddress()
+}
+
+func ethereumAddress(publicKey curve.Point) ([20]byte, error) {
+	point, ok := publicKey.(*curve.Secp256k1Point)
+	if !ok {
+		return [20]byte{}, errors.New("config: Ethereum addresses require secp256k1")
+	}
+	if point.IsIdentity() {
+		return [20]byte{}, errors.New("config: public key is the identity")
+	}
+	// the encoding is 0x04 ‖ x ‖ y
+	uncompressed, err := point.MarshalUncompressed()
+	if err != nil {
+		return [20]byte{}, err
+	}
+	h := sha3.NewLegacyKeccak256()
+	_, _ = h.Write(uncompressed[1:])
+	var address [20]byte
+	copy(address[:], h.Sum(nil)[12:])
+	return address, nil
+}
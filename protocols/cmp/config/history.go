@@ -0,0 +1,48 @@
+package config
+
+import (
+	"github.com/taurusgroup/multi-party-sig/internal/types"
+)
+
+// Operation identifies how a Config was produced.
+type Operation string
+
+const (
+	OperationKeygen     Operation = "keygen"
+	OperationRefresh    Operation = "refresh"
+	OperationRefreshAux Operation = "refresh-aux"
+	OperationReshare    Operation = "reshare"
+	OperationDerive     Operation = "derive"
+	OperationDealer     Operation = "dealer"
+	OperationCombine    Operation = "combine"
+	OperationRotateRID  Operation = "rotate-rid"
+)
+
+// HistoryEntry records one operation in the lineage of a Config.
+//
+// It only contains public data, so that the history can be handed to an auditor.
+type HistoryEntry struct {
+	// Operation is the operation which produced the config.
+	Operation Operation
+	// ParentRID is the RID of the config the operation was applied to.
+	// It is empty for a keygen, or a config created by a dealer.
+	ParentRID types.RID
+	// Threshold is the threshold of the resulting config.
+	Threshold int
+}
+
+// NextHistory returns the history of a config obtained by applying op to parent, with the given threshold.
+//
+// The history of parent is copied, so that it is never modified. parent may be nil for a keygen.
+func NextHistory(parent *Config, op Operation, threshold int) []HistoryEntry {
+	entry := HistoryEntry{Operation: op, Threshold: threshold}
+	if parent == nil {
+		return []HistoryEntry{entry}
+	}
+	if parent.RID != nil {
+		entry.ParentRID = parent.RID.Copy()
+	}
+	history := make([]HistoryEntry, 0, len(parent.History)+1)
+	history = append(history, parent.History...)
+	return append(history, entry)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/pedersen"
+	zksafeprime "github.com/taurusgroup/multi-party-sig/pkg/zk/safeprime"
 )
 
 // EmptyConfig creates an empty Config with a fixed group, ready for unmarshalling.
@@ -23,6 +24,8 @@ func EmptyConfig(group curve.Curve) *Config {
 	}
 }
 
+// configMarshal is the serialized form of a Config.
+// P and Q, as well as N, S and T in publicMarshal, are nil for a SchnorrOnly config.
 type configMarshal struct {
 	ID             party.ID
 	Threshold      int
@@ -30,13 +33,16 @@ type configMarshal struct {
 	P, Q           *saferith.Nat
 	RID, ChainKey  types.RID
 	Public         []cbor.RawMessage
+	History        []HistoryEntry `cbor:",omitempty"`
 }
 
+// N, S and T are kept encoded, so that their length is checked by paillier.ModulusFromBytes
+// and paillier.NatFromBytes before they are decoded.
 type publicMarshal struct {
 	ID             party.ID
 	ECDSA, ElGamal curve.Point
-	N              *saferith.Modulus
-	S, T           *saferith.Nat
+	N, S, T        []byte
+	SafePrime      *zksafeprime.Proof `cbor:",omitempty"`
 }
 
 func (c *Config) MarshalBinary() ([]byte, error) {
@@ -44,12 +50,13 @@ func (c *Config) MarshalBinary() ([]byte, error) {
 	for _, id := range c.PartyIDs() {
 		p := c.Public[id]
 		pm := &publicMarshal{
-			ID:      id,
-			ECDSA:   p.ECDSA,
-			ElGamal: p.ElGamal,
-			N:       p.Pedersen.N(),
-			S:       p.Pedersen.S(),
-			T:       p.Pedersen.T(),
+			ID:        id,
+			ECDSA:     p.ECDSA,
+			ElGamal:   p.ElGamal,
+			SafePrime: p.SafePrime,
+		}
+		if p.Pedersen != nil {
+			pm.N, pm.S, pm.T = p.Pedersen.N().Bytes(), p.Pedersen.S().Bytes(), p.Pedersen.T().Bytes()
 		}
 		data, err := cbor.Marshal(pm)
 		if err != nil {
@@ -57,17 +64,20 @@ func (c *Config) MarshalBinary() ([]byte, error) {
 		}
 		ps = append(ps, data)
 	}
-	return cbor.Marshal(&configMarshal{
+	cm := &configMarshal{
 		ID:        c.ID,
 		Threshold: c.Threshold,
 		ECDSA:     c.ECDSA,
 		ElGamal:   c.ElGamal,
-		P:         c.Paillier.P(),
-		Q:         c.Paillier.Q(),
 		RID:       c.RID,
 		ChainKey:  c.ChainKey,
 		Public:    ps,
-	})
+		History:   c.History,
+	}
+	if c.Paillier != nil {
+		cm.P, cm.Q = c.Paillier.P(), c.Paillier.Q()
+	}
+	return cbor.Marshal(cm)
 }
 
 func (c *Config) UnmarshalBinary(data []byte) error {
@@ -87,14 +97,17 @@ func (c *Config) UnmarshalBinary(data []byte) error {
 		return errors.New("config: ECDSA or ElGamal secret key is zero")
 	}
 
-	// get Paillier secret key
-	if err := paillier.ValidatePrime(cm.P); err != nil {
-		return fmt.Errorf("config: prime P: %w", err)
-	}
-	if err := paillier.ValidatePrime(cm.Q); err != nil {
-		return fmt.Errorf("config: prime Q: %w", err)
+	// get Paillier secret key, unless the config is SchnorrOnly
+	var paillierSecret *paillier.SecretKey
+	if cm.P != nil || cm.Q != nil {
+		if err := paillier.ValidatePrime(cm.P); err != nil {
+			return fmt.Errorf("config: prime P: %w", err)
+		}
+		if err := paillier.ValidatePrime(cm.Q); err != nil {
+			return fmt.Errorf("config: prime Q: %w", err)
+		}
+		paillierSecret = paillier.NewSecretKeyFromPrimes(cm.P, cm.Q)
 	}
-	paillierSecret := paillier.NewSecretKeyFromPrimes(cm.P, cm.Q)
 
 	// handle public parameters
 	ps := make(map[party.ID]*Public, len(cm.Public))
@@ -110,33 +123,59 @@ func (c *Config) UnmarshalBinary(data []byte) error {
 			return fmt.Errorf("config: party %s: duplicate entry", p.ID)
 		}
 
+		if paillierSecret == nil {
+			if len(p.N) != 0 || len(p.S) != 0 || len(p.T) != 0 {
+				return fmt.Errorf("config: party %s: unexpected Pedersen parameters", p.ID)
+			}
+			ps[p.ID] = &Public{
+				ECDSA:   p.ECDSA,
+				ElGamal: p.ElGamal,
+			}
+			continue
+		}
+
+		s, err := paillier.NatFromBytes(p.S)
+		if err != nil {
+			return fmt.Errorf("config: party %s: Pedersen parameter: %w", p.ID, err)
+		}
+		t, err := paillier.NatFromBytes(p.T)
+		if err != nil {
+			return fmt.Errorf("config: party %s: Pedersen parameter: %w", p.ID, err)
+		}
+
 		// handle our own key separately
 		if p.ID == cm.ID {
 			ps[p.ID] = &Public{
-				ECDSA:    cm.ECDSA.ActOnBase(),
-				ElGamal:  cm.ElGamal.ActOnBase(),
-				Paillier: paillierSecret.PublicKey,
-				Pedersen: pedersen.New(paillierSecret.Modulus(), p.S, p.T),
+				ECDSA:     cm.ECDSA.ActOnBase(),
+				ElGamal:   cm.ElGamal.ActOnBase(),
+				Paillier:  paillierSecret.PublicKey,
+				Pedersen:  pedersen.New(paillierSecret.Modulus(), s, t),
+				SafePrime: p.SafePrime,
 			}
 			continue
 		}
 
-		if err := paillier.ValidateN(p.N); err != nil {
+		n, err := paillier.ModulusFromBytes(p.N)
+		if err != nil {
 			return fmt.Errorf("config: party %s: %w", p.ID, err)
 		}
-		if err := pedersen.ValidateParameters(p.N, p.S, p.T); err != nil {
+		if err = paillier.ValidateN(n); err != nil {
+			return fmt.Errorf("config: party %s: %w", p.ID, err)
+		}
+		if err = pedersen.ValidateParameters(n, s, t); err != nil {
 			return fmt.Errorf("config: party %s: %w", p.ID, err)
 		}
 		if p.ECDSA.IsIdentity() || p.ElGamal.IsIdentity() {
 			return fmt.Errorf("config: party %s: ECDSA or ElGamal public key is identity", p.ID)
 		}
 
-		paillierPublic := paillier.NewPublicKey(p.N)
+		paillierPublic := paillier.NewPublicKey(n)
 		ps[p.ID] = &Public{
-			ECDSA:    p.ECDSA,
-			ElGamal:  p.ElGamal,
-			Paillier: paillierPublic,
-			Pedersen: pedersen.New(paillierPublic.Modulus(), p.S, p.T),
+			ECDSA:     p.ECDSA,
+			ElGamal:   p.ElGamal,
+			Paillier:  paillierPublic,
+			Pedersen:  pedersen.New(paillierPublic.Modulus(), s, t),
+			SafePrime: p.SafePrime,
 		}
 	}
 
@@ -151,7 +190,7 @@ func (c *Config) UnmarshalBinary(data []byte) error {
 		return errors.New("config: no public data for this party")
 	}
 
-	*c = Config{
+	cfg := Config{
 		Group:     c.Group,
 		ID:        cm.ID,
 		Threshold: cm.Threshold,
@@ -161,6 +200,11 @@ func (c *Config) UnmarshalBinary(data []byte) error {
 		RID:       cm.RID,
 		ChainKey:  cm.ChainKey,
 		Public:    ps,
+		History:   cm.History,
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
 	}
+	*c = cfg
 	return nil
 }
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// MembershipStep is one level of a MembershipProof.
+type MembershipStep struct {
+	// Sibling is the hash of the other node at this level.
+	Sibling [32]byte
+	// Left is true if Sibling is the left child of the parent node.
+	Left bool
+}
+
+// MembershipProof shows that a party's Public data is included in the tree whose root is given by PartySetRoot.
+type MembershipProof struct {
+	Steps []MembershipStep
+}
+
+// PartySetRoot returns the root of a Merkle tree whose leaves are the Public entries of all parties,
+// sorted by ID.
+//
+// It commits to the full party set, so that a verifier who only knows the root can check
+// that a given party belongs to it using a MembershipProof.
+// It returns an error if the Public data of a party can't be serialized.
+func (c *Config) PartySetRoot() ([32]byte, error) {
+	level, err := c.merkleLeaves()
+	if err != nil || len(level) == 0 {
+		return [32]byte{}, err
+	}
+	for len(level) > 1 {
+		level = merkleParents(level)
+	}
+	return level[0], nil
+}
+
+// PartyMembershipProof returns a proof that the Public data of id is included in PartySetRoot.
+func (c *Config) PartyMembershipProof(id party.ID) (*MembershipProof, error) {
+	partyIDs := c.PartyIDs()
+	index := -1
+	for i, j := range partyIDs {
+		if j == id {
+			index = i
+		}
+	}
+	if index < 0 {
+		return nil, errors.New("config: party not found")
+	}
+
+	proof := &MembershipProof{}
+	level, err := c.merkleLeaves()
+	if err != nil {
+		return nil, err
+	}
+	for len(level) > 1 {
+		// the last node of an odd level has no sibling and is promoted as is
+		if sibling := index ^ 1; sibling < len(level) {
+			proof.Steps = append(proof.Steps, MembershipStep{
+				Sibling: level[sibling],
+				Left:    sibling < index,
+			})
+		}
+		level = merkleParents(level)
+		index /= 2
+	}
+	return proof, nil
+}
+
+// VerifyPartyMembership returns true if proof shows that public is the data of party id,
+// in the party set committed to by root.
+func VerifyPartyMembership(root [32]byte, id party.ID, public *Public, proof *MembershipProof) bool {
+	if public == nil || proof == nil {
+		return false
+	}
+	node, err := merkleLeaf(id, public)
+	if err != nil {
+		return false
+	}
+	for _, step := range proof.Steps {
+		if step.Left {
+			node = merkleNode(step.Sibling, node)
+		} else {
+			node = merkleNode(node, step.Sibling)
+		}
+	}
+	return node == root
+}
+
+// merkleLeaves returns the hashes of all Public entries, sorted by ID.
+func (c *Config) merkleLeaves() ([][32]byte, error) {
+	partyIDs := c.PartyIDs()
+	leaves := make([][32]byte, len(partyIDs))
+	for i, j := range partyIDs {
+		leaf, err := merkleLeaf(j, c.Public[j])
+		if err != nil {
+			return nil, err
+		}
+		leaves[i] = leaf
+	}
+	return leaves, nil
+}
+
+// merkleParents returns the level above nodes.
+func merkleParents(nodes [][32]byte) [][32]byte {
+	parents := make([][32]byte, 0, (len(nodes)+1)/2)
+	for i := 0; i < len(nodes); i += 2 {
+		if i+1 == len(nodes) {
+			parents = append(parents, nodes[i])
+		} else {
+			parents = append(parents, merkleNode(nodes[i], nodes[i+1]))
+		}
+	}
+	return parents
+}
+
+// merkleLeaf returns the hash of the Public data of id.
+func merkleLeaf(id party.ID, public *Public) ([32]byte, error) {
+	h := hash.New(&hash.BytesWithDomain{TheDomain: "Merkle Leaf", Bytes: []byte(id)})
+	if err := h.WriteAny(public); err != nil {
+		return [32]byte{}, fmt.Errorf("config: party %s: %w", id, err)
+	}
+	return merkleSum(h), nil
+}
+
+func merkleNode(left, right [32]byte) [32]byte {
+	h := hash.New(&hash.BytesWithDomain{TheDomain: "Merkle Node", Bytes: append(left[:], right[:]...)})
+	return merkleSum(h)
+}
+
+func merkleSum(h *hash.Hash) [32]byte {
+	var out [32]byte
+	_, _ = io.ReadFull(h.Digest(), out[:])
+	return out
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// PublicBuilder assembles the Public map of a Config one party at a time,
+// for instance when the data of each party is received separately.
+type PublicBuilder struct {
+	group    curve.Curve
+	partyIDs party.IDSlice
+	public   map[party.ID]*Public
+}
+
+// NewPublicBuilder returns a PublicBuilder for the parties in partyIDs, whose points are in group.
+func NewPublicBuilder(group curve.Curve, partyIDs []party.ID) (*PublicBuilder, error) {
+	ids := party.NewIDSlice(partyIDs)
+	if len(ids) == 0 {
+		return nil, errors.New("config: no parties")
+	}
+	if !ids.Valid() {
+		return nil, errors.New("config: duplicate party IDs")
+	}
+	return &PublicBuilder{
+		group:    group,
+		partyIDs: ids,
+		public:   make(map[party.ID]*Public, len(ids)),
+	}, nil
+}
+
+// AddPublic validates public and adds it as the entry of party id.
+//
+// It returns an error if id is not one of the declared parties, or if it already has an entry.
+func (b *PublicBuilder) AddPublic(id party.ID, public *Public) error {
+	if !b.partyIDs.Contains(id) {
+		return fmt.Errorf("config: party %s: not in the party set", id)
+	}
+	if _, ok := b.public[id]; ok {
+		return fmt.Errorf("config: party %s: duplicate entry", id)
+	}
+	if err := public.Validate(b.group); err != nil {
+		return fmt.Errorf("config: party %s: %w", id, err)
+	}
+	b.public[id] = public
+	return nil
+}
+
+// MergePublic adds all entries of public, in the order of their IDs.
+// It stops at the first entry which cannot be added.
+func (b *PublicBuilder) MergePublic(public map[party.ID]*Public) error {
+	ids := make([]party.ID, 0, len(public))
+	for id := range public {
+		ids = append(ids, id)
+	}
+	for _, id := range party.NewIDSlice(ids) {
+		if err := b.AddPublic(id, public[id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Missing returns the sorted IDs of the parties which do not have an entry yet.
+func (b *PublicBuilder) Missing() party.IDSlice {
+	missing := make(party.IDSlice, 0, len(b.partyIDs)-len(b.public))
+	for _, id := range b.partyIDs {
+		if _, ok := b.public[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// Finalize returns the assembled map, which can be used as Config.Public.
+// It returns an error if any of the declared parties does not have an entry.
+func (b *PublicBuilder) Finalize() (map[party.ID]*Public, error) {
+	if missing := b.Missing(); len(missing) > 0 {
+		return nil, fmt.Errorf("config: missing entries for parties %v", missing)
+	}
+	public := make(map[party.ID]*Public, len(b.public))
+	for id, p := range b.public {
+		public[id] = p
+	}
+	return public, nil
+}
+
+// PublicShareAccumulator computes the public key of a Config from the ECDSA public shares of its parties, as they arrive,
+// for instance to display it while the parties of a keygen report their results.
+type PublicShareAccumulator struct {
+	group     curve.Curve
+	partyIDs  party.IDSlice
+	threshold int
+	shares    map[party.ID]curve.Point
+}
+
+// AccumulatePublicShares returns a PublicShareAccumulator for the parties in partyIDs,
+// whose shares are points in group with the given threshold.
+func AccumulatePublicShares(group curve.Curve, partyIDs []party.ID, threshold int) (*PublicShareAccumulator, error) {
+	ids := party.NewIDSlice(partyIDs)
+	if !ids.Valid() {
+		return nil, errors.New("config: duplicate party IDs")
+	}
+	if !ValidThreshold(threshold, len(ids)) {
+		return nil, fmt.Errorf("config: threshold %d is invalid", threshold)
+	}
+	if err := polynomial.CheckInterpolationDomain(group, ids); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	return &PublicShareAccumulator{
+		group:     group,
+		partyIDs:  ids,
+		threshold: threshold,
+		shares:    make(map[party.ID]curve.Point, len(ids)),
+	}, nil
+}
+
+// Add records share as the ECDSA public share of party id, which is Public.ECDSA in its Config.
+//
+// It returns an error if id is not one of the declared parties, if it already has a share,
+// or if share is not a point of the group other than the identity.
+func (a *PublicShareAccumulator) Add(id party.ID, share curve.Point) error {
+	if !a.partyIDs.Contains(id) {
+		return fmt.Errorf("config: party %s: not in the party set", id)
+	}
+	if _, ok := a.shares[id]; ok {
+		return fmt.Errorf("config: party %s: duplicate entry", id)
+	}
+	if share == nil || share.IsIdentity() {
+		return fmt.Errorf("config: party %s: ECDSA public share is identity", id)
+	}
+	if err := curve.CheckCurve(a.group, share); err != nil {
+		return fmt.Errorf("config: party %s: %w", id, err)
+	}
+	a.shares[id] = share
+	return nil
+}
+
+// Ready returns true once Threshold+1 shares were added, which is enough to compute the public key.
+func (a *PublicShareAccumulator) Ready() bool {
+	return len(a.shares) > a.threshold
+}
+
+// PublicPoint returns the public key interpolated from the shares added so far, or an error if it is not Ready.
+//
+// If the shares are consistent, the result is the same as Config.PublicPoint, whichever shares were added.
+// Their consistency is not checked, since it only follows from Config.Validate once all shares are known.
+func (a *PublicShareAccumulator) PublicPoint() (curve.Point, error) {
+	if !a.Ready() {
+		return nil, fmt.Errorf("config: %d of the %d shares required for the public key", len(a.shares), a.threshold+1)
+	}
+	ids := make([]party.ID, 0, len(a.shares))
+	for id := range a.shares {
+		ids = append(ids, id)
+	}
+	sum := a.group.NewPoint()
+	for id, l := range polynomial.Lagrange(a.group, ids) {
+		sum = sum.Add(l.Act(a.shares[id]))
+	}
+	return sum, nil
+}
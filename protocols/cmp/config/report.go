@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/params"
+)
+
+// MinCurveSecurityBits is the security level below which SecurityReport warns about the curve.
+const MinCurveSecurityBits = 128
+
+// SecurityReport summarizes the parameters of a Config which determine its security.
+type SecurityReport struct {
+	// Curve is the name of the group of the Config.
+	Curve string
+	// CurveSecurityBits is the security level of the discrete logarithm in the group, half the bits of its order.
+	CurveSecurityBits int
+	// PaillierBits is the bit length of the smallest Paillier modulus among the parties, or 0 for a SchnorrOnly config.
+	PaillierBits int
+	// Threshold is the maximum number of corrupted parties tolerated.
+	Threshold int
+	// Parties is the number of parties sharing the key.
+	Parties int
+	// SafePrimeProofs is true if every party proved that its Paillier modulus is made of safe primes.
+	SafePrimeProofs bool
+	// Warnings describes each parameter which does not meet the requirements of the protocols, sorted by party.
+	// It is empty for a healthy config.
+	Warnings []string
+}
+
+// SecurityReport returns the security parameters of c, together with warnings for those which are too weak.
+//
+// Unlike Validate, it does not stop at the first problem, so that auditors get a complete picture.
+// The secret keys are not checked.
+func (c *Config) SecurityReport() *SecurityReport {
+	r := &SecurityReport{
+		Curve:             c.Group.Name(),
+		CurveSecurityBits: c.Group.ScalarBits() / 2,
+		Threshold:         c.Threshold,
+		Parties:           len(c.Public),
+		SafePrimeProofs:   !c.SchnorrOnly(),
+	}
+	if r.CurveSecurityBits < MinCurveSecurityBits {
+		r.warn("curve %s: %d bits of security, below %d", r.Curve, r.CurveSecurityBits, MinCurveSecurityBits)
+	}
+	if c.Threshold == 0 && len(c.Public) > 1 {
+		r.warn("threshold 0: any single party can sign")
+	}
+	if !ValidThreshold(c.Threshold, len(c.Public)) {
+		r.warn("threshold %d: invalid for %d parties", c.Threshold, len(c.Public))
+	}
+
+	for _, j := range c.PartyIDs() {
+		public := c.Public[j]
+		if public == nil {
+			r.SafePrimeProofs = false
+			r.warn("party %s: no public data", j)
+			continue
+		}
+		if public.SafePrime == nil {
+			r.SafePrimeProofs = false
+		}
+		if public.Paillier != nil {
+			bits := public.Paillier.N().BitLen()
+			if r.PaillierBits == 0 || bits < r.PaillierBits {
+				r.PaillierBits = bits
+			}
+			if bits < params.BitsPaillier {
+				// Validate would fail on the length, which is more informative
+				r.warn("party %s: Paillier modulus has %d bits, below %d", j, bits, params.BitsPaillier)
+				continue
+			}
+		}
+		if err := public.Validate(c.Group); err != nil {
+			r.warn("party %s: %v", j, err)
+		}
+	}
+	return r
+}
+
+func (r *SecurityReport) warn(format string, a ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, a...))
+}
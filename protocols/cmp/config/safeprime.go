@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	zksafeprime "github.com/taurusgroup/multi-party-sig/pkg/zk/safeprime"
+)
+
+// safePrimeHash returns the hash used for the SafePrime proof of party id.
+//
+// It does not depend on a session, so that the proof stored in a Public can be verified by anyone at any time.
+func safePrimeHash(id party.ID) *hash.Hash {
+	return hash.New(&hash.BytesWithDomain{TheDomain: "CMP Safe Prime Proof", Bytes: []byte(id)})
+}
+
+// NewSafePrimeProof returns the proof stored in Public.SafePrime of party id, whose Paillier key is sk.
+func NewSafePrimeProof(id party.ID, sk *paillier.SecretKey, pl *pool.Pool) *zksafeprime.Proof {
+	return zksafeprime.NewProof(safePrimeHash(id), zksafeprime.Private{
+		P:   sk.P(),
+		Q:   sk.Q(),
+		Phi: sk.Phi(),
+	}, zksafeprime.Public{N: sk.N()}, pl)
+}
+
+// VerifySafePrimeProof returns true if proof shows that the Paillier modulus n of party id is made of safe primes,
+// in the sense of zksafeprime.Proof.
+func VerifySafePrimeProof(id party.ID, n *saferith.Modulus, proof *zksafeprime.Proof, pl *pool.Pool) bool {
+	return proof.Verify(zksafeprime.Public{N: n}, safePrimeHash(id), pl)
+}
+
+// VerifySafePrimes returns an error if a party of c has no SafePrime proof, or if its proof does not verify.
+//
+// Validate does not check these proofs, since they are expensive to verify and only generated on request.
+func (c *Config) VerifySafePrimes(pl *pool.Pool) error {
+	for _, j := range c.PartyIDs() {
+		public := c.Public[j]
+		if public.Paillier == nil || public.SafePrime == nil {
+			return fmt.Errorf("config: party %s: missing safe prime proof", j)
+		}
+		if !VerifySafePrimeProof(j, public.Paillier.N(), public.SafePrime, pl) {
+			return fmt.Errorf("config: party %s: failed to verify safe prime proof", j)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// PublicSummary is a compact description of the group of a Config, meant to be published,
+// for instance by a block explorer displaying an address backed by a threshold key.
+//
+// Unlike a full export of the Public parameters, it contains no per-party data such as
+// the Paillier moduli, and its size does not depend on the number of parties.
+type PublicSummary struct {
+	// PublicKey is the public key of the group, as returned by Config.PublicPoint.
+	PublicKey curve.Point
+	// Threshold is the maximum number of parties that can be corrupted.
+	Threshold int
+	// Parties is the number of parties in the group.
+	Parties int
+	// RID is the common randomness agreed upon during keygen.
+	RID types.RID
+}
+
+// PublicSummary returns the serialization of the PublicSummary of c,
+// which can be read back with ParsePublicSummary.
+func (c *Config) PublicSummary() ([]byte, error) {
+	summary := &PublicSummary{
+		PublicKey: c.PublicPoint(),
+		Threshold: c.Threshold,
+		Parties:   len(c.Public),
+		RID:       c.RID.Copy(),
+	}
+	data, err := cbor.Marshal(summary)
+	if err != nil {
+		return nil, fmt.Errorf("config: public summary: %w", err)
+	}
+	return data, nil
+}
+
+// ParsePublicSummary reads a summary produced by Config.PublicSummary, for a config over group.
+func ParsePublicSummary(group curve.Curve, data []byte) (*PublicSummary, error) {
+	summary := &PublicSummary{PublicKey: group.NewPoint()}
+	if err := cbor.Unmarshal(data, summary); err != nil {
+		return nil, fmt.Errorf("config: public summary: %w", err)
+	}
+	if !ValidThreshold(summary.Threshold, summary.Parties) {
+		return nil, fmt.Errorf("config: public summary: threshold %d is invalid for %d parties", summary.Threshold, summary.Parties)
+	}
+	if summary.PublicKey.IsIdentity() {
+		return nil, errors.New("config: public summary: public key is the identity")
+	}
+	if err := summary.RID.Validate(); err != nil {
+		return nil, fmt.Errorf("config: public summary: %w", err)
+	}
+	return summary, nil
+}
@@ -0,0 +1,115 @@
+package cmp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pedersen"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+// TrustedDealer must be passed to DealerSplit, to acknowledge that the dealer knows the full ECDSA secret key.
+type TrustedDealer bool
+
+// IUnderstandTheDealerKnowsTheSecret is the only value of TrustedDealer accepted by DealerSplit.
+const IUnderstandTheDealerKnowsTheSecret TrustedDealer = true
+
+// DealerSplit splits an existing ECDSA secret key between parties with the given threshold,
+// for instance to migrate a single-key wallet to threshold signing.
+//
+// Every party receives fresh ElGamal, Paillier and Pedersen parameters, and the configs share a new RID and chain key.
+//
+// Unlike Keygen, the secret is known to the dealer, which must therefore be trusted not to keep or leak it.
+// The same precautions as RecoverAndResplit apply.
+func DealerSplit(secret curve.Scalar, parties []party.ID, threshold int, acknowledge TrustedDealer, pl *pool.Pool) (map[party.ID]*Config, error) {
+	if acknowledge != IUnderstandTheDealerKnowsTheSecret {
+		return nil, errors.New("cmp.DealerSplit: the use of a trusted dealer must be acknowledged")
+	}
+	if secret == nil || secret.IsZero() {
+		return nil, errors.New("cmp.DealerSplit: secret is zero")
+	}
+	chainKey, err := types.NewRID(sample.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("cmp.DealerSplit: %w", err)
+	}
+	configs, err := split(secret, chainKey, parties, threshold, config.NextHistory(nil, config.OperationDealer, threshold), pl)
+	if err != nil {
+		return nil, fmt.Errorf("cmp.DealerSplit: %w", err)
+	}
+	return configs, nil
+}
+
+// split creates a Shamir sharing of secret between parties, and returns their configs with the given history.
+func split(secret curve.Scalar, chainKey types.RID, parties []party.ID, threshold int, history []config.HistoryEntry, pl *pool.Pool) (map[party.ID]*Config, error) {
+	group := secret.Curve()
+	partyIDs := party.NewIDSlice(parties)
+	if !partyIDs.Valid() {
+		return nil, errors.New("duplicate new parties")
+	}
+	if err := config.CheckPartyCount(len(partyIDs)); err != nil {
+		return nil, err
+	}
+	if !config.ValidThreshold(threshold, len(partyIDs)) {
+		return nil, fmt.Errorf("threshold %d is invalid for %d parties", threshold, len(partyIDs))
+	}
+	if err := polynomial.CheckInterpolationDomain(group, partyIDs); err != nil {
+		return nil, err
+	}
+
+	rid, err := types.NewRID(sample.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	f := polynomial.NewPolynomial(group, threshold, secret)
+	configs := make(map[party.ID]*Config, len(partyIDs))
+	public := make(map[party.ID]*config.Public, len(partyIDs))
+	for _, id := range partyIDs {
+		paillierSecret := paillier.NewSecretKey(pl)
+		// the trapdoor λ is only used by keygen to prove the parameters, which a dealt config does not need
+		pedersenPublic, _ := paillierSecret.GeneratePedersen()
+		if err = pedersen.ValidateParameters(pedersenPublic.N(), pedersenPublic.S(), pedersenPublic.T()); err != nil {
+			return nil, fmt.Errorf("party %s: %w", id, err)
+		}
+		elGamalSecret := sample.Scalar(sample.Reader, group)
+		ecdsaSecret := f.Evaluate(id.Scalar(group))
+
+		configs[id] = &Config{
+			Group:     group,
+			ID:        id,
+			Threshold: threshold,
+			ECDSA:     ecdsaSecret,
+			ElGamal:   elGamalSecret,
+			Paillier:  paillierSecret,
+			RID:       rid.Copy(),
+			ChainKey:  chainKey.Copy(),
+			History:   append([]config.HistoryEntry(nil), history...),
+		}
+		public[id] = &config.Public{
+			ECDSA:    ecdsaSecret.ActOnBase(),
+			ElGamal:  elGamalSecret.ActOnBase(),
+			Paillier: paillierSecret.PublicKey,
+			Pedersen: pedersenPublic,
+		}
+	}
+	// each config gets its own map and Public structs, so that modifying the entry of a party in one config
+	// does not affect the others. The points and keys they hold are never modified in place, and are shared.
+	for _, c := range configs {
+		c.Public = make(map[party.ID]*config.Public, len(public))
+		for j, p := range public {
+			copied := *p
+			c.Public[j] = &copied
+		}
+		if err = c.Validate(); err != nil {
+			return nil, fmt.Errorf("party %s: %w", c.ID, err)
+		}
+	}
+	return configs, nil
+}
@@ -1,13 +1,16 @@
 package keygen
 
 import (
-	"crypto/rand"
+	"errors"
 	"fmt"
+	"io"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
@@ -16,9 +19,93 @@ import (
 
 const Rounds round.Number = 5
 
+// refreshMode selects how an existing config is refreshed.
+type refreshMode int
+
+const (
+	// refreshFull re-randomizes the ECDSA shares, and replaces all other keys.
+	refreshFull refreshMode = iota
+	// refreshAux keeps the ECDSA shares, and replaces all other keys.
+	refreshAux
+	// refreshLowerThreshold reshares the ECDSA secret with a lower threshold, and replaces all other keys.
+	refreshLowerThreshold
+)
+
+// auxMode selects the auxiliary parameters generated by a keygen.
+type auxMode int
+
+const (
+	// auxPaillier generates Paillier and Pedersen parameters.
+	auxPaillier auxMode = iota
+	// auxNone generates no Paillier and Pedersen parameters, for a config.SchnorrOnly config.
+	auxNone
+	// auxSafePrime generates Paillier and Pedersen parameters, together with a proof that the Paillier primes are safe.
+	auxSafePrime
+)
+
 func Start(info round.Info, pl *pool.Pool, c *config.Config) protocol.StartFunc {
+	return start(info, pl, c, refreshFull, auxPaillier, nil, nil)
+}
+
+// StartWithPrimeSource is like Start, but the Paillier primes of this party are taken from source,
+// for instance a pool of pre-generated safe primes.
+func StartWithPrimeSource(info round.Info, pl *pool.Pool, c *config.Config, source paillier.PrimeSource) protocol.StartFunc {
+	return start(info, pl, c, refreshFull, auxPaillier, source, nil)
+}
+
+// StartWithSeed is a keygen in which all the secrets of this party, including its Paillier primes,
+// are derived from seed, so that running it again with the same seed for every party gives the same config.
+//
+// This is INSECURE, and must only be used for reproducible tests: anyone who knows seed learns the secret share of this party.
+// The Paillier primes are generated sequentially to be reproducible, which is slower than with a pool.
+// The messages themselves are not reproducible, since the zero-knowledge proofs are still randomized.
+func StartWithSeed(info round.Info, seed []byte) protocol.StartFunc {
+	if seed == nil {
+		seed = []byte{}
+	}
+	return start(info, nil, nil, refreshFull, auxPaillier, nil, seed)
+}
+
+// StartSchnorrOnly is a keygen which skips the generation of the Paillier and Pedersen parameters.
+// The resulting config is SchnorrOnly, and the shares are sent in the clear over the confidential point-to-point channels.
+func StartSchnorrOnly(info round.Info, pl *pool.Pool) protocol.StartFunc {
+	return start(info, pl, nil, refreshFull, auxNone, nil, nil)
+}
+
+// StartWithSafePrimeProofs is a keygen in which every party proves that its Paillier primes are safe,
+// with a zksafeprime.Proof verified by all others and stored in config.Public.SafePrime.
+func StartWithSafePrimeProofs(info round.Info, pl *pool.Pool) protocol.StartFunc {
+	return start(info, pl, nil, refreshFull, auxSafePrime, nil, nil)
+}
+
+// StartAuxRefresh is a refresh of c which only replaces the ElGamal, Paillier and Pedersen keys of all parties.
+// The ECDSA shares of c are kept unchanged.
+func StartAuxRefresh(info round.Info, pl *pool.Pool, c *config.Config) protocol.StartFunc {
+	return start(info, pl, c, refreshAux, auxPaillier, nil, nil)
+}
+
+// StartLowerThreshold is a refresh of c in which the ECDSA secret is reshared with info.Threshold,
+// which must be lower than c.Threshold. All parties of c must take part.
+//
+// Each party Pᵢ shares λᵢ⋅xᵢ, where λᵢ is its Lagrange coefficient for the full set of parties,
+// and the others check that Fᵢ(0) = λᵢ⋅Xᵢ, so that the public key is preserved.
+func StartLowerThreshold(info round.Info, pl *pool.Pool, c *config.Config) protocol.StartFunc {
+	return start(info, pl, c, refreshLowerThreshold, auxPaillier, nil, nil)
+}
+
+// start returns the keygen, or the refresh of c if it is not nil.
+// aux only applies to a keygen: a refresh of c generates the same kind of auxiliary parameters as c has.
+// The Paillier primes are taken from source if it is not nil.
+// If seed is not nil, the secrets of this party are derived from it, see StartWithSeed.
+func start(info round.Info, pl *pool.Pool, c *config.Config, mode refreshMode, aux auxMode, source paillier.PrimeSource, seed []byte) protocol.StartFunc {
 	return func(sessionID []byte) (_ round.Session, err error) {
 		var helper *round.Helper
+		if c == nil && mode != refreshFull {
+			return nil, errors.New("keygen: refresh requires a config")
+		}
+		if err = config.CheckPartyCount(len(info.PartyIDs)); err != nil {
+			return nil, fmt.Errorf("keygen: %w", err)
+		}
 		if c == nil {
 			helper, err = round.NewSession(info, sessionID, pl)
 		} else {
@@ -29,8 +116,42 @@ func Start(info round.Info, pl *pool.Pool, c *config.Config) protocol.StartFunc
 		}
 
 		group := helper.Group()
+		if err = polynomial.CheckInterpolationDomain(group, helper.PartyIDs()); err != nil {
+			return nil, fmt.Errorf("keygen: %w", err)
+		}
+
+		if c != nil && mode == refreshLowerThreshold {
+			if helper.Threshold() >= c.Threshold {
+				return nil, fmt.Errorf("keygen: threshold %d is not lower than %d", helper.Threshold(), c.Threshold)
+			}
+			if helper.N() != len(c.Public) || !helper.PartyIDs().Contains(c.PartyIDs()...) {
+				return nil, errors.New("keygen: all parties must take part in lowering the threshold")
+			}
+			// Fⱼ(0) = λⱼ⋅Xⱼ
+			lagrange := polynomial.Lagrange(group, helper.PartyIDs())
+			ReshareConstants := make(map[party.ID]curve.Point, len(c.Public))
+			for id, public := range c.Public {
+				ReshareConstants[id] = lagrange[id].Act(public.ECDSA)
+			}
+			// fᵢ(X) deg(fᵢ) = t, fᵢ(0) = λᵢ⋅xᵢ
+			VSSConstant := group.NewScalar().Set(lagrange[c.ID]).Mul(c.ECDSA)
+			return &round1{
+				Helper:           helper,
+				PreviousChainKey: c.ChainKey,
+				VSSSecret:        polynomial.NewPolynomial(group, helper.Threshold(), VSSConstant),
+				ReshareConstants: ReshareConstants,
+				SchnorrOnly:      c.SchnorrOnly(),
+				ProveSafePrimes:  c.Public[c.ID].SafePrime != nil,
+				History:          config.NextHistory(c, config.OperationReshare, helper.Threshold()),
+				PrimeSource:      source,
+			}, nil
+		}
 
 		if c != nil {
+			operation := config.OperationRefresh
+			if mode == refreshAux {
+				operation = config.OperationRefreshAux
+			}
 			PublicSharesECDSA := make(map[party.ID]curve.Point, len(c.Public))
 			for id, public := range c.Public {
 				PublicSharesECDSA[id] = public.ECDSA
@@ -41,15 +162,32 @@ func Start(info round.Info, pl *pool.Pool, c *config.Config) protocol.StartFunc
 				PreviousPublicSharesECDSA: PublicSharesECDSA,
 				PreviousChainKey:          c.ChainKey,
 				VSSSecret:                 polynomial.NewPolynomial(group, helper.Threshold(), group.NewScalar()), // fᵢ(X) deg(fᵢ) = t, fᵢ(0) = 0
+				KeepECDSA:                 mode == refreshAux,
+				SchnorrOnly:               c.SchnorrOnly(),
+				ProveSafePrimes:           c.Public[c.ID].SafePrime != nil,
+				History:                   config.NextHistory(c, operation, helper.Threshold()),
+				PrimeSource:               source,
 			}, nil
 		}
 
+		var seeded io.Reader
+		var rand io.Reader = sample.Reader
+		if seed != nil {
+			seeded = hash.New(&hash.BytesWithDomain{TheDomain: "Keygen Seed", Bytes: seed}).Digest()
+			rand = seeded
+		}
+
 		// sample fᵢ(X) deg(fᵢ) = t, fᵢ(0) = secretᵢ
-		VSSConstant := sample.Scalar(rand.Reader, group)
-		VSSSecret := polynomial.NewPolynomial(group, helper.Threshold(), VSSConstant)
+		VSSConstant := sample.Scalar(rand, group)
+		VSSSecret := polynomial.NewPolynomialFromReader(rand, group, helper.Threshold(), VSSConstant)
 		return &round1{
-			Helper:    helper,
-			VSSSecret: VSSSecret,
+			Helper:          helper,
+			VSSSecret:       VSSSecret,
+			SchnorrOnly:     aux == auxNone,
+			ProveSafePrimes: aux == auxSafePrime,
+			History:         config.NextHistory(nil, config.OperationKeygen, helper.Threshold()),
+			PrimeSource:     source,
+			Rand:            seeded,
 		}, nil
 
 	}
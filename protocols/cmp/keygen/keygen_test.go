@@ -1,16 +1,25 @@
 package keygen
 
 import (
+	"crypto/rand"
+	"errors"
+	"fmt"
 	mrand "math/rand"
 	"testing"
 
+	"github.com/cronokirby/saferith"
 	"github.com/fxamacker/cbor/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/test"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
 	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
 )
 
@@ -76,14 +85,112 @@ func TestKeygen(t *testing.T) {
 		rounds = append(rounds, r)
 	}
 
-	for {
-		err, done := test.Rounds(rounds, nil)
-		require.NoError(t, err, "failed to process round")
-		if done {
-			break
+	require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
+	checkOutput(t, rounds)
+}
+
+func TestKeygenWithSeed(t *testing.T) {
+	if testing.Short() {
+		t.Skip("generates the Paillier primes sequentially")
+	}
+	N := 2
+	partyIDs := test.PartyIDs(N)
+
+	keygen := func(seeds map[party.ID][]byte) map[party.ID][]byte {
+		rounds := make([]round.Session, 0, N)
+		for _, partyID := range partyIDs {
+			r, err := StartWithSeed(round.Info{
+				ProtocolID:       "cmp/keygen-test",
+				FinalRoundNumber: Rounds,
+				SelfID:           partyID,
+				PartyIDs:         partyIDs,
+				Threshold:        N - 1,
+				Group:            group,
+			}, seeds[partyID])(nil)
+			require.NoError(t, err)
+			rounds = append(rounds, r)
+		}
+		require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
+		configs := make(map[party.ID][]byte, N)
+		for _, r := range rounds {
+			require.IsType(t, &round.Output{}, r)
+			c := r.(*round.Output).Result.(*config.Config)
+			data, err := c.MarshalBinary()
+			require.NoError(t, err)
+			configs[c.ID] = data
 		}
+		return configs
 	}
+
+	seeds := map[party.ID][]byte{partyIDs[0]: []byte("seed a"), partyIDs[1]: []byte("seed b")}
+	first := keygen(seeds)
+	assert.Equal(t, first, keygen(seeds), "the same seeds should give the same configs")
+
+	seeds[partyIDs[1]] = []byte("seed c")
+	other := keygen(seeds)
+	for _, id := range partyIDs {
+		assert.NotEqual(t, first[id], other[id], "different seeds should give different configs")
+	}
+}
+
+// testPrimes are safe Blum primes of params.BitsBlumPrime bits.
+var testPrimes = []string{
+	"D08769E92F80F7FDFB85EC02AFFDAED0FDE2782070757F191DCDC4D108110AC1E31C07FC253B5F7B91C5D9F203AA0572D3F2062A3D2904C535C6ACCA7D5674E1C2640720E762C72B66931F483C2D910908CF02EA6723A0CBBB1016CA696C38FEAC59B31E40584C8141889A11F7A38F5B17811D11F42CD15B8470F11C6183802B",
+	"C21239C3484FC3C8409F40A9A22FABFFE26CA10C27506E3E017C2EC8C4B98D7A6D30DED0686869884BE9BAD27F5241B7313F73D19E9E4B384FABF9554B5BB4D517CBAC0268420C63D545612C9ADABEEDF20F94244E7F8F2080B0C675AC98D97C580D43375F999B1AC127EC580B89B2D302EF33DD5FD8474A241B0398F6088CA7",
+	"FD90167F42443623D284EA828FB13E374CBF73E16CC6755422B97640AB7FC77FDAF452B4F3A2E8472614EEE11CC8EAF48783CE2B4876A3BB72E9ACF248E86DAA5CE4D5A88E77352BCBA30A998CD8B0AD2414D43222E3BA56D82523E2073730F817695B34A4A26128D5E030A7307D3D04456DC512EBB8B53FDBD1DFC07662099B",
+	"DB531C32024A262A0DF9603E48C79E863F9539A82B8619480289EC38C3664CC63E3AC2C04888827559FFDBCB735A8D2F1D24BAF910643CE819452D95CAFFB686E6110057985E93605DE89E33B99C34140EF362117F975A5056BFF14A51C9CD16A4961BE1F02C081C7AD8B2A5450858023A157AFA3C3441E8E00941F8D33ED6B7",
+}
+
+func TestKeygenWithPrimeSource(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	N := 2
+	partyIDs := test.PartyIDs(N)
+
+	rounds := make([]round.Session, 0, N)
+	sources := make([]*paillier.PrimePool, 0, N)
+	for i, partyID := range partyIDs {
+		p, _ := new(saferith.Nat).SetHex(testPrimes[2*i])
+		q, _ := new(saferith.Nat).SetHex(testPrimes[2*i+1])
+		source := paillier.NewPrimePool(p, q)
+		sources = append(sources, source)
+		info := round.Info{
+			ProtocolID:       "cmp/keygen-test",
+			FinalRoundNumber: Rounds,
+			SelfID:           partyID,
+			PartyIDs:         partyIDs,
+			Threshold:        N - 1,
+			Group:            group,
+		}
+		r, err := StartWithPrimeSource(info, pl, nil, source)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
 	checkOutput(t, rounds)
+	for i, r := range rounds {
+		c := r.(*round.Output).Result.(*config.Config)
+		assert.NoError(t, c.Validate())
+		p, _ := new(saferith.Nat).SetHex(testPrimes[2*i])
+		q, _ := new(saferith.Nat).SetHex(testPrimes[2*i+1])
+		assert.True(t, c.Paillier.PublicKey.Equal(paillier.NewSecretKeyFromPrimes(q, p).PublicKey), "the Paillier key should come from the source")
+		assert.Zero(t, sources[i].Len())
+	}
+
+	// an exhausted source makes the keygen fail, instead of silently generating primes
+	r, err := StartWithPrimeSource(round.Info{
+		ProtocolID:       "cmp/keygen-test",
+		FinalRoundNumber: Rounds,
+		SelfID:           partyIDs[0],
+		PartyIDs:         partyIDs,
+		Threshold:        N - 1,
+		Group:            group,
+	}, pl, nil, sources[0])(nil)
+	require.NoError(t, err)
+	_, err = r.(round.Round).Finalize(make(chan *round.Message, N))
+	assert.ErrorIs(t, err, paillier.ErrPrimePoolEmpty)
 }
 
 func TestRefresh(t *testing.T) {
@@ -110,12 +217,260 @@ func TestRefresh(t *testing.T) {
 
 	}
 
+	require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
+	checkOutput(t, rounds)
+}
+
+func TestRejectInvalidElGamal(t *testing.T) {
+	partyIDs := test.PartyIDs(2)
+	helper, err := round.NewSession(round.Info{
+		ProtocolID:       "cmp/keygen-threshold",
+		FinalRoundNumber: Rounds,
+		SelfID:           partyIDs[0],
+		PartyIDs:         partyIDs,
+		Threshold:        1,
+		Group:            group,
+	}, nil, nil)
+	require.NoError(t, err)
+	r3 := &round3{round2: &round2{round1: &round1{Helper: helper}}}
+
+	s := sample.Scalar(rand.Reader, group)
+	for ElGamal, expected := range map[curve.Point]error{
+		nil:                                   round.ErrNilFields,
+		group.NewPoint():                      round.ErrNilFields,
+		test.OtherPoint{Point: s.ActOnBase()}: errors.New("curve: expected an element of secp256k1, got P-256"),
+	} {
+		err := r3.StoreBroadcastMessage(round.Message{From: partyIDs[1], Content: &broadcast3{
+			VSSPolynomial:      polynomial.NewPolynomialExponent(polynomial.NewPolynomial(group, 1, s)),
+			SchnorrCommitments: zksch.NewRandomness(rand.Reader, group, nil).Commitment(),
+			ElGamalPublic:      ElGamal,
+			N:                  []byte{35},
+			S:                  []byte{4},
+			T:                  []byte{9},
+		}})
+		assert.EqualError(t, err, expected.Error(), "invalid ElGamal key should be rejected")
+	}
+}
+
+func TestRejectLargeModulus(t *testing.T) {
+	partyIDs := test.PartyIDs(2)
+	helper, err := round.NewSession(round.Info{
+		ProtocolID:       "cmp/keygen-threshold",
+		FinalRoundNumber: Rounds,
+		SelfID:           partyIDs[0],
+		PartyIDs:         partyIDs,
+		Threshold:        1,
+		Group:            group,
+	}, nil, nil)
+	require.NoError(t, err)
+	r3 := &round3{round2: &round2{round1: &round1{Helper: helper}}}
+
+	// a 100,000-bit number, which must be rejected before it is decoded
+	large := make([]byte, 100_000/8)
+	large[0] = 0xff
+	for name, body := range map[string]*broadcast3{
+		"N": {N: large, S: []byte{4}, T: []byte{9}},
+		"S": {N: []byte{35}, S: large, T: []byte{9}},
+		"T": {N: []byte{35}, S: []byte{4}, T: large},
+	} {
+		body.VSSPolynomial = polynomial.EmptyExponent(group)
+		body.SchnorrCommitments = zksch.EmptyCommitment(group)
+		err := r3.StoreBroadcastMessage(round.Message{From: partyIDs[1], Content: body})
+		assert.ErrorIs(t, err, paillier.ErrPaillierLarge, "a large %s should be rejected", name)
+	}
+
+}
+
+func TestRejectIdentityPublicKey(t *testing.T) {
+	N := 2
+	partyIDs := test.PartyIDs(N)
+
+	// the constants of the VSS polynomials sum to 0, so that the public key is the identity
+	sum := group.NewScalar()
+	rounds := make([]round.Session, 0, N)
+	for i, partyID := range partyIDs {
+		r, err := StartSchnorrOnly(round.Info{
+			ProtocolID:       "cmp/keygen-test",
+			FinalRoundNumber: Rounds,
+			SelfID:           partyID,
+			PartyIDs:         partyIDs,
+			Threshold:        N - 1,
+			Group:            group,
+		}, nil)(nil)
+		require.NoError(t, err)
+		constant := sample.Scalar(rand.Reader, group)
+		if i == N-1 {
+			constant = group.NewScalar().Set(sum).Negate()
+		}
+		sum.Add(constant)
+		r.(*round1).VSSSecret = polynomial.NewPolynomial(group, N-1, constant)
+		rounds = append(rounds, r)
+	}
+
 	for {
 		err, done := test.Rounds(rounds, nil)
-		require.NoError(t, err, "failed to process round")
-		if done {
-			break
+		if err != nil {
+			assert.ErrorIs(t, err, config.ErrIdentityPublicKey)
+			return
 		}
+		require.False(t, done, "keygen should not succeed with an identity public key")
 	}
-	checkOutput(t, rounds)
+}
+
+// wrongDegreeRule replaces the VSS polynomial sent by a party with one of a higher degree.
+type wrongDegreeRule struct {
+	from   party.ID
+	degree int
+}
+
+func (wrongDegreeRule) ModifyBefore(round.Session) {}
+func (wrongDegreeRule) ModifyAfter(round.Session)  {}
+func (rule wrongDegreeRule) ModifyContent(rNext round.Session, _ party.ID, content round.Content) {
+	body, ok := content.(*broadcast3)
+	if !ok || rNext.SelfID() != rule.from {
+		return
+	}
+	body.VSSPolynomial = polynomial.NewPolynomialExponent(polynomial.NewPolynomial(group, rule.degree, sample.Scalar(rand.Reader, group)))
+}
+
+func TestRejectWrongDegree(t *testing.T) {
+	N := 3
+	partyIDs := test.PartyIDs(N)
+
+	rounds := make([]round.Session, 0, N)
+	for _, partyID := range partyIDs {
+		r, err := StartSchnorrOnly(round.Info{
+			ProtocolID:       "cmp/keygen-test",
+			FinalRoundNumber: Rounds,
+			SelfID:           partyID,
+			PartyIDs:         partyIDs,
+			Threshold:        1,
+			Group:            group,
+		}, nil)(nil)
+		require.NoError(t, err)
+		rounds = append(rounds, r)
+	}
+
+	rule := wrongDegreeRule{from: partyIDs[0], degree: 2}
+	for {
+		err, done := test.Rounds(rounds, rule)
+		if err != nil {
+			assert.EqualError(t, err, fmt.Sprintf("vss polynomial of party %s has degree 2 instead of 1", partyIDs[0]))
+			return
+		}
+		require.False(t, done, "a polynomial of the wrong degree should be rejected")
+	}
+}
+
+func TestStartMaxParties(t *testing.T) {
+	partyIDs := test.PartyIDs(1025)
+	_, err := Start(round.Info{
+		ProtocolID:       "cmp/keygen-threshold",
+		FinalRoundNumber: Rounds,
+		SelfID:           partyIDs[0],
+		PartyIDs:         partyIDs,
+		Threshold:        1,
+		Group:            group,
+	}, nil, nil)(nil)
+	assert.EqualError(t, err, "keygen: config: 1025 parties exceeds the maximum of 1024")
+}
+
+// BenchmarkKeygen runs a keygen between 2 parties, with the Paillier primes taken from testPrimes,
+// so that it measures the protocol rather than the prime generation, and reports the time per party.
+func BenchmarkKeygen(b *testing.B) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	N := 2
+	partyIDs := test.PartyIDs(N)
+	primes := make([]*saferith.Nat, 0, len(testPrimes))
+	for _, s := range testPrimes {
+		p, _ := new(saferith.Nat).SetHex(s)
+		primes = append(primes, p)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rounds := make([]round.Session, 0, N)
+		for j, partyID := range partyIDs {
+			info := round.Info{
+				ProtocolID:       "cmp/keygen-test",
+				FinalRoundNumber: Rounds,
+				SelfID:           partyID,
+				PartyIDs:         partyIDs,
+				Threshold:        N - 1,
+				Group:            group,
+			}
+			r, err := StartWithPrimeSource(info, pl, nil, paillier.NewPrimePool(primes[2*j], primes[2*j+1]))(nil)
+			if err != nil {
+				b.Fatal(err)
+			}
+			rounds = append(rounds, r)
+		}
+		for {
+			err, done := test.Rounds(rounds, nil)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if done {
+				break
+			}
+		}
+	}
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*N), "ns/party")
+}
+
+func TestEstimateMessageSizes(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	N := 2
+	partyIDs := test.PartyIDs(N)
+	newPrimeSource := func(i int) paillier.PrimeSource {
+		p, _ := new(saferith.Nat).SetHex(testPrimes[2*i])
+		q, _ := new(saferith.Nat).SetHex(testPrimes[2*i+1])
+		return paillier.NewPrimePool(p, q)
+	}
+	run := func(configs []*config.Config) ([]*config.Config, *test.SizeRule) {
+		rounds := make([]round.Session, 0, N)
+		for i, partyID := range partyIDs {
+			info := round.Info{
+				ProtocolID:       "cmp/keygen-test",
+				FinalRoundNumber: Rounds,
+				SelfID:           partyID,
+				PartyIDs:         partyIDs,
+				Threshold:        N - 1,
+				Group:            group,
+			}
+			var c *config.Config
+			if configs != nil {
+				c = configs[i]
+			}
+			r, err := StartWithPrimeSource(info, pl, c, newPrimeSource(i))(nil)
+			require.NoError(t, err, "round creation should not result in an error")
+			rounds = append(rounds, r)
+		}
+		rule := test.NewSizeRule(partyIDs[0])
+		require.NoError(t, test.RunRounds(rounds, rule), "failed to process round")
+		configs = make([]*config.Config, 0, N)
+		for _, r := range rounds {
+			configs = append(configs, r.(*round.Output).Result.(*config.Config))
+		}
+		return configs, rule
+	}
+	check := func(estimates []MessageSize, rule *test.SizeRule) {
+		require.Len(t, estimates, 5)
+		for _, estimate := range estimates {
+			actual := rule.Sizes[estimate.Round][estimate.Broadcast]
+			require.Len(t, actual, estimate.Count, "round %d", estimate.Round)
+			for _, size := range actual {
+				assert.InEpsilon(t, estimate.Size, size, 0.01, "round %d, broadcast %v", estimate.Round, estimate.Broadcast)
+			}
+		}
+	}
+
+	configs, rule := run(nil)
+	check(EstimateMessageSizes(group, N, N-1), rule)
+	_, rule = run(configs)
+	check(EstimateRefreshMessageSizes(group, N, N-1), rule)
 }
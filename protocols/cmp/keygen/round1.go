@@ -1,9 +1,10 @@
 package keygen
 
 import (
-	"crypto/rand"
 	"errors"
+	"io"
 
+	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/types"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
@@ -14,6 +15,7 @@ import (
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/pedersen"
 	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
 )
 
 var _ round.Round = (*round1)(nil)
@@ -42,6 +44,33 @@ type round1 struct {
 	// Keygen:  fᵢ(0) = xⁱ
 	// Refresh: fᵢ(0) = 0
 	VSSSecret *polynomial.Polynomial
+
+	// History is the history of the resulting config.
+	History []config.HistoryEntry
+
+	// KeepECDSA is set for an auxiliary refresh, where only the ElGamal, Paillier and Pedersen keys are replaced.
+	// The VSS is still performed, but the shares are discarded and sk'ᵢ, pk'ⱼ are kept as is.
+	KeepECDSA bool
+
+	// ReshareConstants[j] = λⱼ⋅X'ⱼ is the expected constant Fⱼ(0) of each VSS polynomial, when lowering the threshold.
+	// It is nil otherwise.
+	ReshareConstants map[party.ID]curve.Point
+
+	// SchnorrOnly is set when no Paillier and Pedersen parameters are generated,
+	// in which case the shares are sent unencrypted and the resulting config is config.SchnorrOnly.
+	SchnorrOnly bool
+
+	// ProveSafePrimes is set when each party proves that its Paillier primes are safe,
+	// in which case the proofs are stored in config.Public.SafePrime.
+	ProveSafePrimes bool
+
+	// PrimeSource provides the Paillier primes, if set.
+	// Otherwise, they are generated on the fly.
+	PrimeSource paillier.PrimeSource
+
+	// Rand is the source of the secrets of this party, if set by StartWithSeed.
+	// Otherwise, they are sampled from sample.Reader.
+	Rand io.Reader
 }
 
 // VerifyMessage implements round.Round.
@@ -52,8 +81,8 @@ func (r *round1) StoreMessage(round.Message) error { return nil }
 
 // Finalize implements round.Round
 //
-// - sample Paillier (pᵢ, qᵢ)
-// - sample Pedersen Nᵢ, sᵢ, tᵢ
+// - sample Paillier (pᵢ, qᵢ), unless SchnorrOnly
+// - sample Pedersen Nᵢ, sᵢ, tᵢ, unless SchnorrOnly
 // - sample aᵢ  <- 𝔽
 // - set Aᵢ = aᵢ⋅G
 // - compute Fᵢ(X) = fᵢ(X)⋅G
@@ -61,12 +90,38 @@ func (r *round1) StoreMessage(round.Message) error { return nil }
 // - sample cᵢ <- {0,1}ᵏ
 // - commit to message.
 func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	rand := r.Rand
+	if rand == nil {
+		rand = sample.Reader
+	}
+
 	// generate Paillier and Pedersen
-	PaillierSecret := paillier.NewSecretKey(nil)
-	SelfPaillierPublic := PaillierSecret.PublicKey
-	SelfPedersenPublic, PedersenSecret := PaillierSecret.GeneratePedersen()
+	var (
+		PaillierSecret *paillier.SecretKey
+		PedersenSecret *saferith.Nat
+		PaillierPublic = map[party.ID]*paillier.PublicKey{}
+		Pedersen       = map[party.ID]*pedersen.Parameters{}
+	)
+	if !r.SchnorrOnly {
+		done := r.StartPhase("paillier keygen")
+		if r.PrimeSource != nil {
+			var err error
+			if PaillierSecret, err = paillier.NewSecretKeyFromSource(r.PrimeSource); err != nil {
+				done()
+				return r, err
+			}
+		} else if r.Rand != nil {
+			// the primes must be searched for sequentially to be reproducible
+			PaillierSecret = paillier.NewSecretKeyFromPrimes(sample.Paillier(r.Rand, nil))
+		} else {
+			PaillierSecret = paillier.NewSecretKey(r.Pool)
+		}
+		PaillierPublic[r.SelfID()] = PaillierSecret.PublicKey
+		Pedersen[r.SelfID()], PedersenSecret = PaillierSecret.GeneratePedersenFromReader(rand)
+		done()
+	}
 
-	ElGamalSecret, ElGamalPublic := sample.ScalarPointPair(rand.Reader, r.Group())
+	ElGamalSecret, ElGamalPublic := sample.ScalarPointPair(rand, r.Group())
 
 	// save our own share already so we are consistent with what we receive from others
 	SelfShare := r.VSSSecret.Evaluate(r.SelfID().Scalar(r.Group()))
@@ -75,22 +130,25 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	SelfVSSPolynomial := polynomial.NewPolynomialExponent(r.VSSSecret)
 
 	// generate Schnorr randomness
-	SchnorrRand := zksch.NewRandomness(rand.Reader, r.Group(), nil)
+	SchnorrRand := zksch.NewRandomness(rand, r.Group(), nil)
 
 	// Sample RIDᵢ
-	SelfRID, err := types.NewRID(rand.Reader)
+	SelfRID, err := types.NewRID(rand)
 	if err != nil {
 		return r, errors.New("failed to sample Rho")
 	}
-	chainKey, err := types.NewRID(rand.Reader)
+	chainKey, err := types.NewRID(rand)
 	if err != nil {
 		return r, errors.New("failed to sample c")
 	}
 
 	// commit to data in message 2
-	SelfCommitment, Decommitment, err := r.HashForID(r.SelfID()).Commit(
-		SelfRID, chainKey, SelfVSSPolynomial, SchnorrRand.Commitment(), ElGamalPublic,
-		SelfPedersenPublic.N(), SelfPedersenPublic.S(), SelfPedersenPublic.T())
+	committed := []interface{}{SelfRID, chainKey, SelfVSSPolynomial, SchnorrRand.Commitment(), ElGamalPublic}
+	if !r.SchnorrOnly {
+		SelfPedersenPublic := Pedersen[r.SelfID()]
+		committed = append(committed, SelfPedersenPublic.N(), SelfPedersenPublic.S(), SelfPedersenPublic.T())
+	}
+	SelfCommitment, Decommitment, err := r.HashForID(r.SelfID()).Commit(committed...)
 	if err != nil {
 		return r, errors.New("failed to commit")
 	}
@@ -110,8 +168,8 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 		ChainKeys:      map[party.ID]types.RID{r.SelfID(): chainKey},
 		ShareReceived:  map[party.ID]curve.Scalar{r.SelfID(): SelfShare},
 		ElGamalPublic:  map[party.ID]curve.Point{r.SelfID(): ElGamalPublic},
-		PaillierPublic: map[party.ID]*paillier.PublicKey{r.SelfID(): SelfPaillierPublic},
-		Pedersen:       map[party.ID]*pedersen.Parameters{r.SelfID(): SelfPedersenPublic},
+		PaillierPublic: PaillierPublic,
+		Pedersen:       Pedersen,
 		ElGamalSecret:  ElGamalSecret,
 		PaillierSecret: PaillierSecret,
 		PedersenSecret: PedersenSecret,
@@ -34,10 +34,10 @@ type round2 struct {
 	ShareReceived map[party.ID]curve.Scalar
 
 	ElGamalPublic map[party.ID]curve.Point
-	// PaillierPublic[j] = Nⱼ
+	// PaillierPublic[j] = Nⱼ, empty if SchnorrOnly
 	PaillierPublic map[party.ID]*paillier.PublicKey
 
-	// Pedersen[j] = (Nⱼ,Sⱼ,Tⱼ)
+	// Pedersen[j] = (Nⱼ,Sⱼ,Tⱼ), empty if SchnorrOnly
 	Pedersen map[party.ID]*pedersen.Parameters
 
 	ElGamalSecret curve.Scalar
@@ -88,17 +88,19 @@ func (round2) StoreMessage(round.Message) error { return nil }
 // - send all committed data.
 func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// Send the message we created in Round1 to all
-	err := r.BroadcastMessage(out, &broadcast3{
+	msg := &broadcast3{
 		RID:                r.RIDs[r.SelfID()],
 		C:                  r.ChainKeys[r.SelfID()],
 		VSSPolynomial:      r.VSSPolynomials[r.SelfID()],
 		SchnorrCommitments: r.SchnorrRand.Commitment(),
 		ElGamalPublic:      r.ElGamalPublic[r.SelfID()],
-		N:                  r.Pedersen[r.SelfID()].N(),
-		S:                  r.Pedersen[r.SelfID()].S(),
-		T:                  r.Pedersen[r.SelfID()].T(),
 		Decommitment:       r.Decommitment,
-	})
+	}
+	if !r.SchnorrOnly {
+		ped := r.Pedersen[r.SelfID()]
+		msg.N, msg.S, msg.T = ped.N().Bytes(), ped.S().Bytes(), ped.T().Bytes()
+	}
+	err := r.BroadcastMessage(out, msg)
 	if err != nil {
 		return r, err
 	}
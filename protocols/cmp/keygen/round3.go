@@ -17,7 +17,9 @@ import (
 	zkfac "github.com/taurusgroup/multi-party-sig/pkg/zk/fac"
 	zkmod "github.com/taurusgroup/multi-party-sig/pkg/zk/mod"
 	zkprm "github.com/taurusgroup/multi-party-sig/pkg/zk/prm"
+	zksafeprime "github.com/taurusgroup/multi-party-sig/pkg/zk/safeprime"
 	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
 )
 
 var _ round.Round = (*round3)(nil)
@@ -40,11 +42,13 @@ type broadcast3 struct {
 	SchnorrCommitments *zksch.Commitment
 	ElGamalPublic      curve.Point
 	// N Paillier and Pedersen N = p•q, p ≡ q ≡ 3 mod 4
-	N *saferith.Modulus
+	// N, S and T are nil if SchnorrOnly.
+	// They are kept encoded, so that their length is checked before they are decoded.
+	N []byte
 	// S = r² mod N
-	S *saferith.Nat
+	S []byte
 	// T = Sˡ mod N
-	T *saferith.Nat
+	T []byte
 	// Decommitment = uᵢ decommitment bytes
 	Decommitment hash.Decommitment
 }
@@ -56,8 +60,8 @@ type broadcast3 struct {
 //   - if keygen, verify Fⱼ(0) != ∞
 //   - if refresh, verify Fⱼ(0) == ∞
 //
-// - validate Paillier
-// - validate Pedersen
+// - validate Paillier, or check that it is absent if SchnorrOnly
+// - validate Pedersen, or check that it is absent if SchnorrOnly
 // - validate commitments.
 // - store ridⱼ, Cⱼ, Nⱼ, Sⱼ, Tⱼ, Fⱼ(X), Aⱼ.
 func (r *round3) StoreBroadcastMessage(msg round.Message) error {
@@ -68,9 +72,40 @@ func (r *round3) StoreBroadcastMessage(msg round.Message) error {
 	}
 
 	// check nil
-	if body.N == nil || body.S == nil || body.T == nil || body.VSSPolynomial == nil || body.SchnorrCommitments == nil {
+	if body.VSSPolynomial == nil || body.SchnorrCommitments == nil {
 		return round.ErrNilFields
 	}
+	if r.SchnorrOnly {
+		if len(body.N) != 0 || len(body.S) != 0 || len(body.T) != 0 {
+			return errors.New("unexpected Paillier and Pedersen parameters")
+		}
+	} else if len(body.N) == 0 || len(body.S) == 0 || len(body.T) == 0 {
+		return round.ErrNilFields
+	}
+	// check the length of N, S and T before decoding them
+	var (
+		n    *saferith.Modulus
+		s, t *saferith.Nat
+	)
+	if !r.SchnorrOnly {
+		var err error
+		if n, err = paillier.ModulusFromBytes(body.N); err != nil {
+			return err
+		}
+		if s, err = paillier.NatFromBytes(body.S); err != nil {
+			return err
+		}
+		if t, err = paillier.NatFromBytes(body.T); err != nil {
+			return err
+		}
+	}
+	// an identity ElGamal key would make all encryptions to this party trivial
+	if body.ElGamalPublic == nil || body.ElGamalPublic.IsIdentity() {
+		return round.ErrNilFields
+	}
+	if err := curve.CheckCurve(r.Group(), body.ElGamalPublic); err != nil {
+		return err
+	}
 	// check RID length
 	if err := body.RID.Validate(); err != nil {
 		return fmt.Errorf("rid: %w", err)
@@ -85,6 +120,9 @@ func (r *round3) StoreBroadcastMessage(msg round.Message) error {
 
 	// Save all X, VSSCommitments
 	VSSPolynomial := body.VSSPolynomial
+	if err := VSSPolynomial.Validate(); err != nil {
+		return fmt.Errorf("vss polynomial of party %s: %w", from, err)
+	}
 	// check that the constant coefficient is 0
 	// if refresh then the polynomial is constant
 	if !(r.VSSSecret.Constant().IsZero() == VSSPolynomial.IsConstant) {
@@ -92,27 +130,36 @@ func (r *round3) StoreBroadcastMessage(msg round.Message) error {
 	}
 	// check deg(Fⱼ) = t
 	if VSSPolynomial.Degree() != r.Threshold() {
-		return errors.New("vss polynomial has incorrect degree")
+		return fmt.Errorf("vss polynomial of party %s has degree %d instead of %d", from, VSSPolynomial.Degree(), r.Threshold())
 	}
-
-	// Set Paillier
-	if err := paillier.ValidateN(body.N); err != nil {
-		return err
+	// if lowering the threshold, check Fⱼ(0) = λⱼ⋅X'ⱼ, so that the public key is preserved
+	if r.ReshareConstants != nil && !VSSPolynomial.Constant().Equal(r.ReshareConstants[from]) {
+		return errors.New("vss polynomial has incorrect constant")
 	}
 
-	// Verify Pedersen
-	if err := pedersen.ValidateParameters(body.N, body.S, body.T); err != nil {
-		return err
+	committed := []interface{}{body.RID, body.C, VSSPolynomial, body.SchnorrCommitments, body.ElGamalPublic}
+	if !r.SchnorrOnly {
+		// Set Paillier
+		if err := paillier.ValidateN(n); err != nil {
+			return err
+		}
+
+		// Verify Pedersen
+		if err := pedersen.ValidateParameters(n, s, t); err != nil {
+			return err
+		}
+		committed = append(committed, n, s, t)
 	}
 	// Verify decommit
-	if !r.HashForID(from).Decommit(r.Commitments[from], body.Decommitment,
-		body.RID, body.C, VSSPolynomial, body.SchnorrCommitments, body.ElGamalPublic, body.N, body.S, body.T) {
+	if !r.HashForID(from).Decommit(r.Commitments[from], body.Decommitment, committed...) {
 		return errors.New("failed to decommit")
 	}
 	r.RIDs[from] = body.RID
 	r.ChainKeys[from] = body.C
-	r.PaillierPublic[from] = paillier.NewPublicKey(body.N)
-	r.Pedersen[from] = pedersen.New(arith.ModulusFromN(body.N), body.S, body.T)
+	if !r.SchnorrOnly {
+		r.PaillierPublic[from] = paillier.NewPublicKey(n)
+		r.Pedersen[from] = pedersen.New(arith.ModulusFromN(n), s, t)
+	}
 	r.VSSPolynomials[from] = body.VSSPolynomial
 	r.SchnorrCommitments[from] = body.SchnorrCommitments
 	r.ElGamalPublic[from] = body.ElGamalPublic
@@ -134,7 +181,9 @@ func (round3) StoreMessage(round.Message) error { return nil }
 // - prove Schnorr for all coefficients of fᵢ(X)
 //   - if refresh skip constant coefficient
 //
+// - prove that the Paillier primes are safe, if ProveSafePrimes
 // - send proofs and encryption of share for Pⱼ.
+//   - if SchnorrOnly, skip the proofs and send the share unencrypted.
 func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// c = ⊕ⱼ cⱼ
 	chainKey := r.PreviousChainKey
@@ -150,11 +199,31 @@ func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 		rid.XOR(r.RIDs[j])
 	}
 
+	if r.SchnorrOnly {
+		if err := r.BroadcastMessage(out, &broadcast4{}); err != nil {
+			return r, err
+		}
+		for _, j := range r.OtherPartyIDs() {
+			// compute fᵢ(j)
+			share := r.VSSSecret.Evaluate(j.Scalar(r.Group()))
+			if err := r.SendMessage(out, &message4{PlainShare: share}, j); err != nil {
+				return r, err
+			}
+		}
+		r.UpdateHashState(rid)
+		return &round4{
+			round3:   r,
+			RID:      rid,
+			ChainKey: chainKey,
+		}, nil
+	}
+
 	// temporary hash which does not modify the state
 	h := r.Hash()
 	_ = h.WriteAny(rid, r.SelfID())
 
 	// Prove N is a blum prime with zkmod
+	done := r.StartPhase("zkmod and zkprm proofs")
 	mod := zkmod.NewProof(h.Clone(), zkmod.Private{
 		P:   r.PaillierSecret.P(),
 		Q:   r.PaillierSecret.Q(),
@@ -168,10 +237,22 @@ func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 		P:      r.PaillierSecret.P(),
 		Q:      r.PaillierSecret.Q(),
 	}, h.Clone(), zkprm.Public{Aux: r.Pedersen[r.SelfID()]}, r.Pool)
+	done()
+
+	// the safe prime proof is not bound to this session, so that it can be stored and verified later
+	var safePrime *zksafeprime.Proof
+	SafePrimeProofs := map[party.ID]*zksafeprime.Proof{}
+	if r.ProveSafePrimes {
+		done = r.StartPhase("safe prime proof")
+		safePrime = config.NewSafePrimeProof(r.SelfID(), r.PaillierSecret, r.Pool)
+		SafePrimeProofs[r.SelfID()] = safePrime
+		done()
+	}
 
 	if err := r.BroadcastMessage(out, &broadcast4{
-		Mod: mod,
-		Prm: prm,
+		Mod:       mod,
+		Prm:       prm,
+		SafePrime: safePrime,
 	}); err != nil {
 		return r, err
 	}
@@ -202,9 +283,10 @@ func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// Write rid to the hash state
 	r.UpdateHashState(rid)
 	return &round4{
-		round3:   r,
-		RID:      rid,
-		ChainKey: chainKey,
+		round3:          r,
+		RID:             rid,
+		ChainKey:        chainKey,
+		SafePrimeProofs: SafePrimeProofs,
 	}, nil
 }
 
@@ -2,6 +2,7 @@ package keygen
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/types"
@@ -12,6 +13,7 @@ import (
 	zkfac "github.com/taurusgroup/multi-party-sig/pkg/zk/fac"
 	zkmod "github.com/taurusgroup/multi-party-sig/pkg/zk/mod"
 	zkprm "github.com/taurusgroup/multi-party-sig/pkg/zk/prm"
+	zksafeprime "github.com/taurusgroup/multi-party-sig/pkg/zk/safeprime"
 	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
 )
 
@@ -25,30 +27,43 @@ type round4 struct {
 	RID types.RID
 	// ChainKey is a sequence of random bytes agreed upon together
 	ChainKey types.RID
+
+	// SafePrimeProofs[j] is the proof that the Paillier primes of Pⱼ are safe, if ProveSafePrimes
+	SafePrimeProofs map[party.ID]*zksafeprime.Proof
 }
 
 type message4 struct {
 	// Share = Encᵢ(x) is the encryption of the receivers share
 	Share *paillier.Ciphertext
 	Fac   *zkfac.Proof
+	// PlainShare = x is the receivers share, sent instead of Share and Fac if SchnorrOnly
+	PlainShare curve.Scalar
 }
 
+// broadcast4 is empty if SchnorrOnly.
 type broadcast4 struct {
 	round.NormalBroadcastContent
 	Mod *zkmod.Proof
 	Prm *zkprm.Proof
+	// SafePrime is only sent if ProveSafePrimes
+	SafePrime *zksafeprime.Proof
 }
 
 // StoreBroadcastMessage implements round.BroadcastRound.
 //
-// - verify Mod, Prm proof for N
+// - verify Mod, Prm proof for N, unless SchnorrOnly
+// - verify SafePrime proof for N, if ProveSafePrimes
 func (r *round4) StoreBroadcastMessage(msg round.Message) error {
 	from := msg.From
 	body, ok := msg.Content.(*broadcast4)
 	if !ok || body == nil {
 		return round.ErrInvalidContent
 	}
+	if r.SchnorrOnly {
+		return nil
+	}
 
+	defer r.StartPhase("zkmod and zkprm verification")()
 	// verify zkmod
 	if !body.Mod.Verify(zkmod.Public{N: r.Pedersen[from].N()}, r.HashForID(from), r.Pool) {
 		return errors.New("failed to validate mod proof")
@@ -59,12 +74,20 @@ func (r *round4) StoreBroadcastMessage(msg round.Message) error {
 		return errors.New("failed to validate prm proof")
 	}
 
+	if r.ProveSafePrimes {
+		if !config.VerifySafePrimeProof(from, r.Pedersen[from].N(), body.SafePrime, r.Pool) {
+			return errors.New("failed to validate safe prime proof")
+		}
+		r.SafePrimeProofs[from] = body.SafePrime
+	}
+
 	return nil
 }
 
 // VerifyMessage implements round.Round.
 //
 // - verify validity of share ciphertext.
+//   - if SchnorrOnly, check that the share is sent unencrypted.
 func (r *round4) VerifyMessage(msg round.Message) error {
 	from := msg.From
 	body, ok := msg.Content.(*message4)
@@ -72,6 +95,16 @@ func (r *round4) VerifyMessage(msg round.Message) error {
 		return round.ErrInvalidContent
 	}
 
+	if r.SchnorrOnly {
+		if body.PlainShare == nil || body.Share != nil || body.Fac != nil {
+			return round.ErrNilFields
+		}
+		return curve.CheckCurve(r.Group(), body.PlainShare)
+	}
+	if body.PlainShare != nil {
+		return errors.New("unexpected unencrypted share")
+	}
+
 	if !r.PaillierPublic[msg.To].ValidateCiphertexts(body.Share) {
 		return errors.New("invalid ciphertext")
 	}
@@ -93,18 +126,24 @@ func (r *round4) VerifyMessage(msg round.Message) error {
 func (r *round4) StoreMessage(msg round.Message) error {
 	from, body := msg.From, msg.Content.(*message4)
 
-	// decrypt share
-	DecryptedShare, err := r.PaillierSecret.Dec(body.Share)
-	if err != nil {
-		return err
-	}
-	Share := r.Group().NewScalar().SetNat(DecryptedShare.Mod(r.Group().Order()))
-	if DecryptedShare.Eq(curve.MakeInt(Share)) != 1 {
-		return errors.New("decrypted share is not in correct range")
+	Share := body.PlainShare
+	if !r.SchnorrOnly {
+		// decrypt share
+		DecryptedShare, err := r.PaillierSecret.Dec(body.Share)
+		if err != nil {
+			return err
+		}
+		Share = r.Group().NewScalar().SetNat(DecryptedShare.Mod(r.Group().Order()))
+		if DecryptedShare.Eq(curve.MakeInt(Share)) != 1 {
+			return errors.New("decrypted share is not in correct range")
+		}
 	}
 
 	// verify share with VSS
-	ExpectedPublicShare := r.VSSPolynomials[from].Evaluate(r.SelfID().Scalar(r.Group())) // Fⱼ(i)
+	ExpectedPublicShare, err := r.VSSPolynomials[from].EvaluateChecked(r.SelfID().Scalar(r.Group())) // Fⱼ(i)
+	if err != nil {
+		return fmt.Errorf("vss polynomial of party %s: %w", from, err)
+	}
 	PublicShare := Share.ActOnBase()
 	// X == Fⱼ(i)
 	if !PublicShare.Equal(ExpectedPublicShare) {
@@ -129,8 +168,10 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 	if r.PreviousSecretECDSA != nil {
 		UpdatedSecretECDSA.Set(r.PreviousSecretECDSA)
 	}
-	for _, j := range r.PartyIDs() {
-		UpdatedSecretECDSA.Add(r.ShareReceived[j])
+	if !r.KeepECDSA {
+		for _, j := range r.PartyIDs() {
+			UpdatedSecretECDSA.Add(r.ShareReceived[j])
+		}
 	}
 
 	// [F₁(X), …, Fₙ(X)]
@@ -145,18 +186,24 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 		return r, err
 	}
 
-	// compute the new public key share Xⱼ = F(j) (+X'ⱼ if doing a refresh)
+	// compute the new public key share Xⱼ = F(j) (+X'ⱼ if doing a refresh, or Xⱼ = X'ⱼ for an auxiliary refresh)
 	PublicData := make(map[party.ID]*config.Public, len(r.PartyIDs()))
 	for _, j := range r.PartyIDs() {
-		PublicECDSAShare := ShamirPublicPolynomial.Evaluate(j.Scalar(r.Group()))
-		if r.PreviousPublicSharesECDSA != nil {
-			PublicECDSAShare = PublicECDSAShare.Add(r.PreviousPublicSharesECDSA[j])
+		var PublicECDSAShare curve.Point
+		if r.KeepECDSA {
+			PublicECDSAShare = r.PreviousPublicSharesECDSA[j]
+		} else {
+			PublicECDSAShare = ShamirPublicPolynomial.Evaluate(j.Scalar(r.Group()))
+			if r.PreviousPublicSharesECDSA != nil {
+				PublicECDSAShare = PublicECDSAShare.Add(r.PreviousPublicSharesECDSA[j])
+			}
 		}
 		PublicData[j] = &config.Public{
-			ECDSA:    PublicECDSAShare,
-			ElGamal:  r.ElGamalPublic[j],
-			Paillier: r.PaillierPublic[j],
-			Pedersen: r.Pedersen[j],
+			ECDSA:     PublicECDSAShare,
+			ElGamal:   r.ElGamalPublic[j],
+			Paillier:  r.PaillierPublic[j],
+			Pedersen:  r.Pedersen[j],
+			SafePrime: r.SafePrimeProofs[j],
 		}
 	}
 
@@ -170,6 +217,10 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 		RID:       r.RID.Copy(),
 		ChainKey:  r.ChainKey.Copy(),
 		Public:    PublicData,
+		History:   r.History,
+	}
+	if UpdatedConfig.PublicPoint().IsIdentity() {
+		return r, config.ErrIdentityPublicKey
 	}
 
 	// write new ssid to hash, to bind the Schnorr proof to this new config
@@ -196,7 +247,12 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 func (message4) RoundNumber() round.Number { return 4 }
 
 // MessageContent implements round.Round.
-func (round4) MessageContent() round.Content { return &message4{} }
+func (r *round4) MessageContent() round.Content {
+	if r.SchnorrOnly {
+		return &message4{PlainShare: r.Group().NewScalar()}
+	}
+	return &message4{}
+}
 
 // RoundNumber implements round.Content.
 func (broadcast4) RoundNumber() round.Number { return 4 }
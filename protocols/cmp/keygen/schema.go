@@ -0,0 +1,56 @@
+package keygen
+
+import (
+	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+)
+
+// schemaSizeMargin is the factor by which a message may exceed its size given by EstimateMessageSizes.
+// The estimate is accurate up to the variable length of some integers, so this only rejects garbage.
+const schemaSizeMargin = 2
+
+// messageSchema returns the Schema of the messages received by r in the given round.
+//
+// The commitments, RIDs and points of the broadcast messages have a fixed size encoding,
+// and the size of all messages is bounded using the estimate for the mode of r.
+func (r *round1) messageSchema(number round.Number, broadcast bool) *round.Schema {
+	refresh := r.VSSSecret.Constant().IsZero()
+	sizes := estimateMessageSizes(r.Group(), r.N(), r.Threshold(), refresh, r.SchnorrOnly, r.ProveSafePrimes)
+	schema := round.SizeSchema(sizes, number, broadcast, schemaSizeMargin)
+	if schema == nil || !broadcast {
+		return schema
+	}
+	switch number {
+	case 2:
+		schema.Fields = map[string]int{"Commitment": hash.DigestLengthBytes}
+	case 3:
+		schema.Fields = map[string]int{
+			"RID":           params.SecBytes,
+			"C":             params.SecBytes,
+			"ElGamalPublic": round.PointSize(r.Group()),
+			"Decommitment":  params.SecBytes,
+		}
+	}
+	return schema
+}
+
+// MessageSchema implements round.SchemaRound.
+func (r *round2) MessageSchema(broadcast bool) *round.Schema {
+	return r.messageSchema(r.Number(), broadcast)
+}
+
+// MessageSchema implements round.SchemaRound.
+func (r *round3) MessageSchema(broadcast bool) *round.Schema {
+	return r.messageSchema(r.Number(), broadcast)
+}
+
+// MessageSchema implements round.SchemaRound.
+func (r *round4) MessageSchema(broadcast bool) *round.Schema {
+	return r.messageSchema(r.Number(), broadcast)
+}
+
+// MessageSchema implements round.SchemaRound.
+func (r *round5) MessageSchema(broadcast bool) *round.Schema {
+	return r.messageSchema(r.Number(), broadcast)
+}
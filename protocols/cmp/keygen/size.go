@@ -0,0 +1,127 @@
+package keygen
+
+import (
+	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	zksafeprime "github.com/taurusgroup/multi-party-sig/pkg/zk/safeprime"
+)
+
+// MessageSize describes the messages of a given type sent by a single party during one round.
+type MessageSize = round.MessageSize
+
+// EstimateMessageSizes returns the expected sizes of the messages sent by each party
+// during a keygen with n parties and the given threshold over group, ordered by round.
+//
+// The estimate is derived from the encoded sizes of points, scalars, Paillier ciphertexts and ZK proofs,
+// at the security level defined in internal/params.
+func EstimateMessageSizes(group curve.Curve, n, threshold int) []MessageSize {
+	return estimateMessageSizes(group, n, threshold, false, false, false)
+}
+
+// EstimateRefreshMessageSizes is the same as EstimateMessageSizes, for a refresh of a config
+// with n parties and the given threshold.
+func EstimateRefreshMessageSizes(group curve.Curve, n, threshold int) []MessageSize {
+	return estimateMessageSizes(group, n, threshold, true, false, false)
+}
+
+// estimateMessageSizes returns the sizes of the messages of a keygen, or a refresh if refresh is set.
+// The messages contain no Paillier and Pedersen parameters if schnorrOnly is set,
+// and a zksafeprime proof if safePrimes is set.
+func estimateMessageSizes(group curve.Curve, n, threshold int, refresh, schnorrOnly, safePrimes bool) []MessageSize {
+	var (
+		point      = round.CBORBytes(round.PointSize(group))
+		scalar     = round.CBORBytes((group.ScalarBits() + 7) / 8)
+		ciphertext = round.CBORBytes(params.BytesCiphertext)
+		natModN    = round.CBORBytes(params.BytesIntModN)
+		// a big.Int is encoded as a tagged byte string
+		bigModN = 1 + natModN
+		null    = 1
+	)
+	// a saferith.Int has an extra byte for its sign, and a sum may have one more bit than its terms
+	cborInt := func(bits int) int { return round.CBORBytes(1 + (bits+1+7)/8) }
+
+	// the constant coefficient of the polynomial is omitted during a refresh, since it is the identity
+	coefficients := threshold + 1
+	if refresh {
+		coefficients = threshold
+	}
+	vssPolynomial := round.CBORBytes(4 + round.CBORMap(map[string]int{
+		"IsConstant":   1,
+		"Coefficients": round.CBORArray(coefficients, point),
+	}))
+
+	modulus, pedersen, share, fac, plainShare := round.CBORBytes(params.BytesPaillier), natModN, ciphertext, 0, null
+	if schnorrOnly {
+		modulus, pedersen, share, fac, plainShare = null, null, null, null, scalar
+	} else {
+		// zkfac: P, Q, A, B, T, σ, z₁, z₂, w₁, w₂, v
+		fac = round.CBORMap(map[string]int{
+			"Comm": round.CBORMap(map[string]int{
+				"P": natModN,
+				"Q": natModN,
+				"A": natModN,
+				"B": natModN,
+				"T": natModN,
+			}),
+			"Sigma": cborInt(params.L + 2*params.BitsIntModN),
+			"Z1":    cborInt(params.LPlusEpsilon + params.BitsIntModN/2),
+			"Z2":    cborInt(params.LPlusEpsilon + params.BitsIntModN/2),
+			"W1":    cborInt(params.LPlusEpsilon + params.BitsIntModN),
+			"W2":    cborInt(params.LPlusEpsilon + params.BitsIntModN),
+			"V":     cborInt(params.LPlusEpsilon + 2*params.BitsIntModN),
+		})
+	}
+
+	broadcast4 := map[string]int{"Mod": null, "Prm": null, "SafePrime": null}
+	if !schnorrOnly {
+		// zkmod: w, (aᵢ, bᵢ, xᵢ, zᵢ)
+		broadcast4["Mod"] = round.CBORMap(map[string]int{
+			"W": bigModN,
+			"Responses": round.CBORArray(params.StatParam, round.CBORMap(map[string]int{
+				"A": 1,
+				"B": 1,
+				"X": bigModN,
+				"Z": bigModN,
+			})),
+		})
+		// zkprm: Aᵢ, zᵢ
+		broadcast4["Prm"] = round.CBORMap(map[string]int{
+			"As": round.CBORArray(params.StatParam, bigModN),
+			"Zs": round.CBORArray(params.StatParam, bigModN),
+		})
+	}
+	if safePrimes {
+		// zksafeprime: x₁, …, xₘ
+		broadcast4["SafePrime"] = round.CBORMap(map[string]int{
+			"Roots": round.CBORArray(zksafeprime.Iterations, bigModN),
+		})
+	}
+
+	return []MessageSize{
+		{Round: 2, Broadcast: true, Count: 1, Size: round.CBORMap(map[string]int{
+			"Commitment": round.CBORBytes(hash.DigestLengthBytes),
+		})},
+		{Round: 3, Broadcast: true, Count: 1, Size: round.CBORMap(map[string]int{
+			"RID":                round.CBORBytes(params.SecBytes),
+			"C":                  round.CBORBytes(params.SecBytes),
+			"VSSPolynomial":      vssPolynomial,
+			"SchnorrCommitments": round.CBORMap(map[string]int{"C": point}),
+			"ElGamalPublic":      point,
+			"N":                  modulus,
+			"S":                  pedersen,
+			"T":                  pedersen,
+			"Decommitment":       round.CBORBytes(params.SecBytes),
+		})},
+		{Round: 4, Broadcast: true, Count: 1, Size: round.CBORMap(broadcast4)},
+		{Round: 4, Count: n - 1, Size: round.CBORMap(map[string]int{
+			"Share":      share,
+			"Fac":        fac,
+			"PlainShare": plainShare,
+		})},
+		{Round: 5, Broadcast: true, Count: 1, Size: round.CBORMap(map[string]int{
+			"SchnorrResponse": round.CBORMap(map[string]int{"Z": scalar}),
+		})},
+	}
+}
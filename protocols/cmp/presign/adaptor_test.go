@@ -0,0 +1,77 @@
+package presign
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+)
+
+func TestAdaptor(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	// the counterparty of the swap holds t
+	secret := sample.Scalar(rand.Reader, group)
+	T := secret.ActOnBase()
+
+	rounds := make([]round.Session, 0, N)
+	for _, c := range configs {
+		r, err := StartPresignAdaptor(c, partyIDs, T, pl)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+	require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
+	preSignatures := make(map[party.ID]*ecdsa.AdaptorPreSignature, N)
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r)
+		preSignature, ok := r.(*round.Output).Result.(*ecdsa.AdaptorPreSignature)
+		require.True(t, ok, "result should be *ecdsa.AdaptorPreSignature")
+		require.True(t, preSignature.RT.Equal(secret.Act(preSignature.R)), "RT should be t⋅R")
+		preSignatures[r.SelfID()] = preSignature
+	}
+
+	rounds = rounds[:0]
+	for id, c := range configs {
+		r, err := StartPresignAdaptorOnline(c, preSignatures[id], messageHash, nil, pl)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+	require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
+
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r)
+		adaptorSignature, ok := r.(*round.Output).Result.(*ecdsa.AdaptorSignature)
+		require.True(t, ok, "result should be *ecdsa.AdaptorSignature")
+		publicKey := configs[r.SelfID()].PublicPoint()
+		require.True(t, adaptorSignature.Verify(publicKey, messageHash))
+
+		// (R_T, s̃) is not a valid signature before adapting it
+		unadapted := ecdsa.Signature{R: adaptorSignature.RT, S: adaptorSignature.S}
+		assert.False(t, unadapted.Verify(publicKey, messageHash))
+
+		_, err := ecdsa.Adapt(adaptorSignature, sample.Scalar(rand.Reader, group))
+		assert.Error(t, err, "adapting with the wrong secret should fail")
+
+		signature, err := ecdsa.Adapt(adaptorSignature, secret)
+		require.NoError(t, err)
+		require.True(t, signature.Verify(publicKey, messageHash))
+
+		extracted, err := ecdsa.Extract(adaptorSignature, signature)
+		require.NoError(t, err)
+		assert.True(t, extracted.Equal(secret), "the secret should be extracted from the signature")
+
+		// the secret can still be extracted from the low-s form of the signature
+		negated := &ecdsa.Signature{R: signature.R, S: group.NewScalar().Set(signature.S).Negate()}
+		extracted, err = ecdsa.Extract(adaptorSignature, negated)
+		require.NoError(t, err)
+		assert.True(t, extracted.Equal(secret))
+	}
+}
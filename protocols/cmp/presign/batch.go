@@ -0,0 +1,74 @@
+package presign
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+const protocolBatchID = "cmp/presign-batch"
+
+// StartSignBatch runs one full presign protocol for each message in the same execution,
+// so that k signatures can be obtained with the same number of messages as for a single one.
+//
+// Each signature is generated by an independent presign session, and therefore uses an independent nonce.
+// The result is a []*ecdsa.Signature, in the same order as messages.
+func StartSignBatch(c *config.Config, signers []party.ID, messages [][]byte, pl *pool.Pool) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		if c == nil {
+			return nil, errors.New("presign: config is nil")
+		}
+		if len(messages) == 0 {
+			return nil, errors.New("sign.Create: no messages")
+		}
+
+		info := round.Info{
+			ProtocolID:       protocolBatchID,
+			FinalRoundNumber: protocolFullRounds,
+			SelfID:           c.ID,
+			PartyIDs:         signers,
+			Threshold:        c.Threshold,
+			Group:            c.Group,
+		}
+		auxInfo := make([]hash.WriterToWithDomain, 0, len(messages)+1)
+		auxInfo = append(auxInfo, c)
+		for i, message := range messages {
+			// an empty message would start a presign session without the online phase
+			if len(message) == 0 {
+				return nil, fmt.Errorf("sign.Create: message %d is empty", i)
+			}
+			auxInfo = append(auxInfo, types.SigningMessage(message))
+		}
+		helper, err := round.NewSession(info, sessionID, pl, auxInfo...)
+		if err != nil {
+			return nil, fmt.Errorf("sign.Create: %w", err)
+		}
+
+		sessions := make([]round.Session, len(messages))
+		for i, message := range messages {
+			// derive a distinct session ID for each signature from the batch's SSID
+			subSessionID := binary.BigEndian.AppendUint32(append([]byte{}, helper.SSID()...), uint32(i))
+			sessions[i], err = StartPresign(c, signers, message, pl)(subSessionID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return round.NewBatch(helper, sessions, func(results []interface{}) interface{} {
+			signatures := make([]*ecdsa.Signature, len(results))
+			for i, result := range results {
+				signatures[i] = result.(*ecdsa.Signature)
+			}
+			return signatures
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package presign
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"golang.org/x/crypto/sha3"
+)
+
+func TestSignBatch(t *testing.T) {
+	messages := make([][]byte, 3)
+	for i := range messages {
+		messages[i] = make([]byte, 32)
+		sha3.ShakeSum128(messages[i], []byte{byte(i)})
+	}
+
+	rounds := make([]round.Session, 0, N)
+	for _, c := range configs {
+		pl := pool.NewPool(1)
+		defer pl.TearDown()
+		r, err := StartSignBatch(c, partyIDs, messages, pl)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
+
+	var first []*ecdsa.Signature
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r)
+		signatures, ok := r.(*round.Output).Result.([]*ecdsa.Signature)
+		require.True(t, ok, "result should be []*ecdsa.Signature")
+		require.Len(t, signatures, len(messages))
+		for i, signature := range signatures {
+			assert.True(t, signature.Verify(configs[r.SelfID()].PublicPoint(), messages[i]))
+		}
+		if first == nil {
+			first = signatures
+		}
+		for i := range signatures {
+			assert.True(t, first[i].R.Equal(signatures[i].R), "all parties should obtain the same signatures")
+		}
+	}
+
+	// each signature must use a different nonce
+	for i := range first {
+		for j := i + 1; j < len(first); j++ {
+			assert.False(t, first[i].R.Equal(first[j].R), "signatures %d and %d share a nonce", i, j)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package presign
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+// preSignatureBatch is the serialized form of a slice of PreSignatures.
+type preSignatureBatch struct {
+	PreSignatures []cbor.RawMessage
+	// Checksum is the hash of all entries, see batchChecksum.
+	Checksum []byte
+}
+
+// batchChecksum returns the hash of the serialized entries of a batch, in order.
+func batchChecksum(entries []cbor.RawMessage) []byte {
+	h := hash.New()
+	for _, entry := range entries {
+		_ = h.WriteAny(&hash.BytesWithDomain{
+			TheDomain: "PreSignature Batch Entry",
+			Bytes:     entry,
+		})
+	}
+	return h.Sum()
+}
+
+// MarshalPreSignatures serializes a batch of PreSignatures, for instance generated in advance
+// with StartPresign, so that they can be stored and loaded with UnmarshalPreSignatures.
+//
+// The result contains the secret shares kᵢ and χᵢ of each PreSignature, and must be stored as securely as the Config.
+func MarshalPreSignatures(preSignatures []*ecdsa.PreSignature) ([]byte, error) {
+	entries := make([]cbor.RawMessage, 0, len(preSignatures))
+	for i, preSignature := range preSignatures {
+		if preSignature == nil {
+			return nil, fmt.Errorf("presign: presignature %d is nil", i)
+		}
+		data, err := cbor.Marshal(preSignature)
+		if err != nil {
+			return nil, fmt.Errorf("presign: presignature %d: %w", i, err)
+		}
+		entries = append(entries, data)
+	}
+	return cbor.Marshal(&preSignatureBatch{
+		PreSignatures: entries,
+		Checksum:      batchChecksum(entries),
+	})
+}
+
+// UnmarshalPreSignatures reads a batch serialized with MarshalPreSignatures, and checks that
+// each PreSignature is well-formed and was generated by the party of c, for the public key of c.
+//
+// The checksum only detects accidental corruption of the batch, since anyone can recompute it.
+// Deliberate changes are caught by the checks against c, which verify the shares of this party and the public key.
+func UnmarshalPreSignatures(c *config.Config, data []byte) ([]*ecdsa.PreSignature, error) {
+	if c == nil {
+		return nil, errors.New("presign: config is nil")
+	}
+	var batch preSignatureBatch
+	if err := cbor.Unmarshal(data, &batch); err != nil {
+		return nil, fmt.Errorf("presign: %w", err)
+	}
+	if !bytes.Equal(batch.Checksum, batchChecksum(batch.PreSignatures)) {
+		return nil, errors.New("presign: batch checksum mismatch")
+	}
+
+	publicKey := c.PublicPoint()
+	preSignatures := make([]*ecdsa.PreSignature, 0, len(batch.PreSignatures))
+	ids := make(map[string]int, len(batch.PreSignatures))
+	for i, entry := range batch.PreSignatures {
+		preSignature := ecdsa.EmptyPreSignature(c.Group)
+		if err := cbor.Unmarshal(entry, preSignature); err != nil {
+			return nil, fmt.Errorf("presign: presignature %d: %w", i, err)
+		}
+		if err := validatePreSignature(c, publicKey, preSignature); err != nil {
+			return nil, fmt.Errorf("presign: presignature %d: %w", i, err)
+		}
+		// using the same PreSignature twice reveals the secret key
+		if j, ok := ids[string(preSignature.ID)]; ok {
+			return nil, fmt.Errorf("presign: presignature %d has the same ID as presignature %d", i, j)
+		}
+		ids[string(preSignature.ID)] = i
+		preSignatures = append(preSignatures, preSignature)
+	}
+	return preSignatures, nil
+}
+
+// validatePreSignature checks that preSignature can be used by the party of c to sign for publicKey.
+func validatePreSignature(c *config.Config, publicKey curve.Point, preSignature *ecdsa.PreSignature) error {
+	if err := preSignature.Validate(); err != nil {
+		return err
+	}
+	if err := c.CheckSigners(preSignature.SignerIDs()); err != nil {
+		return err
+	}
+
+	// R̄ᵢ = kᵢ⋅R and Sᵢ = χᵢ⋅R
+	R := preSignature.R
+	if !preSignature.KShare.Act(R).Equal(preSignature.RBar.Points[c.ID]) {
+		return errors.New("KShare does not match RBar")
+	}
+	if !preSignature.ChiShare.Act(R).Equal(preSignature.S.Points[c.ID]) {
+		return errors.New("ChiShare does not match S")
+	}
+
+	// ∑ⱼ Sⱼ = X
+	sum := c.Group.NewPoint()
+	for _, S := range preSignature.S.Points {
+		sum = sum.Add(S)
+	}
+	if !sum.Equal(publicKey) {
+		return errors.New("presignature was not generated for the public key of the config")
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package presign
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+// consistentPreSignature returns a PreSignature which passes the checks of UnmarshalPreSignatures for c,
+// without running the protocol: the shares of the other parties are random, and ∑ⱼ Sⱼ = X.
+func consistentPreSignature(t *testing.T, c *config.Config) *ecdsa.PreSignature {
+	id, err := types.NewRID(rand.Reader)
+	require.NoError(t, err)
+	R := sample.Scalar(rand.Reader, group).ActOnBase()
+	kShare, chiShare := sample.Scalar(rand.Reader, group), sample.Scalar(rand.Reader, group)
+
+	RBar := make(map[party.ID]curve.Point, len(partyIDs))
+	S := make(map[party.ID]curve.Point, len(partyIDs))
+	RBar[c.ID], S[c.ID] = kShare.Act(R), chiShare.Act(R)
+	sum := S[c.ID]
+	others := partyIDs.Remove(c.ID)
+	for i, j := range others {
+		RBar[j] = sample.Scalar(rand.Reader, group).ActOnBase()
+		if i == len(others)-1 {
+			S[j] = c.PublicPoint().Sub(sum)
+			break
+		}
+		S[j] = sample.Scalar(rand.Reader, group).ActOnBase()
+		sum = sum.Add(S[j])
+	}
+	return &ecdsa.PreSignature{
+		ID:       id,
+		R:        R,
+		RBar:     party.NewPointMap(RBar),
+		S:        party.NewPointMap(S),
+		KShare:   kShare,
+		ChiShare: chiShare,
+	}
+}
+
+func TestMarshalPreSignatures(t *testing.T) {
+	c := configs[partyIDs[0]]
+	preSignatures := make([]*ecdsa.PreSignature, 5)
+	for i := range preSignatures {
+		preSignatures[i] = consistentPreSignature(t, c)
+	}
+	data, err := MarshalPreSignatures(preSignatures)
+	require.NoError(t, err)
+
+	decoded, err := UnmarshalPreSignatures(c, data)
+	require.NoError(t, err)
+	require.Len(t, decoded, len(preSignatures))
+	for i, preSignature := range decoded {
+		assert.Equal(t, preSignatures[i].ID, preSignature.ID)
+		assert.True(t, preSignatures[i].R.Equal(preSignature.R))
+		assert.True(t, preSignatures[i].KShare.Equal(preSignature.KShare))
+		assert.True(t, preSignatures[i].ChiShare.Equal(preSignature.ChiShare))
+		assert.Equal(t, partyIDs, preSignature.SignerIDs())
+	}
+
+	_, err = UnmarshalPreSignatures(configs[partyIDs[1]], data)
+	assert.ErrorContains(t, err, "presignature 0", "another party should not accept the presignatures")
+
+	// corrupt the last byte of the third entry, which belongs to ChiShare
+	var batch preSignatureBatch
+	require.NoError(t, cbor.Unmarshal(data, &batch))
+	entry := batch.PreSignatures[2]
+	entry[len(entry)-1] ^= 1
+	corrupted, err := cbor.Marshal(&batch)
+	require.NoError(t, err)
+	_, err = UnmarshalPreSignatures(c, corrupted)
+	assert.EqualError(t, err, "presign: batch checksum mismatch")
+
+	// with an updated checksum, the entry itself must be rejected
+	batch.Checksum = batchChecksum(batch.PreSignatures)
+	corrupted, err = cbor.Marshal(&batch)
+	require.NoError(t, err)
+	_, err = UnmarshalPreSignatures(c, corrupted)
+	assert.ErrorContains(t, err, "presignature 2")
+
+	data, err = MarshalPreSignatures([]*ecdsa.PreSignature{preSignatures[0], preSignatures[1], preSignatures[0]})
+	require.NoError(t, err)
+	_, err = UnmarshalPreSignatures(c, data)
+	assert.EqualError(t, err, "presign: presignature 2 has the same ID as presignature 0")
+}
@@ -1,8 +1,6 @@
 package presign
 
 import (
-	"crypto/rand"
-
 	"github.com/taurusgroup/multi-party-sig/internal/elgamal"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/types"
@@ -44,6 +42,9 @@ type presign1 struct {
 
 	// Message is the message to be signed. If it is nil, a presignature is created.
 	Message []byte
+
+	// Adaptor = T is the adaptor point of an adaptor presignature, or nil.
+	Adaptor curve.Point
 }
 
 // VerifyMessage implements round.Round.
@@ -68,12 +69,12 @@ func (presign1) StoreMessage(round.Message) error { return nil }
 // In two rounds, we compare the hashes received and if they are different then we abort.
 func (r *presign1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// γᵢ <- 𝔽,
-	GammaShare := sample.Scalar(rand.Reader, r.Group())
+	GammaShare := sample.Scalar(sample.Reader, r.Group())
 	// Gᵢ = Encᵢ(γᵢ;νᵢ)
 	G, GNonce := r.Paillier[r.SelfID()].Enc(curve.MakeInt(GammaShare))
 
 	// kᵢ <- 𝔽,
-	KShare := sample.Scalar(rand.Reader, r.Group())
+	KShare := sample.Scalar(sample.Reader, r.Group())
 	KShareInt := curve.MakeInt(KShare)
 	// Kᵢ = Encᵢ(kᵢ;ρᵢ)
 	K, KNonce := r.Paillier[r.SelfID()].Enc(KShareInt)
@@ -81,7 +82,7 @@ func (r *presign1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// Zᵢ = (bᵢ⋅G, kᵢ⋅G+bᵢ⋅Yᵢ), bᵢ
 	ElGamalK, ElGamalNonce := elgamal.Encrypt(r.ElGamal[r.SelfID()], KShare)
 
-	presignatureID, err := types.NewRID(rand.Reader)
+	presignatureID, err := types.NewRID(sample.Reader)
 	if err != nil {
 		return r, err
 	}
@@ -172,7 +172,10 @@ func (r *presign2) Finalize(out chan<- *round.Message) (round.Session, error) {
 	msgs := make(map[party.ID]*message3, n)
 	for idx, mtaOutRaw := range mtaOuts {
 		j := otherIDs[idx]
-		m := mtaOutRaw.(mtaOut)
+		m, ok := mtaOutRaw.(mtaOut)
+		if !ok {
+			return r, mtaOutRaw.(error)
+		}
 		DeltaShareBeta[j] = m.DeltaBeta
 		DeltaCiphertext[j] = m.DeltaD
 		ChiShareBeta[j] = m.ChiBeta
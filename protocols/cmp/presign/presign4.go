@@ -48,9 +48,12 @@ func (r *presign4) StoreBroadcastMessage(msg round.Message) error {
 		return round.ErrInvalidContent
 	}
 
-	if body.DeltaShare.IsZero() || !body.ElGamalChi.Valid() {
+	if body.DeltaShare == nil || body.DeltaShare.IsZero() || !body.ElGamalChi.Valid() {
 		return round.ErrNilFields
 	}
+	if err := curve.CheckCurve(r.Group(), body.DeltaShare); err != nil {
+		return err
+	}
 	r.ElGamalChi[msg.From] = body.ElGamalChi
 	r.DeltaShares[msg.From] = body.DeltaShare
 	return nil
@@ -64,18 +67,28 @@ func (presign4) StoreMessage(round.Message) error { return nil }
 
 // Finalize implements round.Round
 //
-// - set Γᵢ = γᵢ⋅G.
+// - set Γᵢ = γᵢ⋅G, and Γᵢᵀ = γᵢ⋅T for an adaptor presignature.
 // - prove zklogstar.
 func (r *presign4) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// Γᵢ = γᵢ⋅G
-	BigGammaShare := r.Group().NewScalar().SetNat(r.GammaShare.Mod(r.Group().Order())).ActOnBase()
+	GammaShare := r.Group().NewScalar().SetNat(r.GammaShare.Mod(r.Group().Order()))
+	BigGammaShare := GammaShare.ActOnBase()
+
+	// Γᵢᵀ = γᵢ⋅T
+	var BigGammaShareAdaptor curve.Point
+	if r.Adaptor != nil {
+		BigGammaShareAdaptor = GammaShare.Act(r.Adaptor)
+	}
 
 	zkPrivate := zklogstar.Private{
 		X:   r.GammaShare,
 		Rho: r.GNonce,
 	}
 
-	if err := r.BroadcastMessage(out, &broadcast5{BigGammaShare: BigGammaShare}); err != nil {
+	if err := r.BroadcastMessage(out, &broadcast5{
+		BigGammaShare:        BigGammaShare,
+		BigGammaShareAdaptor: BigGammaShareAdaptor,
+	}); err != nil {
 		return r, err
 	}
 
@@ -90,8 +103,20 @@ func (r *presign4) Finalize(out chan<- *round.Message) (round.Session, error) {
 			Aux:    r.Pedersen[j],
 		}, zkPrivate)
 
+		var proofLogAdaptor *zklogstar.Proof
+		if r.Adaptor != nil {
+			proofLogAdaptor = zklogstar.NewProof(r.Group(), r.HashForID(r.SelfID()), zklogstar.Public{
+				C:      r.G[r.SelfID()],
+				X:      BigGammaShareAdaptor,
+				G:      r.Adaptor,
+				Prover: r.Paillier[r.SelfID()],
+				Aux:    r.Pedersen[j],
+			}, zkPrivate)
+		}
+
 		err := r.SendMessage(out, &message5{
-			ProofLog: proofLog,
+			ProofLog:        proofLog,
+			ProofLogAdaptor: proofLogAdaptor,
 		}, j)
 		if err != nil {
 			return err
@@ -106,8 +131,9 @@ func (r *presign4) Finalize(out chan<- *round.Message) (round.Session, error) {
 	}
 
 	return &presign5{
-		presign4:      r,
-		BigGammaShare: map[party.ID]curve.Point{r.SelfID(): BigGammaShare},
+		presign4:             r,
+		BigGammaShare:        map[party.ID]curve.Point{r.SelfID(): BigGammaShare},
+		BigGammaShareAdaptor: map[party.ID]curve.Point{r.SelfID(): BigGammaShareAdaptor},
 	}, nil
 }
 
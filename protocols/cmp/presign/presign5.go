@@ -17,30 +17,48 @@ type presign5 struct {
 
 	// BigGammaShare[j] = Γⱼ = [γⱼ]•G
 	BigGammaShare map[party.ID]curve.Point
+	// BigGammaShareAdaptor[j] = Γⱼᵀ = [γⱼ]•T, for an adaptor presignature
+	BigGammaShareAdaptor map[party.ID]curve.Point
 }
 
 type message5 struct {
 	ProofLog *zklogstar.Proof
+	// ProofLogAdaptor proves that Γᵢᵀ uses the same γᵢ as Γᵢ, for an adaptor presignature.
+	ProofLogAdaptor *zklogstar.Proof
 }
 
 type broadcast5 struct {
 	round.NormalBroadcastContent
 	// BigGammaShare = Γᵢ
 	BigGammaShare curve.Point
+	// BigGammaShareAdaptor = Γᵢᵀ, for an adaptor presignature
+	BigGammaShareAdaptor curve.Point
 }
 
 // StoreBroadcastMessage implements round.BroadcastRound.
 //
-// - save Γⱼ
+// - save Γⱼ, and Γⱼᵀ for an adaptor presignature
 func (r *presign5) StoreBroadcastMessage(msg round.Message) error {
 	body, ok := msg.Content.(*broadcast5)
 	if !ok || body == nil {
 		return round.ErrInvalidContent
 	}
 
-	if body.BigGammaShare.IsIdentity() {
+	if body.BigGammaShare == nil || body.BigGammaShare.IsIdentity() {
 		return round.ErrNilFields
 	}
+	if err := curve.CheckCurve(r.Group(), body.BigGammaShare); err != nil {
+		return err
+	}
+	if r.Adaptor != nil {
+		if body.BigGammaShareAdaptor == nil || body.BigGammaShareAdaptor.IsIdentity() {
+			return round.ErrNilFields
+		}
+		if err := curve.CheckCurve(r.Group(), body.BigGammaShareAdaptor); err != nil {
+			return err
+		}
+		r.BigGammaShareAdaptor[msg.From] = body.BigGammaShareAdaptor
+	}
 	r.BigGammaShare[msg.From] = body.BigGammaShare
 	return nil
 }
@@ -60,6 +78,15 @@ func (r *presign5) VerifyMessage(msg round.Message) error {
 	}) {
 		return errors.New("failed to validate log* proof for BigGammaShare")
 	}
+	if r.Adaptor != nil && !body.ProofLogAdaptor.Verify(r.HashForID(msg.From), zklogstar.Public{
+		C:      r.G[from],
+		X:      r.BigGammaShareAdaptor[from],
+		G:      r.Adaptor,
+		Prover: r.Paillier[from],
+		Aux:    r.Pedersen[to],
+	}) {
+		return errors.New("failed to validate log* proof for BigGammaShareAdaptor")
+	}
 
 	return nil
 }
@@ -69,7 +96,7 @@ func (presign5) StoreMessage(round.Message) error { return nil }
 
 // Finalize implements round.Round
 //
-// - compute Γ = ∑ⱼ Γⱼ
+// - compute Γ = ∑ⱼ Γⱼ, and Γᵀ = ∑ⱼ Γⱼᵀ for an adaptor presignature
 // - compute Δᵢ = kᵢ⋅Γ.
 func (r *presign5) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// Γ = ∑ⱼ Γⱼ
@@ -77,6 +104,18 @@ func (r *presign5) Finalize(out chan<- *round.Message) (round.Session, error) {
 	for _, GammaJ := range r.BigGammaShare {
 		Gamma = Gamma.Add(GammaJ)
 	}
+	if Gamma.IsIdentity() {
+		return r.AbortRound(errors.New("computed Γ is the identity")), nil
+	}
+
+	// Γᵀ = ∑ⱼ Γⱼᵀ
+	var GammaAdaptor curve.Point
+	if r.Adaptor != nil {
+		GammaAdaptor = r.Group().NewPoint()
+		for _, GammaJ := range r.BigGammaShareAdaptor {
+			GammaAdaptor = GammaAdaptor.Add(GammaJ)
+		}
+	}
 
 	// Δᵢ = kᵢ⋅Γ
 	BigDeltaShare := r.KShare.Act(Gamma)
@@ -103,6 +142,7 @@ func (r *presign5) Finalize(out chan<- *round.Message) (round.Session, error) {
 	return &presign6{
 		presign5:       r,
 		Gamma:          Gamma,
+		GammaAdaptor:   GammaAdaptor,
 		BigDeltaShares: map[party.ID]curve.Point{r.SelfID(): BigDeltaShare},
 	}, nil
 }
@@ -112,9 +152,13 @@ func (message5) RoundNumber() round.Number { return 5 }
 
 // MessageContent implements round.Round.
 func (r *presign5) MessageContent() round.Content {
-	return &message5{
+	content := &message5{
 		ProofLog: zklogstar.Empty(r.Group()),
 	}
+	if r.Adaptor != nil {
+		content.ProofLogAdaptor = zklogstar.Empty(r.Group())
+	}
+	return content
 }
 
 // RoundNumber implements round.Content.
@@ -122,9 +166,13 @@ func (broadcast5) RoundNumber() round.Number { return 5 }
 
 // BroadcastContent implements round.BroadcastRound.
 func (r *presign5) BroadcastContent() round.BroadcastContent {
-	return &broadcast5{
+	content := &broadcast5{
 		BigGammaShare: r.Group().NewPoint(),
 	}
+	if r.Adaptor != nil {
+		content.BigGammaShareAdaptor = r.Group().NewPoint()
+	}
+	return content
 }
 
 // Number implements round.Round.
@@ -20,6 +20,8 @@ type presign6 struct {
 
 	// Gamma = ∑ᵢ Γᵢ
 	Gamma curve.Point
+	// GammaAdaptor = ∑ᵢ Γᵢᵀ, for an adaptor presignature
+	GammaAdaptor curve.Point
 }
 
 type broadcast6 struct {
@@ -39,9 +41,12 @@ func (r *presign6) StoreBroadcastMessage(msg round.Message) error {
 		return round.ErrInvalidContent
 	}
 
-	if body.BigDeltaShare.IsIdentity() {
+	if body.BigDeltaShare == nil || body.BigDeltaShare.IsIdentity() {
 		return round.ErrNilFields
 	}
+	if err := curve.CheckCurve(r.Group(), body.BigDeltaShare); err != nil {
+		return err
+	}
 
 	if !body.Proof.Verify(r.HashForID(from), zkelog.Public{
 		E:             r.ElGamalK[from],
@@ -65,7 +70,7 @@ func (r *presign6) StoreMessage(msg round.Message) error { return nil }
 // Finalize implements round.Round
 //
 // - compute δ = ∑ⱼ δⱼ,
-// - compute R = [δ⁻¹] Γ,
+// - compute R = [δ⁻¹] Γ, and Rᵀ = [δ⁻¹] Γᵀ for an adaptor presignature,
 // - compute Sᵢ = χᵢ⋅R,
 // - compute {R̄ⱼ = δ⁻¹⋅Δⱼ}ⱼ.
 func (r *presign6) Finalize(out chan<- *round.Message) (round.Session, error) {
@@ -75,6 +80,11 @@ func (r *presign6) Finalize(out chan<- *round.Message) (round.Session, error) {
 		Delta.Add(DeltaJ)
 	}
 
+	// δ must be invertible
+	if Delta.IsZero() {
+		return r.AbortRound(errors.New("computed δ is zero")), nil
+	}
+
 	// δ⁻¹
 	DeltaInv := r.Group().NewScalar().Set(Delta).Invert()
 
@@ -116,6 +126,12 @@ func (r *presign6) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// Sᵢ = χᵢ⋅R,
 	S := r.ChiShare.Act(R)
 
+	// Rᵀ = [δ⁻¹] Γᵀ
+	var RAdaptor curve.Point
+	if r.Adaptor != nil {
+		RAdaptor = DeltaInv.Act(r.GammaAdaptor)
+	}
+
 	// {R̄ⱼ = δ⁻¹⋅Δⱼ}ⱼ
 	RBar := make(map[party.ID]curve.Point, r.N())
 	for j, BigDeltaJ := range r.BigDeltaShares {
@@ -147,6 +163,7 @@ func (r *presign6) Finalize(out chan<- *round.Message) (round.Session, error) {
 		Delta:    Delta,
 		S:        map[party.ID]curve.Point{r.SelfID(): S},
 		R:        R,
+		RAdaptor: RAdaptor,
 		RBar:     RBar,
 	}, nil
 }
@@ -25,6 +25,8 @@ type presign7 struct {
 
 	// R = [δ⁻¹] Γ
 	R curve.Point
+	// RAdaptor = Rᵀ = [δ⁻¹] Γᵀ, for an adaptor presignature
+	RAdaptor curve.Point
 
 	// RBar = {R̄ⱼ = δ⁻¹⋅Δⱼ}ⱼ
 	RBar map[party.ID]curve.Point
@@ -49,9 +51,12 @@ func (r *presign7) StoreBroadcastMessage(msg round.Message) error {
 		return round.ErrInvalidContent
 	}
 
-	if body.S.IsIdentity() {
+	if body.S == nil || body.S.IsIdentity() {
 		return round.ErrNilFields
 	}
+	if err := curve.CheckCurve(r.Group(), body.S); err != nil {
+		return err
+	}
 
 	if err := body.DecommitmentID.Validate(); err != nil {
 		return err
@@ -144,6 +149,13 @@ func (r *presign7) Finalize(out chan<- *round.Message) (round.Session, error) {
 		KShare:   r.KShare,
 		ChiShare: r.ChiShare,
 	}
+	if r.Adaptor != nil {
+		return r.ResultRound(&ecdsa.AdaptorPreSignature{
+			PreSignature: preSignature,
+			T:            r.Adaptor,
+			RT:           r.RAdaptor,
+		}), nil
+	}
 	if r.Message == nil {
 		return r.ResultRound(preSignature), nil
 	}
@@ -19,18 +19,46 @@ import (
 )
 
 const (
-	protocolOfflineID                  = "cmp/presign-offline"
-	protocolOnlineID                   = "cmp/presign-online"
-	protocolFullID                     = "cmp/presign-full"
-	protocolOfflineRounds round.Number = 7
-	protocolFullRounds    round.Number = 8
+	protocolOfflineID                     = "cmp/presign-offline"
+	protocolOnlineID                      = "cmp/presign-online"
+	protocolFullID                        = "cmp/presign-full"
+	protocolAdaptorOfflineID              = "cmp/presign-adaptor-offline"
+	protocolAdaptorOnlineID               = "cmp/presign-adaptor-online"
+	protocolOfflineRounds    round.Number = 7
+	protocolFullRounds       round.Number = 8
 )
 
 func StartPresign(c *config.Config, signers []party.ID, message []byte, pl *pool.Pool) protocol.StartFunc {
+	return startPresign(c, signers, message, nil, pl)
+}
+
+// StartPresignAdaptor creates an adaptor presignature for the adaptor point T = t⋅G.
+//
+// Signatures produced from it with StartPresignAdaptorOnline must be completed with ecdsa.Adapt and t,
+// and t can then be recovered from the completed signature with ecdsa.Extract.
+func StartPresignAdaptor(c *config.Config, signers []party.ID, T curve.Point, pl *pool.Pool) protocol.StartFunc {
 	return func(sessionID []byte) (round.Session, error) {
 		if c == nil {
 			return nil, errors.New("presign: config is nil")
 		}
+		if T == nil || T.IsIdentity() {
+			return nil, errors.New("sign.Create: adaptor point is identity")
+		}
+		if err := curve.CheckCurve(c.Group, T); err != nil {
+			return nil, fmt.Errorf("sign.Create: %w", err)
+		}
+		return startPresign(c, signers, nil, T, pl)(sessionID)
+	}
+}
+
+func startPresign(c *config.Config, signers []party.ID, message []byte, adaptor curve.Point, pl *pool.Pool) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		if c == nil {
+			return nil, errors.New("presign: config is nil")
+		}
+		if !c.HasSecret() {
+			return nil, errors.New("presign: this config has no secret share; it is public-only")
+		}
 
 		info := round.Info{
 			SelfID:    c.ID,
@@ -38,7 +66,16 @@ func StartPresign(c *config.Config, signers []party.ID, message []byte, pl *pool
 			Threshold: c.Threshold,
 			Group:     c.Group,
 		}
-		if len(message) == 0 {
+		var auxInfo []hash.WriterToWithDomain
+		if adaptor != nil {
+			info.FinalRoundNumber = protocolOfflineRounds
+			info.ProtocolID = protocolAdaptorOfflineID
+			adaptorData, err := adaptor.MarshalBinary()
+			if err != nil {
+				return nil, fmt.Errorf("sign.Create: %w", err)
+			}
+			auxInfo = append(auxInfo, hash.BytesWithDomain{TheDomain: "Adaptor Point", Bytes: adaptorData})
+		} else if len(message) == 0 {
 			info.FinalRoundNumber = protocolOfflineRounds
 			info.ProtocolID = protocolOfflineID
 		} else {
@@ -46,13 +83,21 @@ func StartPresign(c *config.Config, signers []party.ID, message []byte, pl *pool
 			info.ProtocolID = protocolFullID
 		}
 
-		helper, err := round.NewSession(info, sessionID, pl, c, types.SigningMessage(message))
+		if err := c.CheckSigners(party.NewIDSlice(signers)); err != nil {
+			return nil, fmt.Errorf("sign.Create: %w", err)
+		}
+		if err := polynomial.CheckInterpolationDomain(c.Group, signers); err != nil {
+			return nil, fmt.Errorf("sign.Create: %w", err)
+		}
+
+		auxInfo = append([]hash.WriterToWithDomain{c, types.SigningMessage(message)}, auxInfo...)
+		helper, err := round.NewSession(info, sessionID, pl, auxInfo...)
 		if err != nil {
 			return nil, fmt.Errorf("sign.Create: %w", err)
 		}
 
-		if !c.CanSign(helper.PartyIDs()) {
-			return nil, errors.New("sign.Create: signers is not a valid signing subset")
+		if c.SchnorrOnly() {
+			return nil, errors.New("presign: config has no Paillier keys, it can only be used for Schnorr signatures")
 		}
 		// Scale public data
 		T := helper.N()
@@ -87,11 +132,36 @@ func StartPresign(c *config.Config, signers []party.ID, message []byte, pl *pool
 			Paillier:       Paillier,
 			Pedersen:       Pedersen,
 			Message:        message,
+			Adaptor:        adaptor,
 		}, nil
 	}
 }
 
 func StartPresignOnline(c *config.Config, preSignature *ecdsa.PreSignature, message []byte, pl *pool.Pool) protocol.StartFunc {
+	return StartPresignOnlineWithStore(c, preSignature, message, nil, pl)
+}
+
+// StartPresignOnlineWithStore is like StartPresignOnline, but first records preSignature in store,
+// and fails if it was already used. If store is nil, no check is performed.
+func StartPresignOnlineWithStore(c *config.Config, preSignature *ecdsa.PreSignature, message []byte, store UsedStore, pl *pool.Pool) protocol.StartFunc {
+	return startPresignOnline(c, preSignature, nil, message, store, pl)
+}
+
+// StartPresignAdaptorOnline returns an *ecdsa.AdaptorSignature of message, using an adaptor presignature
+// created by StartPresignAdaptor. Like StartPresignOnlineWithStore, it first records preSignature in store if it is not nil.
+func StartPresignAdaptorOnline(c *config.Config, preSignature *ecdsa.AdaptorPreSignature, message []byte, store UsedStore, pl *pool.Pool) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		if c == nil || preSignature == nil {
+			return nil, errors.New("presign: config or preSignature is nil")
+		}
+		if err := preSignature.Validate(); err != nil {
+			return nil, fmt.Errorf("sign.Create: %w", err)
+		}
+		return startPresignOnline(c, preSignature.PreSignature, preSignature, message, store, pl)(sessionID)
+	}
+}
+
+func startPresignOnline(c *config.Config, preSignature *ecdsa.PreSignature, adaptor *ecdsa.AdaptorPreSignature, message []byte, store UsedStore, pl *pool.Pool) protocol.StartFunc {
 	return func(sessionID []byte) (round.Session, error) {
 		if c == nil || preSignature == nil {
 			return nil, errors.New("presign: config or preSignature is nil")
@@ -106,13 +176,17 @@ func StartPresignOnline(c *config.Config, preSignature *ecdsa.PreSignature, mess
 		}
 
 		signers := preSignature.SignerIDs()
+		protocolID := protocolOnlineID
+		if adaptor != nil {
+			protocolID = protocolAdaptorOnlineID
+		}
 
-		if !c.CanSign(signers) {
-			return nil, errors.New("sign.Create: signers is not a valid signing subset")
+		if err := c.CheckSigners(signers); err != nil {
+			return nil, fmt.Errorf("sign.Create: %w", err)
 		}
 
 		info := round.Info{
-			ProtocolID:       protocolOnlineID,
+			ProtocolID:       protocolID,
 			FinalRoundNumber: protocolFullRounds,
 			SelfID:           c.ID,
 			PartyIDs:         signers,
@@ -135,11 +209,19 @@ func StartPresignOnline(c *config.Config, preSignature *ecdsa.PreSignature, mess
 			return nil, fmt.Errorf("sign.Create: %w", err)
 		}
 
+		// σᵢ is revealed in the first round, so the presignature must be recorded before the session starts
+		if store != nil {
+			if err = store.MarkUsed(preSignature.ID); err != nil {
+				return nil, fmt.Errorf("sign.Create: %w", err)
+			}
+		}
+
 		return &sign1{
 			Helper:       helper,
 			PublicKey:    c.PublicPoint(),
 			Message:      message,
 			PreSignature: preSignature,
+			Adaptor:      adaptor,
 		}, nil
 	}
 }
@@ -17,6 +17,8 @@ type sign1 struct {
 	Message []byte
 	// PreSignature = (R, {R̄ⱼ,Sⱼ}ⱼ, kᵢ, χᵢ)
 	PreSignature *ecdsa.PreSignature
+	// Adaptor is the adaptor presignature wrapping PreSignature, or nil.
+	Adaptor *ecdsa.AdaptorPreSignature
 }
 
 // VerifyMessage implements round.Round.
@@ -27,7 +29,12 @@ func (r *sign1) StoreMessage(round.Message) error { return nil }
 
 func (r *sign1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// σᵢ = kᵢm+rχᵢ (mod q)
-	SigmaShare := r.PreSignature.SignatureShare(r.Message)
+	var SigmaShare curve.Scalar
+	if r.Adaptor != nil {
+		SigmaShare = r.Adaptor.SignatureShare(r.Message)
+	} else {
+		SigmaShare = r.PreSignature.SignatureShare(r.Message)
+	}
 
 	err := r.BroadcastMessage(out, &broadcastSign2{
 		Sigma: SigmaShare,
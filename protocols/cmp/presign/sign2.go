@@ -31,9 +31,12 @@ func (r *sign2) StoreBroadcastMessage(msg round.Message) error {
 		return round.ErrInvalidContent
 	}
 
-	if body.Sigma.IsZero() {
+	if body.Sigma == nil || body.Sigma.IsZero() {
 		return round.ErrNilFields
 	}
+	if err := curve.CheckCurve(r.Group(), body.Sigma); err != nil {
+		return err
+	}
 
 	r.SigmaShares[msg.From] = body.Sigma
 	return nil
@@ -47,9 +50,18 @@ func (sign2) StoreMessage(round.Message) error { return nil }
 
 // Finalize implements round.Round
 //
-// - verify (r,s)
+// - verify (r,s), or the adaptor signature (r,s̃)
 // - if not, find culprit.
 func (r *sign2) Finalize(chan<- *round.Message) (round.Session, error) {
+	if r.Adaptor != nil {
+		s := r.Adaptor.Signature(r.SigmaShares)
+		if s.Verify(r.PublicKey, r.Message) {
+			return r.ResultRound(s), nil
+		}
+		culprits := r.Adaptor.VerifySignatureShares(r.SigmaShares, r.Message)
+		return r.AbortRound(errors.New("adaptor signature failed to verify"), culprits...), nil
+	}
+
 	s := r.PreSignature.Signature(r.SigmaShares)
 
 	if s.Verify(r.PublicKey, r.Message) {
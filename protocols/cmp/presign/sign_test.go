@@ -54,13 +54,7 @@ func TestRound(t *testing.T) {
 		rounds = append(rounds, r)
 	}
 
-	for {
-		err, done := test.Rounds(rounds, nil)
-		require.NoError(t, err, "failed to process round")
-		if done {
-			break
-		}
-	}
+	require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
 	for _, r := range rounds {
 		assert.IsType(t, &round.Output{}, r)
 		signature, ok := r.(*round.Output).Result.(*ecdsa.Signature)
@@ -0,0 +1,101 @@
+package presign
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// ErrPresignatureUsed is returned when a PreSignature which was already used is given to an online signing session.
+var ErrPresignatureUsed = errors.New("presign: presignature has already been used")
+
+// UsedStore records the IDs of the PreSignatures which were used in an online signing session.
+//
+// Signing two different messages with the same PreSignature reveals the secret key,
+// so implementations must persist the IDs across restarts of the process.
+type UsedStore interface {
+	// MarkUsed records id as used, and returns ErrPresignatureUsed if it was already recorded.
+	// It must only return nil once id is durably stored.
+	MarkUsed(id []byte) error
+}
+
+// FileUsedStore is a UsedStore which appends the IDs to a file, one hex encoded ID per line.
+type FileUsedStore struct {
+	mtx  sync.Mutex
+	file *os.File
+	used map[string]struct{}
+}
+
+// NewFileUsedStore opens the store backed by the file at path, creating it if necessary,
+// and loads the IDs recorded by previous instances.
+//
+// A trailing incomplete line, left by a crash during a write, is ignored since
+// the corresponding call to MarkUsed did not succeed.
+func NewFileUsedStore(path string) (*FileUsedStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("presign: %w", err)
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("presign: %w", err)
+	}
+
+	used := make(map[string]struct{})
+	complete := data
+	if i := bytes.LastIndexByte(data, '\n'); i+1 < len(data) {
+		complete = data[:i+1]
+		// terminate the incomplete line, so that the next ID starts on its own line
+		if _, err = file.Write([]byte{'\n'}); err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("presign: %w", err)
+		}
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(complete))
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		id, err := hex.DecodeString(string(line))
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("presign: invalid entry in %s: %w", path, err)
+		}
+		used[string(id)] = struct{}{}
+	}
+	return &FileUsedStore{file: file, used: used}, nil
+}
+
+// MarkUsed implements UsedStore.
+func (s *FileUsedStore) MarkUsed(id []byte) error {
+	if len(id) == 0 {
+		return errors.New("presign: empty presignature ID")
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, ok := s.used[string(id)]; ok {
+		return ErrPresignatureUsed
+	}
+	if _, err := s.file.WriteString(hex.EncodeToString(id) + "\n"); err != nil {
+		return fmt.Errorf("presign: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("presign: %w", err)
+	}
+	s.used[string(id)] = struct{}{}
+	return nil
+}
+
+// Close closes the backing file.
+func (s *FileUsedStore) Close() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,87 @@
+package presign
+
+import (
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+func TestFileUsedStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "used")
+	id1, id2 := []byte("presignature 1"), []byte("presignature 2")
+
+	store, err := NewFileUsedStore(path)
+	require.NoError(t, err)
+	require.NoError(t, store.MarkUsed(id1))
+	assert.ErrorIs(t, store.MarkUsed(id1), ErrPresignatureUsed)
+	require.NoError(t, store.Close())
+
+	// simulate a crash in the middle of writing an entry
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString("0a1b")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// after a restart, the first ID is still rejected
+	store, err = NewFileUsedStore(path)
+	require.NoError(t, err)
+	assert.ErrorIs(t, store.MarkUsed(id1), ErrPresignatureUsed)
+	require.NoError(t, store.MarkUsed(id2))
+	require.NoError(t, store.Close())
+
+	store, err = NewFileUsedStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+	assert.ErrorIs(t, store.MarkUsed(id1), ErrPresignatureUsed)
+	assert.ErrorIs(t, store.MarkUsed(id2), ErrPresignatureUsed)
+}
+
+// randomPreSignature returns a PreSignature for signers which passes Validate,
+// but cannot produce a valid signature.
+func randomPreSignature(t *testing.T, signers party.IDSlice) *ecdsa.PreSignature {
+	id, err := types.NewRID(rand.Reader)
+	require.NoError(t, err)
+	randomPoint := func() curve.Point { return sample.Scalar(rand.Reader, group).ActOnBase() }
+	RBar := make(map[party.ID]curve.Point, len(signers))
+	S := make(map[party.ID]curve.Point, len(signers))
+	for _, j := range signers {
+		RBar[j], S[j] = randomPoint(), randomPoint()
+	}
+	return &ecdsa.PreSignature{
+		ID:       id,
+		R:        randomPoint(),
+		RBar:     party.NewPointMap(RBar),
+		S:        party.NewPointMap(S),
+		KShare:   sample.Scalar(rand.Reader, group),
+		ChiShare: sample.Scalar(rand.Reader, group),
+	}
+}
+
+func TestStartPresignOnlineWithStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "used")
+	c := configs[partyIDs[0]]
+	preSignature := randomPreSignature(t, partyIDs)
+
+	store, err := NewFileUsedStore(path)
+	require.NoError(t, err)
+	_, err = StartPresignOnlineWithStore(c, preSignature, messageHash, store, nil)(nil)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	// a new instance reading the same file must reject the presignature, even for another message
+	store, err = NewFileUsedStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+	_, err = StartPresignOnlineWithStore(c, preSignature, []byte("another message"), store, nil)(nil)
+	assert.ErrorIs(t, err, ErrPresignatureUsed)
+}
@@ -0,0 +1,79 @@
+package cmp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+// ReconstructsSecret must be passed to RecoverAndResplit, to acknowledge that it reconstructs the full ECDSA secret key.
+type ReconstructsSecret bool
+
+// IUnderstandThisReconstructsTheSecret is the only value of ReconstructsSecret accepted by RecoverAndResplit.
+const IUnderstandThisReconstructsTheSecret ReconstructsSecret = true
+
+// RecoverAndResplit is a disaster recovery procedure, which interpolates the ECDSA secret key from the Threshold+1 (or more)
+// configs in shares, and splits it again between newParties with newThreshold.
+//
+// Every new party receives fresh ElGamal, Paillier and Pedersen parameters, and the configs share a new RID.
+// The ECDSA public key and the chain key are unchanged, so that derived keys remain the same.
+//
+// This defeats the purpose of threshold signing: the secret key is held in memory by a single process,
+// which must therefore be trusted with all shares. It should only be run offline, in an isolated environment,
+// and the resulting configs must be distributed to the new parties over secure channels.
+func RecoverAndResplit(shares []*Config, newParties []party.ID, newThreshold int, acknowledge ReconstructsSecret, pl *pool.Pool) (map[party.ID]*Config, error) {
+	if acknowledge != IUnderstandThisReconstructsTheSecret {
+		return nil, errors.New("cmp.RecoverAndResplit: the reconstruction of the secret must be acknowledged")
+	}
+	if len(shares) == 0 || shares[0] == nil {
+		return nil, errors.New("cmp.RecoverAndResplit: no shares")
+	}
+	first := shares[0]
+	group := first.Group
+	publicKey := first.PublicPoint()
+
+	ids := make([]party.ID, 0, len(shares))
+	for _, c := range shares {
+		if c == nil {
+			return nil, errors.New("cmp.RecoverAndResplit: nil share")
+		}
+		if c.Group.Name() != group.Name() || c.Threshold != first.Threshold {
+			return nil, fmt.Errorf("cmp.RecoverAndResplit: share of party %s has different parameters", c.ID)
+		}
+		if !bytes.Equal(c.ChainKey, first.ChainKey) || !c.PublicPoint().Equal(publicKey) {
+			return nil, fmt.Errorf("cmp.RecoverAndResplit: share of party %s is for a different key", c.ID)
+		}
+		public, ok := c.Public[c.ID]
+		if !ok || public == nil || c.ECDSA == nil || !c.ECDSA.ActOnBase().Equal(public.ECDSA) {
+			return nil, fmt.Errorf("cmp.RecoverAndResplit: share of party %s is inconsistent with its public data", c.ID)
+		}
+		ids = append(ids, c.ID)
+	}
+	if !party.NewIDSlice(ids).Valid() {
+		return nil, errors.New("cmp.RecoverAndResplit: duplicate shares")
+	}
+	if len(ids) < first.Threshold+1 {
+		return nil, fmt.Errorf("cmp.RecoverAndResplit: got %d shares, need at least %d", len(ids), first.Threshold+1)
+	}
+
+	// x = ∑ᵢ λᵢ⋅xᵢ
+	secret := group.NewScalar()
+	lagrange := polynomial.Lagrange(group, ids)
+	for _, c := range shares {
+		secret.Add(group.NewScalar().Set(lagrange[c.ID]).Mul(c.ECDSA))
+	}
+	if !secret.ActOnBase().Equal(publicKey) {
+		return nil, errors.New("cmp.RecoverAndResplit: reconstructed secret does not match the public key")
+	}
+
+	configs, err := split(secret, first.ChainKey, newParties, newThreshold, config.NextHistory(first, config.OperationReshare, newThreshold), pl)
+	if err != nil {
+		return nil, fmt.Errorf("cmp.RecoverAndResplit: %w", err)
+	}
+	return configs, nil
+}
@@ -0,0 +1,52 @@
+// Package rid implements a protocol in which the parties of a Config agree on a new RID,
+// the random identifier which is included in the SSID of every session, without resharing any key.
+//
+// Each party commits to a random ridᵢ, and the new RID is ⊕ᵢ ridᵢ, so that it is uniform as long as
+// one party is honest. All keys, auxiliary parameters and the chain key of the Config are kept.
+package rid
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+const (
+	protocolID                  = "cmp/rotate-rid"
+	protocolRounds round.Number = 3
+)
+
+// Start returns a session in which all the parties of c agree on a new RID.
+//
+// The session is bound to the public data of c, including its current RID.
+// Returns *config.Config, a copy of c with the new RID, if successful.
+func Start(c *config.Config, pl *pool.Pool) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		if c == nil {
+			return nil, errors.New("rid: config is nil")
+		}
+		if err := c.Validate(); err != nil {
+			return nil, fmt.Errorf("rid: %w", err)
+		}
+		info := round.Info{
+			ProtocolID:       protocolID,
+			FinalRoundNumber: protocolRounds,
+			SelfID:           c.ID,
+			PartyIDs:         c.PartyIDs(),
+			Threshold:        c.Threshold,
+			Group:            c.Group,
+		}
+		helper, err := round.NewSession(info, sessionID, pl, c)
+		if err != nil {
+			return nil, fmt.Errorf("rid: %w", err)
+		}
+		return &round1{
+			Helper: helper,
+			config: c,
+		}, nil
+	}
+}
@@ -0,0 +1,84 @@
+package rid
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+func startRounds(t *testing.T, configs map[party.ID]*config.Config, partyIDs party.IDSlice, pl *pool.Pool) []round.Session {
+	rounds := make([]round.Session, 0, len(partyIDs))
+	for _, id := range partyIDs {
+		r, err := Start(configs[id], pl)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+	return rounds
+}
+
+func TestRotateRID(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+	configs, partyIDs := test.GenerateConfig(group, 3, 1, rand.Reader, pl)
+
+	rounds := startRounds(t, configs, partyIDs, pl)
+	require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
+
+	var newRID []byte
+	for i, r := range rounds {
+		require.IsType(t, &round.Output{}, r, "expected result round")
+		c := r.(*round.Output).Result.(*config.Config)
+		old := configs[partyIDs[i]]
+		require.NoError(t, c.Validate())
+
+		if newRID == nil {
+			newRID = c.RID
+		}
+		assert.Equal(t, newRID, []byte(c.RID), "all parties should obtain the same RID")
+		assert.NotEqual(t, old.RID, c.RID, "the RID should be rotated")
+		assert.Equal(t, old.ChainKey, c.ChainKey, "the chain key should not change")
+		assert.True(t, old.ECDSA.Equal(c.ECDSA), "the ECDSA share should not change")
+		assert.True(t, old.Paillier.PublicKey.Equal(c.Paillier.PublicKey), "the Paillier key should not change")
+		for _, j := range partyIDs {
+			assert.True(t, old.Public[j].ECDSA.Equal(c.Public[j].ECDSA), "the public ECDSA shares should not change")
+			assert.True(t, old.Public[j].Pedersen.N().Nat().Eq(c.Public[j].Pedersen.N().Nat()) == 1, "the Pedersen parameters should not change")
+		}
+		require.NotEmpty(t, c.History)
+		assert.Equal(t, config.OperationRotateRID, c.History[len(c.History)-1].Operation)
+	}
+}
+
+// changeRIDRule makes party "a" reveal a different ridᵢ than the one it committed to.
+type changeRIDRule struct{}
+
+func (changeRIDRule) ModifyBefore(round.Session) {}
+func (changeRIDRule) ModifyAfter(round.Session)  {}
+func (changeRIDRule) ModifyContent(rNext round.Session, _ party.ID, content round.Content) {
+	if body, ok := content.(*broadcast3); ok && rNext.SelfID() == "a" {
+		body.RID = body.RID.Copy()
+		body.RID[0] ^= 1
+	}
+}
+
+func TestRotateRIDRejectsChangedRID(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+	configs, partyIDs := test.GenerateConfig(group, 3, 1, rand.Reader, pl)
+
+	rounds := startRounds(t, configs, partyIDs, pl)
+	var err error
+	for done := false; !done && err == nil; {
+		err, done = test.Rounds(rounds, changeRIDRule{})
+	}
+	assert.Error(t, err, "a RID which does not match its commitment should be rejected")
+}
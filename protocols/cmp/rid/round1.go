@@ -0,0 +1,54 @@
+package rid
+
+import (
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+var _ round.Round = (*round1)(nil)
+
+type round1 struct {
+	*round.Helper
+
+	config *config.Config
+}
+
+// VerifyMessage implements round.Round.
+func (r *round1) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (r *round1) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - sample ridᵢ <- {0,1}ᵏ
+// - commit to ridᵢ and broadcast Vᵢ.
+func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	selfRID, err := types.NewRID(sample.Reader)
+	if err != nil {
+		return r, err
+	}
+	commitment, decommitment, err := r.HashForID(r.SelfID()).Commit(selfRID)
+	if err != nil {
+		return r, err
+	}
+	if err = r.BroadcastMessage(out, &broadcast2{Commitment: commitment}); err != nil {
+		return r, err
+	}
+	return &round2{
+		round1:       r,
+		Commitments:  map[party.ID]hash.Commitment{r.SelfID(): commitment},
+		RIDs:         map[party.ID]types.RID{r.SelfID(): selfRID},
+		Decommitment: decommitment,
+	}, nil
+}
+
+// MessageContent implements round.Round.
+func (round1) MessageContent() round.Content { return nil }
+
+// Number implements round.Round.
+func (round1) Number() round.Number { return 1 }
@@ -0,0 +1,76 @@
+package rid
+
+import (
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+var _ round.Round = (*round2)(nil)
+
+type round2 struct {
+	*round1
+
+	// Commitments[j] = Vⱼ = H(ridⱼ, uⱼ)
+	Commitments map[party.ID]hash.Commitment
+
+	// RIDs[j] = ridⱼ
+	RIDs map[party.ID]types.RID
+
+	// Decommitment = uᵢ
+	Decommitment hash.Decommitment
+}
+
+type broadcast2 struct {
+	round.ReliableBroadcastContent
+	// Commitment = Vᵢ = H(ridᵢ, uᵢ)
+	Commitment hash.Commitment
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// - save commitment Vⱼ.
+func (r *round2) StoreBroadcastMessage(msg round.Message) error {
+	body, ok := msg.Content.(*broadcast2)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if err := body.Commitment.Validate(); err != nil {
+		return err
+	}
+	r.Commitments[msg.From] = body.Commitment
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (round2) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (round2) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - reveal ridᵢ and uᵢ.
+func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
+	err := r.BroadcastMessage(out, &broadcast3{
+		RID:          r.RIDs[r.SelfID()],
+		Decommitment: r.Decommitment,
+	})
+	if err != nil {
+		return r, err
+	}
+	return &round3{round2: r}, nil
+}
+
+// MessageContent implements round.Round.
+func (round2) MessageContent() round.Content { return nil }
+
+// RoundNumber implements round.Content.
+func (broadcast2) RoundNumber() round.Number { return 2 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (round2) BroadcastContent() round.BroadcastContent { return &broadcast2{} }
+
+// Number implements round.Round.
+func (round2) Number() round.Number { return 2 }
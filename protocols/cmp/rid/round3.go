@@ -0,0 +1,80 @@
+package rid
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+)
+
+var _ round.Round = (*round3)(nil)
+
+type round3 struct {
+	*round2
+}
+
+type broadcast3 struct {
+	round.NormalBroadcastContent
+	// RID = ridᵢ
+	RID types.RID
+	// Decommitment = uᵢ
+	Decommitment hash.Decommitment
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// - verify ridⱼ and the decommitment of Vⱼ.
+// - store ridⱼ.
+func (r *round3) StoreBroadcastMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*broadcast3)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if err := body.RID.Validate(); err != nil {
+		return fmt.Errorf("rid: %w", err)
+	}
+	if err := body.Decommitment.Validate(); err != nil {
+		return err
+	}
+	if !r.HashForID(from).Decommit(r.Commitments[from], body.Decommitment, body.RID) {
+		return errors.New("failed to decommit")
+	}
+	r.RIDs[from] = body.RID
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (round3) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (round3) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - set rid = ⊕ⱼ ridⱼ and return a copy of the config with the new RID.
+func (r *round3) Finalize(chan<- *round.Message) (round.Session, error) {
+	rid := types.EmptyRID()
+	for _, j := range r.PartyIDs() {
+		rid.XOR(r.RIDs[j])
+	}
+	newConfig, err := r.config.WithRID(rid)
+	if err != nil {
+		return r, err
+	}
+	return r.ResultRound(newConfig), nil
+}
+
+// MessageContent implements round.Round.
+func (round3) MessageContent() round.Content { return nil }
+
+// RoundNumber implements round.Content.
+func (broadcast3) RoundNumber() round.Number { return 3 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (round3) BroadcastContent() round.BroadcastContent { return &broadcast3{} }
+
+// Number implements round.Round.
+func (round3) Number() round.Number { return 3 }
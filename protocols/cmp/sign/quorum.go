@@ -0,0 +1,106 @@
+package sign
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+// QuorumCertificate records which parties produced a signature.
+//
+// Each signer attests to the session, the message, the nonce R of the signature and the signer set,
+// with a Schnorr proof of knowledge of its share of the key. It can be checked with Verify,
+// using only the public data of the Config.
+type QuorumCertificate struct {
+	// SSID is the hash of the session's parameters, to which the attestations are bound.
+	SSID []byte
+	// Signers are the parties which took part in the session.
+	Signers party.IDSlice
+	// Attestations[j] is a Schnorr proof of knowledge of λⱼ⋅xⱼ for λⱼ⋅Xⱼ, where λⱼ is the
+	// Lagrange coefficient of j for Signers.
+	Attestations map[party.ID]*zksch.Proof
+}
+
+// AccountableSignature is the result of StartSignAccountable.
+type AccountableSignature struct {
+	Signature   *ecdsa.Signature
+	Certificate *QuorumCertificate
+}
+
+// StartSignAccountable is the same as StartSign, but every signer also attests to its participation,
+// so that the result is an *AccountableSignature, whose certificate names the parties which signed.
+//
+// All signers must use StartSignAccountable, otherwise the session is aborted.
+func StartSignAccountable(config *config.Config, signers []party.ID, message []byte, pl *pool.Pool) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		r, err := StartSign(config, signers, message, pl)(sessionID)
+		if err != nil {
+			return nil, err
+		}
+		r.(*round1).Accountable = true
+		return r, nil
+	}
+}
+
+// attestationHash returns the hash to which the attestation of id is bound.
+func attestationHash(ssid []byte, m curve.Scalar, bigR curve.Point, signers party.IDSlice, id party.ID) *hash.Hash {
+	h := hash.New(&hash.BytesWithDomain{TheDomain: "CMP Quorum Attestation", Bytes: ssid}, signers, id)
+	_ = h.WriteAny(m, bigR)
+	return h
+}
+
+// Verify checks that sig is a valid signature of message for the key of public, the Public map of a Config,
+// and that every party in c.Signers attested to the session which produced sig.
+//
+// It returns an error if a signer has no valid attestation, if an attestation is present for a party
+// which is not a signer, or if the signers can't sign for the key of public.
+func (c *QuorumCertificate) Verify(public map[party.ID]*config.Public, sig *ecdsa.Signature, message []byte) error {
+	if c == nil || sig == nil || sig.R == nil || sig.S == nil {
+		return errors.New("sign: missing certificate or signature")
+	}
+	if len(c.Signers) == 0 || !c.Signers.Valid() {
+		return errors.New("sign: certificate signers must be sorted and unique")
+	}
+	for j := range c.Attestations {
+		if !c.Signers.Contains(j) {
+			return fmt.Errorf("sign: certificate has an attestation for %s, which is not a signer", j)
+		}
+	}
+	group := sig.R.Curve()
+	lagrange := polynomial.Lagrange(group, c.Signers)
+	shares := make(map[party.ID]curve.Point, len(c.Signers))
+	publicKey := group.NewPoint()
+	for _, j := range c.Signers {
+		p, ok := public[j]
+		if !ok || p == nil || p.ECDSA == nil {
+			return fmt.Errorf("sign: signer %s is not a party of the config", j)
+		}
+		shares[j] = lagrange[j].Act(p.ECDSA)
+		publicKey = publicKey.Add(shares[j])
+	}
+	if !sig.Verify(publicKey, message) {
+		return errors.New("sign: signature is not valid for the key of the signers")
+	}
+
+	m := curve.MessageToScalar(group, message)
+	for _, j := range c.Signers {
+		attestation, ok := c.Attestations[j]
+		if !ok || attestation == nil {
+			return fmt.Errorf("sign: signer %s did not attest", j)
+		}
+		if !attestation.Verify(attestationHash(c.SSID, m, sig.R, c.Signers, j), shares[j], nil) {
+			return fmt.Errorf("sign: invalid attestation for %s", j)
+		}
+	}
+	return nil
+}
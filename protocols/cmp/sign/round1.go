@@ -1,8 +1,6 @@
 package sign
 
 import (
-	"crypto/rand"
-
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -26,6 +24,19 @@ type round1 struct {
 	ECDSA          map[party.ID]curve.Point
 
 	Message []byte
+	// MessageScalar = m is the message reduced to a scalar, which is signed.
+	MessageScalar curve.Scalar
+	// Context is bound to the session if it was started with StartSignWithContext, and nil otherwise
+	Context []byte
+
+	// EncBackend creates and verifies the proofs for Kᵢ
+	EncBackend zkenc.Backend
+
+	// LowS is true if the signature must be normalized to s ≤ n/2 before it is returned
+	LowS bool
+
+	// Accountable is true if the signers attest to their participation, see StartSignAccountable
+	Accountable bool
 }
 
 // VerifyMessage implements round.Round.
@@ -51,23 +62,26 @@ func (round1) StoreMessage(round.Message) error { return nil }
 func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// γᵢ <- 𝔽,
 	// Γᵢ = [γᵢ]⋅G
-	GammaShare, BigGammaShare := sample.ScalarPointPair(rand.Reader, r.Group())
+	GammaShare, BigGammaShare := sample.ScalarPointPair(sample.Reader, r.Group())
+	done := r.StartPhase("paillier encryption")
 	// Gᵢ = Encᵢ(γᵢ;νᵢ)
 	G, GNonce := r.Paillier[r.SelfID()].Enc(curve.MakeInt(GammaShare))
 
 	// kᵢ <- 𝔽,
-	KShare := sample.Scalar(rand.Reader, r.Group())
+	KShare := sample.Scalar(sample.Reader, r.Group())
 	// Kᵢ = Encᵢ(kᵢ;ρᵢ)
 	K, KNonce := r.Paillier[r.SelfID()].Enc(curve.MakeInt(KShare))
+	done()
 
 	otherIDs := r.OtherPartyIDs()
 	broadcastMsg := broadcast2{K: K, G: G}
 	if err := r.BroadcastMessage(out, &broadcastMsg); err != nil {
 		return r, err
 	}
+	done = r.StartPhase("zkenc proofs")
 	errors := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
 		j := otherIDs[i]
-		proof := zkenc.NewProof(r.Group(), r.HashForID(r.SelfID()), zkenc.Public{
+		proof := r.EncBackend.NewProof(r.Group(), r.HashForID(r.SelfID()), zkenc.Public{
 			K:      K,
 			Prover: r.Paillier[r.SelfID()],
 			Aux:    r.Pedersen[j],
@@ -84,6 +98,7 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 		}
 		return nil
 	})
+	done()
 	for _, err := range errors {
 		if err != nil {
 			return r, err.(error)
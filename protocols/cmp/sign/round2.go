@@ -85,11 +85,12 @@ func (r *round2) VerifyMessage(msg round.Message) error {
 		return round.ErrNilFields
 	}
 
-	if !body.ProofEnc.Verify(r.Group(), r.HashForID(from), zkenc.Public{
+	defer r.StartPhase("zkenc verification")()
+	if !r.EncBackend.Verify(r.Group(), r.HashForID(from), zkenc.Public{
 		K:      r.K[from],
 		Prover: r.Paillier[from],
 		Aux:    r.Pedersen[to],
-	}) {
+	}, body.ProofEnc) {
 		return errors.New("failed to validate enc proof for K")
 	}
 	return nil
@@ -116,6 +117,7 @@ func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
 		DeltaBeta *saferith.Int
 		ChiBeta   *saferith.Int
 	}
+	done := r.StartPhase("mta")
 	mtaOuts := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
 		j := otherIDs[i]
 
@@ -152,11 +154,15 @@ func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
 			ChiBeta:   ChiBeta,
 		}
 	})
+	done()
 	DeltaShareBetas := make(map[party.ID]*saferith.Int, len(otherIDs)-1)
 	ChiShareBetas := make(map[party.ID]*saferith.Int, len(otherIDs)-1)
 	for idx, mtaOutRaw := range mtaOuts {
 		j := otherIDs[idx]
-		m := mtaOutRaw.(mtaOut)
+		m, ok := mtaOutRaw.(mtaOut)
+		if !ok {
+			return r, mtaOutRaw.(error)
+		}
 		if m.err != nil {
 			return r, m.err
 		}
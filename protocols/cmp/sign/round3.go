@@ -51,9 +51,12 @@ func (r *round3) StoreBroadcastMessage(msg round.Message) error {
 	if !ok || body == nil {
 		return round.ErrInvalidContent
 	}
-	if body.BigGammaShare.IsIdentity() {
+	if body.BigGammaShare == nil || body.BigGammaShare.IsIdentity() {
 		return round.ErrNilFields
 	}
+	if err := curve.CheckCurve(r.Group(), body.BigGammaShare); err != nil {
+		return err
+	}
 	r.BigGammaShare[msg.From] = body.BigGammaShare
 	return nil
 }
@@ -68,6 +71,7 @@ func (r *round3) VerifyMessage(msg round.Message) error {
 		return round.ErrInvalidContent
 	}
 
+	defer r.StartPhase("mta verification")()
 	if !body.DeltaProof.Verify(r.HashForID(from), zkaffg.Public{
 		Kv:       r.K[to],
 		Dv:       body.DeltaD,
@@ -140,6 +144,9 @@ func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 	for _, BigGammaShare := range r.BigGammaShare {
 		Gamma = Gamma.Add(BigGammaShare)
 	}
+	if Gamma.IsIdentity() {
+		return r.AbortRound(errors.New("computed Γ is the identity")), nil
+	}
 
 	// Δᵢ = [kᵢ]Γ
 	KShareInt := curve.MakeInt(r.KShare)
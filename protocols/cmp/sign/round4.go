@@ -7,6 +7,7 @@ import (
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	zklogstar "github.com/taurusgroup/multi-party-sig/pkg/zk/logstar"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
 )
 
 var _ round.Round = (*round4)(nil)
@@ -46,9 +47,12 @@ func (r *round4) StoreBroadcastMessage(msg round.Message) error {
 	if !ok || body == nil {
 		return round.ErrInvalidContent
 	}
-	if body.DeltaShare.IsZero() || body.BigDeltaShare.IsIdentity() {
+	if body.DeltaShare == nil || body.BigDeltaShare == nil || body.DeltaShare.IsZero() || body.BigDeltaShare.IsIdentity() {
 		return round.ErrNilFields
 	}
+	if err := curve.CheckCurve(r.Group(), body.DeltaShare, body.BigDeltaShare); err != nil {
+		return err
+	}
 	r.BigDeltaShares[msg.From] = body.BigDeltaShare
 	r.DeltaShares[msg.From] = body.DeltaShare
 	return nil
@@ -88,7 +92,7 @@ func (round4) StoreMessage(round.Message) error {
 // - set δ = ∑ⱼ δⱼ
 // - set Δ = ∑ⱼ Δⱼ
 // - verify Δ = [δ]G
-// - compute σᵢ = rχᵢ + kᵢm.
+// - compute σᵢ = rχᵢ + kᵢm and Sᵢ = χᵢ⋅R.
 func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// δ = ∑ⱼ δⱼ
 	// Δ = ∑ⱼ Δⱼ
@@ -99,6 +103,11 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 		BigDelta = BigDelta.Add(r.BigDeltaShares[j])
 	}
 
+	// δ must be invertible
+	if Delta.IsZero() {
+		return r.AbortRound(errors.New("computed δ is zero")), nil
+	}
+
 	// Δ == [δ]G
 	deltaComputed := Delta.ActOnBase()
 	if !deltaComputed.Equal(BigDelta) {
@@ -110,24 +119,36 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 	R := BigR.XScalar()                                   // r = R|ₓ
 
 	// km = Hash(m)⋅kᵢ
-	km := curve.FromHash(r.Group(), r.Message)
+	km := r.Group().NewScalar().Set(r.MessageScalar)
 	km.Mul(r.KShare)
 
 	// σᵢ = rχᵢ + kᵢm
 	SigmaShare := r.Group().NewScalar().Set(R).Mul(r.ChiShare).Add(km)
 
+	// Sᵢ = χᵢ⋅R
+	ChiShareR := r.ChiShare.Act(BigR)
+
+	// πᵢ = zksch(λᵢ⋅xᵢ), bound to the session, m, R and the signers
+	var attestation *zksch.Proof
+	if r.Accountable {
+		h := attestationHash(r.SSID(), r.MessageScalar, BigR, r.PartyIDs(), r.SelfID())
+		attestation = zksch.NewProof(h, r.ECDSA[r.SelfID()], r.SecretECDSA, nil)
+	}
+
 	// Send to all
-	err := r.BroadcastMessage(out, &broadcast5{SigmaShare: SigmaShare})
+	err := r.BroadcastMessage(out, &broadcast5{SigmaShare: SigmaShare, ChiShareR: ChiShareR, Attestation: attestation})
 	if err != nil {
 		return r, err
 	}
 	return &round5{
-		round4:      r,
-		SigmaShares: map[party.ID]curve.Scalar{r.SelfID(): SigmaShare},
-		Delta:       Delta,
-		BigDelta:    BigDelta,
-		BigR:        BigR,
-		R:           R,
+		round4:       r,
+		SigmaShares:  map[party.ID]curve.Scalar{r.SelfID(): SigmaShare},
+		ChiShareR:    map[party.ID]curve.Point{r.SelfID(): ChiShareR},
+		Delta:        Delta,
+		BigDelta:     BigDelta,
+		BigR:         BigR,
+		R:            R,
+		Attestations: map[party.ID]*zksch.Proof{r.SelfID(): attestation},
 	}, nil
 }
 
@@ -7,6 +7,7 @@ import (
 	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
 )
 
 var _ round.Round = (*round5)(nil)
@@ -17,6 +18,9 @@ type round5 struct {
 	// SigmaShares[j] = σⱼ = m⋅kⱼ + χⱼ⋅R|ₓ
 	SigmaShares map[party.ID]curve.Scalar
 
+	// ChiShareR[j] = Sⱼ = χⱼ⋅R
+	ChiShareR map[party.ID]curve.Point
+
 	// Delta = δ = ∑ⱼ δⱼ
 	// computed from received shares
 	Delta curve.Scalar
@@ -29,27 +33,51 @@ type round5 struct {
 
 	// R = R|ₓ
 	R curve.Scalar
+
+	// Attestations[j] = πⱼ, if Accountable
+	Attestations map[party.ID]*zksch.Proof
 }
 
 type broadcast5 struct {
 	round.NormalBroadcastContent
 	SigmaShare curve.Scalar
+	// ChiShareR = Sⱼ = χⱼ⋅R, used to identify the party whose σⱼ is invalid
+	ChiShareR curve.Point
+	// Attestation = πᵢ, the attestation of participation, if Accountable
+	Attestation *zksch.Proof `cbor:",omitempty"`
 }
 
+// ErrInvalidSignature is returned, as the error of a protocol.Error listing the culprits,
+// when the combined signature does not verify.
+var ErrInvalidSignature = errors.New("failed to validate signature")
+
 // StoreBroadcastMessage implements round.BroadcastRound.
 //
-// - save σⱼ
+// - verify the attestation πⱼ if Accountable
+// - save σⱼ, Sⱼ
 func (r *round5) StoreBroadcastMessage(msg round.Message) error {
 	body, ok := msg.Content.(*broadcast5)
 	if !ok || body == nil {
 		return round.ErrInvalidContent
 	}
 
-	if body.SigmaShare.IsZero() {
+	if body.SigmaShare == nil || body.SigmaShare.IsZero() || body.ChiShareR == nil || body.ChiShareR.IsIdentity() {
 		return round.ErrNilFields
 	}
+	if err := curve.CheckCurve(r.Group(), body.SigmaShare, body.ChiShareR); err != nil {
+		return err
+	}
+
+	if r.Accountable {
+		h := attestationHash(r.SSID(), r.MessageScalar, r.BigR, r.PartyIDs(), msg.From)
+		if body.Attestation == nil || !body.Attestation.Verify(h, r.ECDSA[msg.From], nil) {
+			return errors.New("invalid attestation")
+		}
+		r.Attestations[msg.From] = body.Attestation
+	}
 
 	r.SigmaShares[msg.From] = body.SigmaShare
+	r.ChiShareR[msg.From] = body.ChiShareR
 	return nil
 }
 
@@ -62,7 +90,8 @@ func (round5) StoreMessage(round.Message) error { return nil }
 // Finalize implements round.Round
 //
 // - compute σ = ∑ⱼ σⱼ
-// - verify signature.
+// - verify signature, and normalize it to s ≤ n/2 if LowS is set
+// - if it is invalid, blame the parties for which σⱼ⋅R ≠ m⋅kⱼ⋅R + r⋅Sⱼ.
 func (r *round5) Finalize(chan<- *round.Message) (round.Session, error) {
 	// compute σ = ∑ⱼ σⱼ
 	Sigma := r.Group().NewScalar()
@@ -75,13 +104,57 @@ func (r *round5) Finalize(chan<- *round.Message) (round.Session, error) {
 		S: Sigma,
 	}
 
-	if !signature.Verify(r.PublicKey, r.Message) {
-		return r.AbortRound(errors.New("failed to validate signature")), nil
+	if !signature.VerifyScalar(r.PublicKey, r.MessageScalar) {
+		return r.AbortRound(ErrInvalidSignature, r.invalidSigmaShares()...), nil
+	}
+	if r.LowS {
+		signature = signature.Normalize()
 	}
 
+	if r.Accountable {
+		return r.ResultRound(&AccountableSignature{
+			Signature: signature,
+			Certificate: &QuorumCertificate{
+				SSID:         r.SSID(),
+				Signers:      r.PartyIDs().Copy(),
+				Attestations: r.Attestations,
+			},
+		}), nil
+	}
+	if r.Context != nil {
+		return r.ResultRound(&ContextSignature{
+			Signature: signature,
+			Context:   r.Context,
+			SSID:      r.SSID(),
+		}), nil
+	}
 	return r.ResultRound(signature), nil
 }
 
+// invalidSigmaShares returns the parties whose σⱼ is inconsistent with Δⱼ and Sⱼ.
+//
+// Since δ⁻¹⋅Δⱼ = kⱼ⋅R, and Δⱼ was proven to be consistent with Kⱼ in round 4,
+// a valid share satisfies σⱼ⋅R = m⋅δ⁻¹⋅Δⱼ + r⋅Sⱼ.
+// Sⱼ is not proven, so a party which sends an Sⱼ consistent with an invalid σⱼ is not identified.
+func (r *round5) invalidSigmaShares() []party.ID {
+	m := r.MessageScalar
+	deltaInv := r.Group().NewScalar().Set(r.Delta).Invert()
+	var culprits []party.ID
+	for _, j := range r.PartyIDs() {
+		SigmaShare, BigDeltaShare, ChiShareR := r.SigmaShares[j], r.BigDeltaShares[j], r.ChiShareR[j]
+		if SigmaShare == nil || BigDeltaShare == nil || ChiShareR == nil {
+			culprits = append(culprits, j)
+			continue
+		}
+		lhs := SigmaShare.Act(r.BigR)
+		rhs := m.Act(deltaInv.Act(BigDeltaShare)).Add(r.R.Act(ChiShareR))
+		if !lhs.Equal(rhs) {
+			culprits = append(culprits, j)
+		}
+	}
+	return culprits
+}
+
 // MessageContent implements round.Round.
 func (r *round5) MessageContent() round.Content { return nil }
 
@@ -90,9 +163,14 @@ func (broadcast5) RoundNumber() round.Number { return 5 }
 
 // BroadcastContent implements round.BroadcastRound.
 func (r *round5) BroadcastContent() round.BroadcastContent {
-	return &broadcast5{
+	content := &broadcast5{
 		SigmaShare: r.Group().NewScalar(),
+		ChiShareR:  r.Group().NewPoint(),
+	}
+	if r.Accountable {
+		content.Attestation = zksch.EmptyProof(r.Group())
 	}
+	return content
 }
 
 // Number implements round.Round.
@@ -0,0 +1,52 @@
+package sign
+
+import (
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// schemaSizeMargin is the factor by which a message may exceed its size given by EstimateMessageSizes.
+// The estimate is accurate up to the variable length of some integers, so this only rejects garbage.
+const schemaSizeMargin = 2
+
+// messageSchema returns the Schema of the messages received in round number by a session with n signers.
+//
+// The points and scalars of the broadcast messages have a fixed size encoding,
+// and the size of all messages is bounded using EstimateMessageSizes, or EstimateAccountableMessageSizes.
+func messageSchema(group curve.Curve, n int, number round.Number, broadcast, accountable bool) *round.Schema {
+	schema := round.SizeSchema(estimateMessageSizes(group, n, accountable), number, broadcast, schemaSizeMargin)
+	if schema == nil || !broadcast {
+		return schema
+	}
+	point := round.PointSize(group)
+	scalar := (group.ScalarBits() + 7) / 8
+	switch number {
+	case 3:
+		schema.Fields = map[string]int{"BigGammaShare": point}
+	case 4:
+		schema.Fields = map[string]int{"DeltaShare": scalar, "BigDeltaShare": point}
+	case 5:
+		schema.Fields = map[string]int{"SigmaShare": scalar, "ChiShareR": point}
+	}
+	return schema
+}
+
+// MessageSchema implements round.SchemaRound.
+func (r *round2) MessageSchema(broadcast bool) *round.Schema {
+	return messageSchema(r.Group(), r.N(), r.Number(), broadcast, r.Accountable)
+}
+
+// MessageSchema implements round.SchemaRound.
+func (r *round3) MessageSchema(broadcast bool) *round.Schema {
+	return messageSchema(r.Group(), r.N(), r.Number(), broadcast, r.Accountable)
+}
+
+// MessageSchema implements round.SchemaRound.
+func (r *round4) MessageSchema(broadcast bool) *round.Schema {
+	return messageSchema(r.Group(), r.N(), r.Number(), broadcast, r.Accountable)
+}
+
+// MessageSchema implements round.SchemaRound.
+func (r *round5) MessageSchema(broadcast bool) *round.Schema {
+	return messageSchema(r.Group(), r.N(), r.Number(), broadcast, r.Accountable)
+}
@@ -0,0 +1,53 @@
+package sign
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+// SighashAll is the Bitcoin sighash type which commits to all inputs and outputs of a transaction.
+const SighashAll uint32 = 0x01
+
+// SighashInput is a message which is signed together with a Bitcoin sighash type.
+//
+// The sighash type is encoded separately from the message, but must be committed to by the signature,
+// so the signers agree on both, and hash them in the order expected by the network.
+type SighashInput struct {
+	// Message is the serialization of the transaction, as prescribed by SighashType,
+	// with the script of the input being signed in place of its signature script.
+	// For a segwit v0 input, it is the preimage defined in BIP-143, without its trailing sighash type.
+	Message []byte
+	// SighashType is appended to Message as a 4 byte little endian integer.
+	SighashType uint32
+}
+
+// Digest returns the Bitcoin signature hash SHA-256(SHA-256(Message ‖ LE32(SighashType))),
+// which is shared by legacy and BIP-143 inputs. Taproot inputs are signed with Schnorr signatures instead.
+func (in *SighashInput) Digest() []byte {
+	h := sha256.New()
+	_, _ = h.Write(in.Message)
+	_, _ = h.Write(binary.LittleEndian.AppendUint32(nil, in.SighashType))
+	first := h.Sum(nil)
+	digest := sha256.Sum256(first)
+	return digest[:]
+}
+
+// StartSignSighash is the same as StartSignLowS, but signs the Digest of input, as required by Bitcoin.
+//
+// The resulting *ecdsa.Signature verifies against input.Digest(). In a Bitcoin script, its DER encoding
+// must be followed by the low byte of input.SighashType.
+func StartSignSighash(config *config.Config, signers []party.ID, input *SighashInput, pl *pool.Pool) protocol.StartFunc {
+	if input == nil || len(input.Message) == 0 {
+		return func([]byte) (round.Session, error) {
+			return nil, errors.New("sign.Create: sighash message is nil")
+		}
+	}
+	return StartSignLowS(config, signers, input.Digest(), pl)
+}
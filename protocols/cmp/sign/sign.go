@@ -6,6 +6,8 @@ import (
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
 	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
@@ -13,6 +15,7 @@ import (
 	"github.com/taurusgroup/multi-party-sig/pkg/pedersen"
 	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	zkenc "github.com/taurusgroup/multi-party-sig/pkg/zk/enc"
 	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
 )
 
@@ -23,11 +26,87 @@ const (
 )
 
 func StartSign(config *config.Config, signers []party.ID, message []byte, pl *pool.Pool) protocol.StartFunc {
+	return StartSignWithBackend(config, signers, message, pl, nil)
+}
+
+// StartSignWithBackend is the same as StartSign, but uses encBackend to create and verify
+// the proofs of correct encryption of Kᵢ. If encBackend is nil, zkenc.DefaultBackend is used.
+func StartSignWithBackend(config *config.Config, signers []party.ID, message []byte, pl *pool.Pool, encBackend zkenc.Backend) protocol.StartFunc {
+	return startSign(config, signers, message, nil, nil, pl, encBackend)
+}
+
+// StartSignScalar is the same as StartSign, but signs m directly, instead of the reduction of a message hash.
+//
+// This is meant for callers which compute the reduced message themselves, for instance in adaptor or blind signature schemes.
+// No hashing is performed, so the caller is responsible for deriving m correctly:
+// signing an m which is not bound to a hash of the message allows for existential forgeries.
+// The result is an *ecdsa.Signature, which can be verified with Signature.VerifyScalar.
+//
+// m must not be zero: for any scalar u, anyone can forge the signature (r, s) = (x(u⋅X), r⋅u⁻¹) of 0
+// without the key, so it proves nothing and is rejected.
+func StartSignScalar(config *config.Config, signers []party.ID, m curve.Scalar, pl *pool.Pool) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		if m == nil || m.IsZero() {
+			return nil, errors.New("sign.Create: message scalar is zero")
+		}
+		if err := curve.CheckCurve(config.Group, m); err != nil {
+			return nil, fmt.Errorf("sign.Create: %w", err)
+		}
+		return startSign(config, signers, nil, m, nil, pl, nil)(sessionID)
+	}
+}
+
+// StartSignLowS is the same as StartSign, but the resulting signature is normalized with ecdsa.Signature.Normalize,
+// so that s ≤ n/2, as required by Bitcoin and Ethereum.
+//
+// StartSign returns the raw signature, which can be normalized later.
+func StartSignLowS(config *config.Config, signers []party.ID, message []byte, pl *pool.Pool) protocol.StartFunc {
 	return func(sessionID []byte) (round.Session, error) {
+		r, err := StartSign(config, signers, message, pl)(sessionID)
+		if err != nil {
+			return nil, err
+		}
+		r.(*round1).LowS = true
+		return r, nil
+	}
+}
+
+// ContextSignature is the result of StartSignWithContext.
+type ContextSignature struct {
+	// Signature is the ECDSA signature over the message, which does not depend on Context.
+	Signature *ecdsa.Signature
+	// Context is the byte string all signers agreed on.
+	Context []byte
+	// SSID is the hash of the session's parameters, including Context, to which all messages
+	// and proofs of the session are bound.
+	SSID []byte
+}
+
+// StartSignWithContext is the same as StartSign, but also binds the session to context,
+// for instance a timestamp or an audit nonce, which all signers must agree on.
+//
+// The context is included in the hash of the session, and therefore in the challenge of every proof,
+// but not in the signed message. The result is a *ContextSignature.
+func StartSignWithContext(config *config.Config, signers []party.ID, message, context []byte, pl *pool.Pool) protocol.StartFunc {
+	if context == nil {
+		context = []byte{}
+	}
+	return startSign(config, signers, message, nil, context, pl, nil)
+}
+
+// startSign creates the first round of a signing session for message, or for messageScalar if it is not nil.
+func startSign(config *config.Config, signers []party.ID, message []byte, messageScalar curve.Scalar, context []byte, pl *pool.Pool, encBackend zkenc.Backend) protocol.StartFunc {
+	if encBackend == nil {
+		encBackend = zkenc.DefaultBackend
+	}
+	return func(sessionID []byte) (round.Session, error) {
+		if !config.HasSecret() {
+			return nil, errors.New("sign.Create: this config has no secret share; it is public-only")
+		}
 		group := config.Group
 
 		// this could be used to indicate a pre-signature later on
-		if len(message) == 0 {
+		if len(message) == 0 && messageScalar == nil {
 			return nil, errors.New("sign.Create: message is nil")
 		}
 
@@ -40,13 +119,34 @@ func StartSign(config *config.Config, signers []party.ID, message []byte, pl *po
 			Group:            config.Group,
 		}
 
-		helper, err := round.NewSession(info, sessionID, pl, config, types.SigningMessage(message))
+		auxInfo := []hash.WriterToWithDomain{config}
+		if messageScalar != nil {
+			data, err := messageScalar.MarshalBinary()
+			if err != nil {
+				return nil, fmt.Errorf("sign.Create: %w", err)
+			}
+			auxInfo = append(auxInfo, &hash.BytesWithDomain{TheDomain: "Signature Message Scalar", Bytes: data})
+		} else {
+			auxInfo = append(auxInfo, types.SigningMessage(message))
+			messageScalar = curve.MessageToScalar(group, message)
+		}
+		if context != nil {
+			auxInfo = append(auxInfo, types.SigningContext(context))
+		}
+		if err := config.CheckSigners(party.NewIDSlice(signers)); err != nil {
+			return nil, fmt.Errorf("sign.Create: %w", err)
+		}
+		if err := polynomial.CheckInterpolationDomain(group, signers); err != nil {
+			return nil, fmt.Errorf("sign.Create: %w", err)
+		}
+
+		helper, err := round.NewSession(info, sessionID, pl, auxInfo...)
 		if err != nil {
 			return nil, fmt.Errorf("sign.Create: %w", err)
 		}
 
-		if !config.CanSign(helper.PartyIDs()) {
-			return nil, errors.New("sign.Create: signers is not a valid signing subset")
+		if config.SchnorrOnly() {
+			return nil, errors.New("sign.Create: config has no Paillier keys, it can only be used for Schnorr signatures")
 		}
 
 		// Scale public data
@@ -77,6 +177,9 @@ func StartSign(config *config.Config, signers []party.ID, message []byte, pl *po
 			Pedersen:       Pedersen,
 			ECDSA:          ECDSA,
 			Message:        message,
+			MessageScalar:  messageScalar,
+			Context:        context,
+			EncBackend:     encBackend,
 		}, nil
 	}
 }
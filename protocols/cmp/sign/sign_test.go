@@ -1,16 +1,32 @@
 package sign
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
 	mrand "math/rand"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/cronokirby/saferith"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	dcrecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/fxamacker/cbor/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/test"
 	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	zkenc "github.com/taurusgroup/multi-party-sig/pkg/zk/enc"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
 	"golang.org/x/crypto/sha3"
 )
 
@@ -33,21 +49,12 @@ func TestRound(t *testing.T) {
 	messageHash := make([]byte, 64)
 	sha3.ShakeSum128(messageHash, messageToSign)
 
-	rounds := make([]round.Session, 0, N)
-	for _, partyID := range partyIDs {
-		c := configs[partyID]
-		r, err := StartSign(c, partyIDs, messageHash, pl)(nil)
-		require.NoError(t, err, "round creation should not result in an error")
-		rounds = append(rounds, r)
-	}
+	rounds, err := test.StartRounds(partyIDs, func(partyID party.ID) protocol.StartFunc {
+		return StartSign(configs[partyID], partyIDs, messageHash, pl)
+	})
+	require.NoError(t, err, "round creation should not result in an error")
 
-	for {
-		err, done := test.Rounds(rounds, nil)
-		require.NoError(t, err, "failed to process round")
-		if done {
-			break
-		}
-	}
+	require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
 
 	for _, r := range rounds {
 		require.IsType(t, &round.Output{}, r, "expected result round")
@@ -57,3 +64,697 @@ func TestRound(t *testing.T) {
 		assert.True(t, signature.Verify(publicPoint, messageHash), "expected valid signature")
 	}
 }
+
+// newHelper returns a round.Helper for partyIDs[0], which can be used to test individual rounds.
+func newHelper(t *testing.T, group curve.Curve, partyIDs party.IDSlice) *round.Helper {
+	helper, err := round.NewSession(round.Info{
+		ProtocolID:       protocolSignID,
+		FinalRoundNumber: protocolSignRounds,
+		SelfID:           partyIDs[0],
+		PartyIDs:         partyIDs,
+		Threshold:        1,
+		Group:            group,
+	}, nil, nil)
+	require.NoError(t, err)
+	return helper
+}
+
+func TestRejectZeroShares(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := test.PartyIDs(2)
+	helper := newHelper(t, group, partyIDs)
+	from := partyIDs[1]
+
+	r3 := &round3{round2: &round2{
+		round1:        &round1{Helper: helper},
+		BigGammaShare: map[party.ID]curve.Point{},
+	}}
+	for _, Gamma := range []curve.Point{nil, group.NewPoint()} {
+		err := r3.StoreBroadcastMessage(round.Message{From: from, Content: &broadcast3{BigGammaShare: Gamma}})
+		assert.Error(t, err, "identity Γ should be rejected")
+	}
+
+	r4 := &round4{
+		round3:         r3,
+		DeltaShares:    map[party.ID]curve.Scalar{},
+		BigDeltaShares: map[party.ID]curve.Point{},
+	}
+	one := group.NewScalar().SetNat(new(saferith.Nat).SetUint64(1))
+	for _, c := range []*broadcast4{
+		{DeltaShare: nil, BigDeltaShare: one.ActOnBase()},
+		{DeltaShare: group.NewScalar(), BigDeltaShare: one.ActOnBase()},
+		{DeltaShare: one, BigDeltaShare: nil},
+		{DeltaShare: one, BigDeltaShare: group.NewPoint()},
+	} {
+		err := r4.StoreBroadcastMessage(round.Message{From: from, Content: c})
+		assert.Error(t, err, "zero δ or identity Δ should be rejected")
+	}
+
+	r5 := &round5{round4: r4, SigmaShares: map[party.ID]curve.Scalar{}, ChiShareR: map[party.ID]curve.Point{}}
+	for _, c := range []*broadcast5{
+		{SigmaShare: nil, ChiShareR: one.ActOnBase()},
+		{SigmaShare: group.NewScalar(), ChiShareR: one.ActOnBase()},
+		{SigmaShare: one, ChiShareR: nil},
+		{SigmaShare: one, ChiShareR: group.NewPoint()},
+	} {
+		err := r5.StoreBroadcastMessage(round.Message{From: from, Content: c})
+		assert.Error(t, err, "zero σ or identity S should be rejected")
+	}
+}
+
+func TestAbortZeroDelta(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := test.PartyIDs(2)
+	helper := newHelper(t, group, partyIDs)
+
+	// the shares are individually valid, but sum to δ = 0, which cannot be inverted
+	delta := sample.Scalar(rand.Reader, group)
+	minusDelta := group.NewScalar().Set(delta).Negate()
+	r4 := &round4{
+		round3: &round3{round2: &round2{round1: &round1{Helper: helper}}},
+		DeltaShares: map[party.ID]curve.Scalar{
+			partyIDs[0]: delta,
+			partyIDs[1]: minusDelta,
+		},
+		BigDeltaShares: map[party.ID]curve.Point{
+			partyIDs[0]: delta.ActOnBase(),
+			partyIDs[1]: minusDelta.ActOnBase(),
+		},
+	}
+	out := make(chan *round.Message, 2)
+	rNext, err := r4.Finalize(out)
+	require.NoError(t, err)
+	assert.IsType(t, &round.Abort{}, rNext, "zero δ should abort")
+}
+
+// corruptSigmaRule adds 1 to the σ share of party "a".
+type corruptSigmaRule struct{}
+
+func (corruptSigmaRule) ModifyBefore(round.Session) {}
+func (corruptSigmaRule) ModifyAfter(round.Session)  {}
+func (corruptSigmaRule) ModifyContent(rNext round.Session, _ party.ID, content round.Content) {
+	if body, ok := content.(*broadcast5); ok && rNext.SelfID() == "a" {
+		// the share is also stored in rNext, so that "a" aborts like the other parties
+		body.SigmaShare.Add(rNext.Group().NewScalar().SetNat(new(saferith.Nat).SetUint64(1)))
+	}
+}
+
+func TestBlameInvalidSigmaShare(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 3
+	configs, partyIDs := test.GenerateConfig(group, N, N-1, mrand.New(mrand.NewSource(1)), pl)
+
+	messageHash := make([]byte, 32)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+
+	rounds, err := test.StartRounds(partyIDs, func(partyID party.ID) protocol.StartFunc {
+		return StartSign(configs[partyID], partyIDs, messageHash, pl)
+	})
+	require.NoError(t, err, "round creation should not result in an error")
+
+	require.NoError(t, test.RunRounds(rounds, corruptSigmaRule{}), "failed to process round")
+
+	for _, r := range rounds {
+		require.IsType(t, &round.Abort{}, r, "an invalid σ share should abort")
+		abort := r.(*round.Abort)
+		assert.ErrorIs(t, abort.Err, ErrInvalidSignature)
+		assert.Equal(t, []party.ID{"a"}, abort.Culprits)
+	}
+}
+
+func TestBlameInvalidG(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 3
+	configs, partyIDs := test.GenerateConfig(group, N, N-1, mrand.New(mrand.NewSource(1)), pl)
+
+	messageHash := make([]byte, 32)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+
+	handlers := make(map[party.ID]*protocol.MultiHandler, N)
+	for _, id := range partyIDs {
+		h, err := protocol.NewMultiHandler(StartSign(configs[id], partyIDs, messageHash, pl), nil)
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	// "a" broadcasts the encryption of another value as Gᵢ, so that it no longer matches its proofs
+	G, _ := configs["b"].Public["a"].Paillier.Enc(curve.MakeInt(sample.Scalar(rand.Reader, group)))
+	test.Deliver(handlers, func(from, to party.ID, msg *protocol.Message) {
+		if from == "a" && msg.Broadcast && msg.RoundNumber == 2 {
+			var fields map[string]cbor.RawMessage
+			require.NoError(t, cbor.Unmarshal(msg.Data, &fields))
+			fields["G"], _ = cbor.Marshal(G)
+			msg.Data, _ = cbor.Marshal(fields)
+		}
+		handlers[to].Accept(msg)
+	})
+
+	for _, id := range []party.ID{"b", "c"} {
+		_, err := handlers[id].Result()
+		var blamed protocol.Error
+		require.ErrorAs(t, err, &blamed, "party %s should reject the invalid G", id)
+		assert.Equal(t, []party.ID{"a"}, blamed.Culprits)
+	}
+}
+
+func TestEstimateMessageSizes(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 3
+	configs, partyIDs := test.GenerateConfig(group, N, N-1, mrand.New(mrand.NewSource(1)), pl)
+
+	messageHash := make([]byte, 32)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+
+	rounds, err := test.StartRounds(partyIDs, func(partyID party.ID) protocol.StartFunc {
+		return StartSign(configs[partyID], partyIDs, messageHash, pl)
+	})
+	require.NoError(t, err, "round creation should not result in an error")
+
+	rule := test.NewSizeRule("a")
+	require.NoError(t, test.RunRounds(rounds, rule), "failed to process round")
+
+	estimates := EstimateMessageSizes(group, N)
+	require.Len(t, estimates, 7)
+	for _, estimate := range estimates {
+		actual := rule.Sizes[estimate.Round][estimate.Broadcast]
+		require.Len(t, actual, estimate.Count, "round %d", estimate.Round)
+		for _, size := range actual {
+			assert.InEpsilon(t, estimate.Size, size, 0.01, "round %d, broadcast %v", estimate.Round, estimate.Broadcast)
+		}
+	}
+}
+
+// countingBackend wraps zkenc.DefaultBackend and counts the number of calls.
+type countingBackend struct {
+	proofs, verifications int64
+}
+
+func (b *countingBackend) NewProof(group curve.Curve, hash *hash.Hash, public zkenc.Public, private zkenc.Private) *zkenc.Proof {
+	atomic.AddInt64(&b.proofs, 1)
+	return zkenc.DefaultBackend.NewProof(group, hash, public, private)
+}
+
+func (b *countingBackend) Verify(group curve.Curve, hash *hash.Hash, public zkenc.Public, proof *zkenc.Proof) bool {
+	atomic.AddInt64(&b.verifications, 1)
+	return zkenc.DefaultBackend.Verify(group, hash, public, proof)
+}
+
+func TestStartSignWithBackend(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 3
+	configs, partyIDs := test.GenerateConfig(group, N, N-1, mrand.New(mrand.NewSource(1)), pl)
+	publicPoint := configs[partyIDs[0]].PublicPoint()
+
+	messageHash := make([]byte, 32)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+
+	backend := &countingBackend{}
+	rounds, err := test.StartRounds(partyIDs, func(partyID party.ID) protocol.StartFunc {
+		return StartSignWithBackend(configs[partyID], partyIDs, messageHash, pl, backend)
+	})
+	require.NoError(t, err, "round creation should not result in an error")
+
+	require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
+
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r, "expected result round")
+		signature := r.(*round.Output).Result.(*ecdsa.Signature)
+		assert.True(t, signature.Verify(publicPoint, messageHash), "expected valid signature")
+	}
+
+	// each party proves and verifies once for every other party
+	assert.EqualValues(t, N*(N-1), backend.proofs)
+	assert.EqualValues(t, N*(N-1), backend.verifications)
+}
+
+func TestRoundNilPool(t *testing.T) {
+	group := curve.Secp256k1{}
+
+	N := 3
+	configs, partyIDs := test.GenerateConfig(group, N, N-1, mrand.New(mrand.NewSource(1)), nil)
+	publicPoint := configs[partyIDs[0]].PublicPoint()
+
+	messageHash := make([]byte, 32)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+
+	rounds, err := test.StartRounds(partyIDs, func(partyID party.ID) protocol.StartFunc {
+		return StartSign(configs[partyID], partyIDs, messageHash, nil)
+	})
+	require.NoError(t, err, "round creation should not result in an error")
+
+	require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
+
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r, "expected result round")
+		signature := r.(*round.Output).Result.(*ecdsa.Signature)
+		assert.True(t, signature.Verify(publicPoint, messageHash), "expected valid signature")
+	}
+}
+
+func TestRejectOtherCurve(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := test.PartyIDs(2)
+	helper := newHelper(t, group, partyIDs)
+	from := partyIDs[1]
+
+	s := sample.Scalar(rand.Reader, group)
+	foreignScalar := test.OtherScalar{Scalar: s}
+	foreignPoint := test.OtherPoint{Point: s.ActOnBase()}
+
+	r3 := &round3{round2: &round2{
+		round1:        &round1{Helper: helper},
+		BigGammaShare: map[party.ID]curve.Point{},
+	}}
+	err := r3.StoreBroadcastMessage(round.Message{From: from, Content: &broadcast3{BigGammaShare: foreignPoint}})
+	assert.EqualError(t, err, "curve: expected an element of secp256k1, got P-256")
+	assert.Empty(t, r3.BigGammaShare, "nothing should be stored")
+
+	r4 := &round4{
+		round3:         r3,
+		DeltaShares:    map[party.ID]curve.Scalar{},
+		BigDeltaShares: map[party.ID]curve.Point{},
+	}
+	err = r4.StoreBroadcastMessage(round.Message{From: from, Content: &broadcast4{DeltaShare: s, BigDeltaShare: foreignPoint}})
+	assert.Error(t, err)
+	err = r4.StoreBroadcastMessage(round.Message{From: from, Content: &broadcast4{DeltaShare: foreignScalar, BigDeltaShare: s.ActOnBase()}})
+	assert.Error(t, err)
+
+	r5 := &round5{round4: r4, SigmaShares: map[party.ID]curve.Scalar{}, ChiShareR: map[party.ID]curve.Point{}}
+	err = r5.StoreBroadcastMessage(round.Message{From: from, Content: &broadcast5{SigmaShare: foreignScalar, ChiShareR: s.ActOnBase()}})
+	assert.Error(t, err)
+	err = r5.StoreBroadcastMessage(round.Message{From: from, Content: &broadcast5{SigmaShare: s, ChiShareR: foreignPoint}})
+	assert.Error(t, err)
+}
+
+func TestStartSignWithContext(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 2
+	configs, partyIDs := test.GenerateConfig(group, N, N-1, mrand.New(mrand.NewSource(1)), pl)
+	publicPoint := configs[partyIDs[0]].PublicPoint()
+
+	messageHash := make([]byte, 32)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+
+	sign := func(context []byte) *ContextSignature {
+		rounds, err := test.StartRounds(partyIDs, func(partyID party.ID) protocol.StartFunc {
+			return StartSignWithContext(configs[partyID], partyIDs, messageHash, context, pl)
+		})
+		require.NoError(t, err, "round creation should not result in an error")
+		require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
+
+		var result *ContextSignature
+		for _, r := range rounds {
+			require.IsType(t, &round.Output{}, r, "expected result round")
+			result = r.(*round.Output).Result.(*ContextSignature)
+			assert.Equal(t, context, result.Context)
+			assert.True(t, result.Signature.Verify(publicPoint, messageHash), "expected valid signature")
+		}
+		return result
+	}
+
+	first := sign([]byte("2026-10-14T09:00:00Z"))
+	second := sign([]byte("2026-10-14T10:00:00Z"))
+	assert.NotEqual(t, first.SSID, second.SSID, "different contexts should give different transcripts")
+
+	plain, err := StartSign(configs[partyIDs[0]], partyIDs, messageHash, pl)(nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, plain.SSID(), first.SSID, "a context should change the transcript")
+}
+
+func TestStartSignUnknownSigner(t *testing.T) {
+	group := curve.Secp256k1{}
+	c := &config.Config{
+		Group:     group,
+		ID:        "a",
+		Threshold: 1,
+		ECDSA:     sample.Scalar(rand.Reader, group),
+		Public:    map[party.ID]*config.Public{"a": {}, "b": {}, "c": {}},
+	}
+	_, err := StartSign(c, []party.ID{"a", "d"}, []byte("hello"), nil)(nil)
+	var unknown *config.UnknownSignersError
+	require.ErrorAs(t, err, &unknown, "the coordinator should learn which signers are unknown")
+	assert.Equal(t, party.IDSlice{"d"}, unknown.Unknown)
+	assert.EqualError(t, err, "sign.Create: config: signers [d] are not parties of the config")
+}
+
+func TestStartSignCollidingIDs(t *testing.T) {
+	group := curve.Secp256k1{}
+	signers := []party.ID{"a", "\x00a"}
+	c := &config.Config{
+		Group:     group,
+		ID:        "a",
+		Threshold: 1,
+		ECDSA:     sample.Scalar(rand.Reader, group),
+		Public:    map[party.ID]*config.Public{"a": {}, "\x00a": {}},
+	}
+	_, err := StartSign(c, signers, []byte("hello"), nil)(nil)
+	assert.ErrorContains(t, err, "same interpolation point")
+}
+
+func TestStartSignScalar(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 2
+	configs, partyIDs := test.GenerateConfig(group, N, N-1, mrand.New(mrand.NewSource(1)), pl)
+	publicPoint := configs[partyIDs[0]].PublicPoint()
+
+	messageHash := make([]byte, 32)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+	m := curve.MessageToScalar(group, messageHash)
+
+	_, err := StartSignScalar(configs[partyIDs[0]], partyIDs, group.NewScalar(), pl)(nil)
+	assert.Error(t, err, "a zero scalar should be rejected")
+	_, err = StartSignScalar(configs[partyIDs[0]], partyIDs, test.OtherScalar{Scalar: m}, pl)(nil)
+	assert.Error(t, err, "a scalar of another curve should be rejected")
+
+	sign := func(start func(c *config.Config) (round.Session, error)) []*ecdsa.Signature {
+		rounds := make([]round.Session, 0, N)
+		for _, partyID := range partyIDs {
+			r, err := start(configs[partyID])
+			require.NoError(t, err, "round creation should not result in an error")
+			rounds = append(rounds, r)
+		}
+		require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
+		signatures := make([]*ecdsa.Signature, 0, N)
+		for _, r := range rounds {
+			require.IsType(t, &round.Output{}, r, "expected result round")
+			signatures = append(signatures, r.(*round.Output).Result.(*ecdsa.Signature))
+		}
+		return signatures
+	}
+
+	hashed := sign(func(c *config.Config) (round.Session, error) {
+		return StartSign(c, partyIDs, messageHash, pl)(nil)
+	})
+	scalar := sign(func(c *config.Config) (round.Session, error) {
+		return StartSignScalar(c, partyIDs, m, pl)(nil)
+	})
+	for i := range scalar {
+		// both paths sign the same scalar, so both signatures verify against the message hash
+		assert.True(t, hashed[i].Verify(publicPoint, messageHash))
+		assert.True(t, scalar[i].Verify(publicPoint, messageHash))
+		assert.True(t, scalar[i].VerifyScalar(publicPoint, m))
+	}
+}
+
+func TestStartSignLowS(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 2
+	configs, partyIDs := test.GenerateConfig(group, N, N-1, mrand.New(mrand.NewSource(1)), pl)
+	publicPoint := configs[partyIDs[0]].PublicPoint()
+	publicKey, err := publicPoint.MarshalBinary()
+	require.NoError(t, err)
+
+	for i := 0; i < 8; i++ {
+		messageHash := make([]byte, 32)
+		sha3.ShakeSum128(messageHash, []byte{byte(i)})
+
+		rounds, err := test.StartRounds(partyIDs, func(partyID party.ID) protocol.StartFunc {
+			return StartSignLowS(configs[partyID], partyIDs, messageHash, pl)
+		})
+		require.NoError(t, err, "round creation should not result in an error")
+		require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
+
+		for _, r := range rounds {
+			require.IsType(t, &round.Output{}, r, "expected result round")
+			signature := r.(*round.Output).Result.(*ecdsa.Signature)
+			assert.True(t, signature.IsLowS(), "s should be at most n/2")
+			require.True(t, signature.Verify(publicPoint, messageHash))
+
+			// the public key is recovered from r ‖ s ‖ v
+			rsv, err := ecdsa.Signature{R: signature.R, S: signature.S}.SigEthereum()
+			require.NoError(t, err)
+			compact := append([]byte{27 + 4 + rsv[64]}, rsv[:64]...)
+			recovered, _, err := dcrecdsa.RecoverCompact(compact, messageHash)
+			require.NoError(t, err)
+			assert.Equal(t, publicKey, recovered.SerializeCompressed(), "recovery should yield the public key")
+		}
+	}
+}
+
+func TestRejectMalformedMessage(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	configs, partyIDs := test.GenerateConfig(group, 2, 1, mrand.New(mrand.NewSource(1)), pl)
+	messageHash := make([]byte, 32)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+
+	handlers := make(map[party.ID]*protocol.MultiHandler, len(partyIDs))
+	for _, id := range partyIDs {
+		h, err := protocol.NewMultiHandler(StartSign(configs[id], partyIDs, messageHash, pl), nil)
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	test.Deliver(handlers, func(from, to party.ID, msg *protocol.Message) {
+		// "a" sends a 64 byte Γ instead of a compressed point
+		if from == "a" && msg.Broadcast && msg.RoundNumber == 3 {
+			var fields map[string]cbor.RawMessage
+			require.NoError(t, cbor.Unmarshal(msg.Data, &fields))
+			fields["BigGammaShare"], _ = cbor.Marshal(make([]byte, 64))
+			msg.Data, _ = cbor.Marshal(fields)
+		}
+		handlers[to].Accept(msg)
+	})
+
+	_, err := handlers["b"].Result()
+	var blamed protocol.Error
+	require.ErrorAs(t, err, &blamed)
+	assert.Equal(t, []party.ID{"a"}, blamed.Culprits)
+	assert.ErrorContains(t, err, "schema: field BigGammaShare has 64 bytes, expected 33")
+}
+
+func TestSighashInput(t *testing.T) {
+	// The native P2WPKH and P2SH-P2WPKH examples of BIP-143, whose hash preimage ends with the sighash type 01000000.
+	vectors := []struct {
+		preimage, sighash string
+	}{
+		{
+			preimage: "0100000096b827c8483d4e9b96712b6713a7b68d6e8003a781feba36c31143470b4efd3752b0a642eea2fb7ae638c36f6252b6750293dbe574a806984b8e4d8548339a3bef51e1b804cc89d182d279655c3aa89e815b1b309fe287d9b2b55d57b90ec68a010000001976a9141d0f172a0ecb48aee1be1f2687d2963ae33f71a188ac0046c32300000000ffffffff863ef3e1a92afbfdb97f31ad0fc7683ee943e9abcf2501590ff8f6551f47e5e51100000001000000",
+			sighash:  "c37af31116d1b27caf68aae9e3ac82f1477929014d5b917657d0eb49478cb670",
+		},
+		{
+			preimage: "01000000b0287b4a252ac05af83d2dcef00ba313af78a3e9c329afa216eb3aa2a7b4613a18606b350cd8bf565266bc352f0caddcf01e8fa789dd8a15386327cf8cabe198db6b1b20aa0fd7b23880be2ecbd4a98130974cf4748fb66092ac4d3ceb1a5477010000001976a91479091972186c449eb1ded22b78e40d009bdf008988ac00ca9a3b00000000feffffffde984f44532e2173ca0d64314fcefe6d30da6f8cf27bafa706da61df8a226c839204000001000000",
+			sighash:  "64f3b0f4dd2bb3aa1ce8566d220cc74dda9df97d8490cc81d89d735c92e59fb6",
+		},
+	}
+	for _, vector := range vectors {
+		preimage, _ := hex.DecodeString(vector.preimage)
+		in := &SighashInput{Message: preimage[:len(preimage)-4], SighashType: SighashAll}
+		assert.Equal(t, vector.sighash, hex.EncodeToString(in.Digest()))
+	}
+
+	// the signature published with the first example verifies against the digest
+	preimage, _ := hex.DecodeString(vectors[0].preimage)
+	input := &SighashInput{Message: preimage[:len(preimage)-4], SighashType: SighashAll}
+	digest := input.Digest()
+	published, _ := hex.DecodeString("304402203609e17b84f6a7d30c80bfa610b5b4542f32a8a0d5447a12fb1366d7f01cc44a0220573a954c4518331561406f90300e8f3358f51928d43c212a8caed02de67eebee")
+	publishedKey, _ := hex.DecodeString("025476c2e83188368da1ff3e292e7acafcdb3566bb0ad253f62fc70f07aeee6357")
+	signature, err := dcrecdsa.ParseDERSignature(published)
+	require.NoError(t, err)
+	key, err := secp256k1.ParsePubKey(publishedKey)
+	require.NoError(t, err)
+	assert.True(t, signature.Verify(digest, key), "the BIP-143 signature should verify")
+	assert.NotEqual(t, digest, (&SighashInput{Message: input.Message, SighashType: 0x81}).Digest(), "the sighash type should be committed")
+
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+	N := 2
+	configs, partyIDs := test.GenerateConfig(group, N, N-1, mrand.New(mrand.NewSource(1)), pl)
+	publicKey, err := configs[partyIDs[0]].PublicPoint().MarshalBinary()
+	require.NoError(t, err)
+	pk, err := secp256k1.ParsePubKey(publicKey)
+	require.NoError(t, err)
+
+	rounds, err := test.StartRounds(partyIDs, func(partyID party.ID) protocol.StartFunc {
+		return StartSignSighash(configs[partyID], partyIDs, input, pl)
+	})
+	require.NoError(t, err, "round creation should not result in an error")
+	require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r, "expected result round")
+		signature := r.(*round.Output).Result.(*ecdsa.Signature)
+		assert.True(t, signature.IsLowS(), "Bitcoin requires s to be at most n/2")
+
+		data, err := signature.Bytes()
+		require.NoError(t, err)
+		var R, S secp256k1.ModNScalar
+		R.SetByteSlice(data[:32])
+		S.SetByteSlice(data[32:])
+		assert.True(t, dcrecdsa.NewSignature(&R, &S).Verify(digest, pk), "the signature should verify against the sighash")
+	}
+
+	_, err = StartSignSighash(configs[partyIDs[0]], partyIDs, &SighashInput{SighashType: SighashAll}, pl)(nil)
+	assert.EqualError(t, err, "sign.Create: sighash message is nil")
+}
+
+func BenchmarkSign(b *testing.B) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	messageHash := make([]byte, 32)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+
+	for _, N := range []int{2, 3, 5} {
+		configs, partyIDs := test.GenerateConfig(group, N, N-1, mrand.New(mrand.NewSource(1)), pl)
+		b.Run(fmt.Sprintf("parties=%d", N), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				rounds := make([]round.Session, 0, N)
+				for _, partyID := range partyIDs {
+					r, err := StartSign(configs[partyID], partyIDs, messageHash, pl)(nil)
+					if err != nil {
+						b.Fatal(err)
+					}
+					rounds = append(rounds, r)
+				}
+				for {
+					err, done := test.Rounds(rounds, nil)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if done {
+						break
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSignAccountable(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N, T := 4, 2
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+	signers := partyIDs[:T+1]
+	nonSigner := partyIDs[T+1]
+
+	messageHash := make([]byte, 32)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+
+	rounds, err := test.StartRounds(signers, func(partyID party.ID) protocol.StartFunc {
+		return StartSignAccountable(configs[partyID], signers, messageHash, pl)
+	})
+	require.NoError(t, err, "round creation should not result in an error")
+	require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
+
+	public := configs[nonSigner].Public
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r, "expected result round")
+		result := r.(*round.Output).Result
+		require.IsType(t, &AccountableSignature{}, result)
+		sig := result.(*AccountableSignature)
+		cert := sig.Certificate
+		assert.Equal(t, party.NewIDSlice(signers), cert.Signers, "the certificate should name exactly the signers")
+		require.NoError(t, cert.Verify(public, sig.Signature, messageHash))
+
+		// claim that a party which did not take part also signed
+		claimed := &QuorumCertificate{
+			SSID:         cert.SSID,
+			Signers:      party.NewIDSlice(append(signers.Copy(), nonSigner)),
+			Attestations: map[party.ID]*zksch.Proof{},
+		}
+		for j, a := range cert.Attestations {
+			claimed.Attestations[j] = a
+		}
+		claimed.Attestations[nonSigner] = cert.Attestations[signers[0]]
+		assert.Error(t, claimed.Verify(public, sig.Signature, messageHash), "a non-participant should not be accepted as a signer")
+
+		extra := &QuorumCertificate{SSID: cert.SSID, Signers: cert.Signers, Attestations: claimed.Attestations}
+		assert.Error(t, extra.Verify(public, sig.Signature, messageHash), "an attestation for a non-participant should be rejected")
+
+		missing := &QuorumCertificate{SSID: cert.SSID, Signers: cert.Signers, Attestations: map[party.ID]*zksch.Proof{}}
+		for _, j := range signers[1:] {
+			missing.Attestations[j] = cert.Attestations[j]
+		}
+		assert.Error(t, missing.Verify(public, sig.Signature, messageHash), "every signer should have attested")
+
+		otherMessage := make([]byte, 32)
+		assert.Error(t, cert.Verify(public, sig.Signature, otherMessage))
+	}
+}
+
+// TestSignAccountableHandler runs StartSignAccountable through protocol.MultiHandler,
+// which checks each message against the schema of its round, unlike test.Rounds.
+func TestSignAccountableHandler(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 2
+	configs, partyIDs := test.GenerateConfig(group, N, N-1, mrand.New(mrand.NewSource(1)), pl)
+	messageHash := make([]byte, 32)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+
+	network := test.NewNetwork(partyIDs)
+	handlers := make(map[party.ID]*protocol.MultiHandler, N)
+	var wg sync.WaitGroup
+	for _, id := range partyIDs {
+		h, err := protocol.NewMultiHandler(StartSignAccountable(configs[id], partyIDs, messageHash, pl), nil)
+		require.NoError(t, err)
+		handlers[id] = h
+		wg.Add(1)
+		go func(id party.ID) {
+			defer wg.Done()
+			test.HandlerLoop(id, handlers[id], network)
+		}(id)
+	}
+	wg.Wait()
+
+	for id, h := range handlers {
+		result, err := h.Result()
+		require.NoError(t, err, "party %s", id)
+		require.IsType(t, &AccountableSignature{}, result)
+		sig := result.(*AccountableSignature)
+		assert.NoError(t, sig.Certificate.Verify(configs[id].Public, sig.Signature, messageHash))
+	}
+}
+
+func TestStartSignPublicOnly(t *testing.T) {
+	group := curve.Secp256k1{}
+
+	N := 2
+	configs, partyIDs := test.GenerateConfig(group, N, N-1, mrand.New(mrand.NewSource(1)), nil)
+	c := configs[partyIDs[0]]
+	require.True(t, c.HasSecret())
+
+	// the public parameters of c, as if loaded from a public-only export
+	public := &config.Config{
+		Group:     c.Group,
+		ID:        c.ID,
+		Threshold: c.Threshold,
+		RID:       c.RID,
+		ChainKey:  c.ChainKey,
+		Public:    c.Public,
+	}
+	assert.False(t, public.HasSecret())
+
+	messageHash := make([]byte, 32)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+	_, err := StartSign(public, partyIDs, messageHash, nil)(nil)
+	assert.EqualError(t, err, "sign.Create: this config has no secret share; it is public-only")
+}
@@ -0,0 +1,115 @@
+package sign
+
+import (
+	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// MessageSize describes the messages of a given type sent by a single party during one round.
+type MessageSize = round.MessageSize
+
+// EstimateMessageSizes returns the expected sizes of the messages sent by each party
+// during a signing session with n signers over group, ordered by round.
+//
+// The estimate is derived from the encoded sizes of points, scalars, Paillier ciphertexts and ZK proofs,
+// at the security level defined in internal/params.
+func EstimateMessageSizes(group curve.Curve, n int) []MessageSize {
+	return estimateMessageSizes(group, n, false)
+}
+
+// EstimateAccountableMessageSizes is the same as EstimateMessageSizes, for a session started with StartSignAccountable,
+// in which the broadcast of round 5 also contains an attestation.
+func EstimateAccountableMessageSizes(group curve.Curve, n int) []MessageSize {
+	return estimateMessageSizes(group, n, true)
+}
+
+func estimateMessageSizes(group curve.Curve, n int, accountable bool) []MessageSize {
+	var (
+		point      = round.CBORBytes(round.PointSize(group))
+		scalar     = round.CBORBytes((group.ScalarBits() + 7) / 8)
+		ciphertext = round.CBORBytes(params.BytesCiphertext)
+		natModN    = round.CBORBytes(params.BytesIntModN)
+	)
+	// Z = α + e⋅x has one more bit than α
+	cborInt := func(bits int) int { return round.CBORBytes(1 + (bits+1+7)/8) }
+
+	// zkenc: S, A, C, Z₁, Z₂, Z₃
+	proofEnc := round.CBORMap(map[string]int{
+		"S":  natModN,
+		"A":  ciphertext,
+		"C":  natModN,
+		"Z1": cborInt(params.LPlusEpsilon),
+		"Z2": natModN,
+		"Z3": cborInt(params.LPlusEpsilon + params.BitsIntModN),
+	})
+	// zkaffg: A, Bₓ, By, E, S, F, T, Z₁, Z₂, Z₃, Z₄, W, Wy
+	proofAffG := round.CBORMap(map[string]int{
+		"A":  ciphertext,
+		"Bx": point,
+		"By": ciphertext,
+		"E":  natModN,
+		"S":  natModN,
+		"F":  natModN,
+		"T":  natModN,
+		"Z1": cborInt(params.LPlusEpsilon),
+		"Z2": cborInt(params.LPrimePlusEpsilon),
+		"Z3": cborInt(params.LPlusEpsilon + params.BitsIntModN),
+		"Z4": cborInt(params.LPlusEpsilon + params.BitsIntModN),
+		"W":  natModN,
+		"Wy": natModN,
+	})
+	// zklog*: S, A, Y, D, Z₁, Z₂, Z₃
+	proofLogStar := round.CBORMap(map[string]int{
+		"S":  natModN,
+		"A":  ciphertext,
+		"Y":  point,
+		"D":  natModN,
+		"Z1": cborInt(params.LPlusEpsilon),
+		"Z2": natModN,
+		"Z3": cborInt(params.LPlusEpsilon + params.BitsIntModN),
+	})
+
+	broadcast5 := map[string]int{
+		"SigmaShare": scalar,
+		"ChiShareR":  point,
+	}
+	if accountable {
+		// zksch: C, Z
+		broadcast5["Attestation"] = round.CBORMap(map[string]int{
+			"C": round.CBORMap(map[string]int{"C": point}),
+			"Z": round.CBORMap(map[string]int{"Z": scalar}),
+		})
+	}
+
+	others := n - 1
+	return []MessageSize{
+		{Round: 2, Broadcast: true, Count: 1, Size: round.CBORMap(map[string]int{
+			"K": ciphertext,
+			"G": ciphertext,
+		})},
+		{Round: 2, Count: others, Size: round.CBORMap(map[string]int{
+			"ProofEnc": proofEnc,
+		})},
+		{Round: 3, Broadcast: true, Count: 1, Size: round.CBORMap(map[string]int{
+			"BigGammaShare": point,
+		})},
+		{Round: 3, Count: others, Size: round.CBORMap(map[string]int{
+			"DeltaD":     ciphertext,
+			"DeltaF":     ciphertext,
+			"DeltaProof": proofAffG,
+			"ChiD":       ciphertext,
+			"ChiF":       ciphertext,
+			"ChiProof":   proofAffG,
+			"ProofLog":   proofLogStar,
+		})},
+		{Round: 4, Broadcast: true, Count: 1, Size: round.CBORMap(map[string]int{
+			"DeltaShare":    scalar,
+			"BigDeltaShare": point,
+		})},
+		{Round: 4, Count: others, Size: round.CBORMap(map[string]int{
+			"ProofLog": proofLogStar,
+		})},
+		{Round: 5, Broadcast: true, Count: 1, Size: round.CBORMap(broadcast5)},
+	}
+}
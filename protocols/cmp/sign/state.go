@@ -0,0 +1,313 @@
+package sign
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/cronokirby/saferith"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+// codec implements protocol.SessionCodec for the signing protocol.
+type codec struct {
+	start     protocol.StartFunc
+	sessionID []byte
+}
+
+// NewCodec returns a protocol.SessionCodec which saves and restores the rounds of the session
+// started by StartSign(config, signers, message, pl) with the given sessionID.
+//
+// The serialized rounds contain the secret nonce shares of the session, and must be protected
+// like the config itself. They do not contain the config, which must be provided again here.
+func NewCodec(config *config.Config, signers []party.ID, message, sessionID []byte, pl *pool.Pool) protocol.SessionCodec {
+	return &codec{
+		start:     StartSign(config, signers, message, pl),
+		sessionID: sessionID,
+	}
+}
+
+// state contains the values computed by a party up to a given round, which cannot be recomputed from the config.
+// Points and scalars are stored in their binary encoding.
+type state struct {
+	SSID  []byte
+	Round round.Number
+
+	// round 2
+	K             map[party.ID]*paillier.Ciphertext
+	G             map[party.ID]*paillier.Ciphertext
+	BigGammaShare map[party.ID][]byte
+	GammaShare    *saferith.Int
+	KShare        []byte
+	KNonce        *saferith.Nat
+	GNonce        *saferith.Nat
+
+	// round 3
+	DeltaShareAlpha map[party.ID]*saferith.Int
+	DeltaShareBeta  map[party.ID]*saferith.Int
+	ChiShareAlpha   map[party.ID]*saferith.Int
+	ChiShareBeta    map[party.ID]*saferith.Int
+
+	// round 4
+	DeltaShares    map[party.ID][]byte
+	BigDeltaShares map[party.ID][]byte
+	Gamma          []byte
+	ChiShare       []byte
+
+	// round 5
+	SigmaShares map[party.ID][]byte
+	ChiShareR   map[party.ID][]byte
+	Delta       []byte
+	BigDelta    []byte
+	BigR        []byte
+	R           []byte
+
+	// output
+	Signature []byte
+}
+
+// Marshal implements protocol.SessionCodec.
+func (c *codec) Marshal(session round.Session) ([]byte, error) {
+	s := state{SSID: session.SSID(), Round: session.Number()}
+	var err error
+	switch r := session.(type) {
+	case *round.Output:
+		signature, ok := r.Result.(*ecdsa.Signature)
+		if !ok {
+			return nil, errors.New("sign: unexpected result")
+		}
+		if s.Signature, err = signature.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	case *round1, *round2, *round3, *round4, *round5:
+		if err = s.save(session); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("sign: cannot save round %d", session.Number())
+	}
+	return cbor.Marshal(s)
+}
+
+// save stores the fields of session, and those of the previous rounds.
+func (s *state) save(session round.Session) error {
+	var err error
+	// the cases fall through to also save the fields of the embedded rounds
+	switch r := session.(type) {
+	case *round5:
+		s.SigmaShares, err = marshalScalars(r.SigmaShares)
+		if err != nil {
+			return err
+		}
+		if s.ChiShareR, err = marshalPoints(r.ChiShareR); err != nil {
+			return err
+		}
+		if s.Delta, err = r.Delta.MarshalBinary(); err != nil {
+			return err
+		}
+		if s.BigDelta, err = r.BigDelta.MarshalBinary(); err != nil {
+			return err
+		}
+		if s.BigR, err = r.BigR.MarshalBinary(); err != nil {
+			return err
+		}
+		if s.R, err = r.R.MarshalBinary(); err != nil {
+			return err
+		}
+		return s.save(r.round4)
+	case *round4:
+		if s.DeltaShares, err = marshalScalars(r.DeltaShares); err != nil {
+			return err
+		}
+		if s.BigDeltaShares, err = marshalPoints(r.BigDeltaShares); err != nil {
+			return err
+		}
+		if s.Gamma, err = r.Gamma.MarshalBinary(); err != nil {
+			return err
+		}
+		if s.ChiShare, err = r.ChiShare.MarshalBinary(); err != nil {
+			return err
+		}
+		return s.save(r.round3)
+	case *round3:
+		s.DeltaShareAlpha, s.DeltaShareBeta = r.DeltaShareAlpha, r.DeltaShareBeta
+		s.ChiShareAlpha, s.ChiShareBeta = r.ChiShareAlpha, r.ChiShareBeta
+		return s.save(r.round2)
+	case *round2:
+		s.K, s.G = r.K, r.G
+		if s.BigGammaShare, err = marshalPoints(r.BigGammaShare); err != nil {
+			return err
+		}
+		s.GammaShare = r.GammaShare
+		if s.KShare, err = r.KShare.MarshalBinary(); err != nil {
+			return err
+		}
+		s.KNonce, s.GNonce = r.KNonce, r.GNonce
+	}
+	return nil
+}
+
+// Unmarshal implements protocol.SessionCodec.
+func (c *codec) Unmarshal(data []byte) (round.Session, error) {
+	var s state
+	if err := cbor.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+	session, err := c.start(c.sessionID)
+	if err != nil {
+		return nil, err
+	}
+	r1 := session.(*round1)
+	if !bytes.Equal(s.SSID, r1.SSID()) {
+		return nil, errors.New("sign: state belongs to a different session")
+	}
+	group := r1.Group()
+
+	if s.Round == 0 {
+		signature := ecdsa.EmptySignature(group)
+		if err = signature.UnmarshalBinary(s.Signature); err != nil {
+			return nil, fmt.Errorf("sign: %w", err)
+		}
+		return r1.ResultRound(&signature), nil
+	}
+	if s.Round == 1 {
+		return r1, nil
+	}
+
+	r2 := &round2{
+		round1:     r1,
+		K:          s.K,
+		G:          s.G,
+		GammaShare: s.GammaShare,
+		KShare:     group.NewScalar(),
+		KNonce:     s.KNonce,
+		GNonce:     s.GNonce,
+	}
+	if r2.BigGammaShare, err = unmarshalPoints(group, s.BigGammaShare); err != nil {
+		return nil, err
+	}
+	if err = r2.KShare.UnmarshalBinary(s.KShare); err != nil {
+		return nil, err
+	}
+	if s.Round == 2 {
+		return r2, nil
+	}
+
+	r3 := &round3{
+		round2:          r2,
+		DeltaShareAlpha: s.DeltaShareAlpha,
+		DeltaShareBeta:  s.DeltaShareBeta,
+		ChiShareAlpha:   s.ChiShareAlpha,
+		ChiShareBeta:    s.ChiShareBeta,
+	}
+	if s.Round == 3 {
+		return r3, nil
+	}
+
+	r4 := &round4{
+		round3:   r3,
+		Gamma:    group.NewPoint(),
+		ChiShare: group.NewScalar(),
+	}
+	if r4.DeltaShares, err = unmarshalScalars(group, s.DeltaShares); err != nil {
+		return nil, err
+	}
+	if r4.BigDeltaShares, err = unmarshalPoints(group, s.BigDeltaShares); err != nil {
+		return nil, err
+	}
+	if err = r4.Gamma.UnmarshalBinary(s.Gamma); err != nil {
+		return nil, err
+	}
+	if err = r4.ChiShare.UnmarshalBinary(s.ChiShare); err != nil {
+		return nil, err
+	}
+	if s.Round == 4 {
+		return r4, nil
+	}
+
+	r5 := &round5{
+		round4:   r4,
+		Delta:    group.NewScalar(),
+		BigDelta: group.NewPoint(),
+		BigR:     group.NewPoint(),
+		R:        group.NewScalar(),
+	}
+	if r5.SigmaShares, err = unmarshalScalars(group, s.SigmaShares); err != nil {
+		return nil, err
+	}
+	if r5.ChiShareR, err = unmarshalPoints(group, s.ChiShareR); err != nil {
+		return nil, err
+	}
+	if err = r5.Delta.UnmarshalBinary(s.Delta); err != nil {
+		return nil, err
+	}
+	if err = r5.BigDelta.UnmarshalBinary(s.BigDelta); err != nil {
+		return nil, err
+	}
+	if err = r5.BigR.UnmarshalBinary(s.BigR); err != nil {
+		return nil, err
+	}
+	if err = r5.R.UnmarshalBinary(s.R); err != nil {
+		return nil, err
+	}
+	if s.Round == 5 {
+		return r5, nil
+	}
+	return nil, fmt.Errorf("sign: invalid round %d", s.Round)
+}
+
+func marshalPoints(points map[party.ID]curve.Point) (map[party.ID][]byte, error) {
+	out := make(map[party.ID][]byte, len(points))
+	for j, p := range points {
+		data, err := p.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		out[j] = data
+	}
+	return out, nil
+}
+
+func unmarshalPoints(group curve.Curve, data map[party.ID][]byte) (map[party.ID]curve.Point, error) {
+	out := make(map[party.ID]curve.Point, len(data))
+	for j, d := range data {
+		p := group.NewPoint()
+		if err := p.UnmarshalBinary(d); err != nil {
+			return nil, err
+		}
+		out[j] = p
+	}
+	return out, nil
+}
+
+func marshalScalars(scalars map[party.ID]curve.Scalar) (map[party.ID][]byte, error) {
+	out := make(map[party.ID][]byte, len(scalars))
+	for j, s := range scalars {
+		data, err := s.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		out[j] = data
+	}
+	return out, nil
+}
+
+func unmarshalScalars(group curve.Curve, data map[party.ID][]byte) (map[party.ID]curve.Scalar, error) {
+	out := make(map[party.ID]curve.Scalar, len(data))
+	for j, d := range data {
+		s := group.NewScalar()
+		if err := s.UnmarshalBinary(d); err != nil {
+			return nil, err
+		}
+		out[j] = s
+	}
+	return out, nil
+}
@@ -0,0 +1,111 @@
+package sign
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+// serialize simulates the transfer of messages to and from an offline party as files.
+func serialize(t *testing.T, msgs []*protocol.Message) [][]byte {
+	files := make([][]byte, 0, len(msgs))
+	for _, msg := range msgs {
+		data, err := msg.MarshalBinary()
+		require.NoError(t, err)
+		files = append(files, data)
+	}
+	return files
+}
+
+func deserialize(t *testing.T, files [][]byte) []*protocol.Message {
+	msgs := make([]*protocol.Message, 0, len(files))
+	for _, data := range files {
+		var msg protocol.Message
+		require.NoError(t, msg.UnmarshalBinary(data))
+		msgs = append(msgs, &msg)
+	}
+	return msgs
+}
+
+func TestOfflineSigner(t *testing.T) {
+	group := curve.Secp256k1{}
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	configs, partyIDs := test.GenerateConfig(group, 3, 2, rand.Reader, pl)
+	message := []byte("hello")
+	sessionID := []byte("offline session")
+	offline := partyIDs[2]
+
+	online := make(map[party.ID]*protocol.MultiHandler, 2)
+	for _, id := range partyIDs[:2] {
+		h, err := protocol.NewMultiHandler(StartSign(configs[id], partyIDs, message, pl), sessionID)
+		require.NoError(t, err)
+		online[id] = h
+	}
+
+	// the offline party keeps nothing in memory between rounds except its config and the serialized state
+	newCodec := func() protocol.SessionCodec {
+		return NewCodec(configs[offline], partyIDs, message, sessionID, pl)
+	}
+	state, outbound, err := protocol.StartOffline(StartSign(configs[offline], partyIDs, message, pl), sessionID, newCodec())
+	require.NoError(t, err)
+	files := serialize(t, outbound)
+
+	// each iteration delivers the messages of one round, with a few more to spare
+	for i := 0; i < 10; i++ {
+		var inbound []*protocol.Message
+		for _, msg := range deserialize(t, files) {
+			for id, h := range online {
+				if msg.IsFor(id) {
+					h.Accept(msg)
+				}
+			}
+		}
+		for _, h := range online {
+		drain:
+			for {
+				select {
+				case msg, ok := <-h.Listen():
+					if !ok {
+						break drain
+					}
+					for id, other := range online {
+						if msg.IsFor(id) {
+							other.Accept(msg)
+						}
+					}
+					if msg.IsFor(offline) {
+						inbound = append(inbound, msg)
+					}
+				default:
+					break drain
+				}
+			}
+		}
+
+		state, outbound, err = protocol.ProcessRound(newCodec(), state, deserialize(t, serialize(t, inbound)))
+		require.NoError(t, err)
+		files = serialize(t, outbound)
+	}
+
+	for _, h := range online {
+		result, err := h.Result()
+		require.NoError(t, err)
+		assert.True(t, result.(*ecdsa.Signature).Verify(configs[offline].PublicPoint(), message))
+	}
+	result, err := protocol.OfflineResult(newCodec(), state)
+	require.NoError(t, err)
+	require.IsType(t, &ecdsa.Signature{}, result)
+	assert.True(t, result.(*ecdsa.Signature).Verify(configs[offline].PublicPoint(), message))
+
+	_, err = protocol.OfflineResult(NewCodec(configs[offline], partyIDs, message, []byte("other"), pl), state)
+	assert.Error(t, err, "the state should be bound to the session")
+}
@@ -1,7 +1,7 @@
 package keygen
 
 import (
-	"crypto/rand"
+	"io"
 
 	"github.com/taurusgroup/multi-party-sig/internal/ot"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
@@ -56,12 +56,12 @@ func (r *round1R) Finalize(out chan<- *round.Message) (round.Session, error) {
 		return r, err
 	}
 	chainKey := make([]byte, params.SecBytes)
-	_, _ = rand.Read(chainKey)
+	_, _ = io.ReadFull(sample.Reader, chainKey)
 	chainKeyCommit, chainKeyDecommit, err := r.Hash().Commit(chainKey)
 	if err != nil {
 		return r, err
 	}
-	refreshScalar := sample.Scalar(rand.Reader, r.Group())
+	refreshScalar := sample.Scalar(sample.Reader, r.Group())
 	refreshCommit, refreshDecommit, err := r.Hash().Commit(refreshScalar)
 	if err != nil {
 		return r, err
@@ -1,7 +1,6 @@
 package sign
 
 import (
-	"crypto/rand"
 	"errors"
 
 	"github.com/taurusgroup/multi-party-sig/internal/ot"
@@ -65,7 +64,7 @@ func (r *round1S) StoreMessage(msg round.Message) error {
 func (r *round1S) Finalize(out chan<- *round.Message) (round.Session, error) {
 	group := r.Group()
 
-	kAPrime := sample.Scalar(rand.Reader, group)
+	kAPrime := sample.Scalar(sample.Reader, group)
 	RPrime := kAPrime.Act(r.D)
 
 	H := r.Hash()
@@ -75,7 +74,7 @@ func (r *round1S) Finalize(out chan<- *round.Message) (round.Session, error) {
 	R := kA.Act(r.D)
 	RProof := zksch.NewProof(r.Hash(), R, kA, r.D)
 
-	phi := sample.Scalar(rand.Reader, group)
+	phi := sample.Scalar(sample.Reader, group)
 	kAInv := group.NewScalar().Set(kA).Invert()
 	alpha1 := group.NewScalar().Set(r.config.SecretShare).Mul(kAInv)
 	alpha2 := group.NewScalar().Set(kAInv)
@@ -103,7 +102,7 @@ func (r *round1S) Finalize(out chan<- *round.Message) (round.Session, error) {
 	}
 	tA2 := tA21.Add(tA22)
 
-	m := curve.FromHash(group, r.hash)
+	m := curve.MessageToScalar(group, r.hash)
 
 	Gamma1 := group.NewBasePoint().Add(phi.Act(kA.ActOnBase())).Sub(tA1.Act(R))
 
@@ -92,7 +92,7 @@ func (r *round2R) Finalize(out chan<- *round.Message) (round.Session, error) {
 	}
 	tB2 := tB21.Add(tB22)
 
-	m := curve.FromHash(group, r.hash)
+	m := curve.MessageToScalar(group, r.hash)
 
 	Gamma1 := tB1.Act(R)
 
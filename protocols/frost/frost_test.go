@@ -94,3 +94,42 @@ func TestFrost(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestSignTaprootTweaked(t *testing.T) {
+	N := 3
+	message := []byte("hello")
+	merkleRoot := taproot.TaggedHash("TapBranch", []byte("scripts"))
+
+	partyIDs := test.PartyIDs(N)
+	n := test.NewNetwork(partyIDs)
+
+	var wg sync.WaitGroup
+	wg.Add(N)
+	for _, id := range partyIDs {
+		go func(id party.ID) {
+			defer wg.Done()
+			h, err := protocol.NewMultiHandler(KeygenTaproot(id, partyIDs, N-1), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(id, h, n)
+			r, err := h.Result()
+			require.NoError(t, err)
+			internal := r.(*TaprootConfig)
+
+			c, err := internal.Tweak(merkleRoot)
+			require.NoError(t, err)
+			output, err := internal.PublicKey.TweakedKey(merkleRoot)
+			require.NoError(t, err)
+			require.True(t, bytes.Equal(output, c.PublicKey), "the config should have the output key")
+
+			h, err = protocol.NewMultiHandler(SignTaproot(c, partyIDs, message), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(id, h, n)
+			signResult, err := h.Result()
+			require.NoError(t, err)
+			signature := signResult.(taproot.Signature)
+			assert.True(t, output.Verify(signature, message), "the signature should be valid under the output key")
+			assert.False(t, internal.PublicKey.Verify(signature, message))
+		}(id)
+	}
+	wg.Wait()
+}
@@ -190,6 +190,20 @@ func (r *TaprootConfig) Derive(adjust *curve.Secp256k1Scalar, newChainKey []byte
 	}, nil
 }
 
+// Tweak adjusts the shares to represent the Taproot output key of our public key, as the internal key,
+// committing to the script tree with the given merkleRoot, which may be empty.
+//
+// Signatures produced with the result are valid under the output key, which is the PublicKey of the result,
+// and taproot.PublicKey.TweakedKey(merkleRoot) of our own. See:
+// https://github.com/bitcoin/bips/blob/master/bip-0341.mediawiki#constructing-and-spending-taproot-outputs
+func (r *TaprootConfig) Tweak(merkleRoot []byte) (*TaprootConfig, error) {
+	tweak, err := r.PublicKey.Tweak(merkleRoot)
+	if err != nil {
+		return nil, err
+	}
+	return r.Derive(tweak, nil)
+}
+
 // DeriveChild adjusts the shares to represent the derived public key at a certain index.
 //
 // This derivation works according to BIP-32, see:
@@ -1,6 +1,7 @@
 package keygen
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/fxamacker/cbor/v2"
@@ -10,7 +11,9 @@ import (
 	"github.com/taurusgroup/multi-party-sig/internal/test"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
 )
 
 func checkOutput(t *testing.T, rounds []round.Session, parties party.IDSlice) {
@@ -74,22 +77,53 @@ func TestKeygen(t *testing.T) {
 	N := 5
 	partyIDs := test.PartyIDs(N)
 
+	rounds, err := test.StartRounds(partyIDs, func(partyID party.ID) protocol.StartFunc {
+		return StartKeygenCommon(false, group, partyIDs, N-1, partyID, nil, nil, nil)
+	})
+	require.NoError(t, err, "round creation should not result in an error")
+
+	require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
+
+	checkOutput(t, rounds, partyIDs)
+}
+
+// wrongDegreeRule replaces the polynomial sent by a party with one of a higher degree.
+type wrongDegreeRule struct {
+	from   party.ID
+	degree int
+}
+
+func (wrongDegreeRule) ModifyBefore(round.Session) {}
+func (wrongDegreeRule) ModifyAfter(round.Session)  {}
+func (rule wrongDegreeRule) ModifyContent(rNext round.Session, _ party.ID, content round.Content) {
+	body, ok := content.(*broadcast2)
+	if !ok || rNext.SelfID() != rule.from {
+		return
+	}
+	body.Phi_i = polynomial.NewPolynomialExponent(polynomial.NewPolynomial(rNext.Group(), rule.degree, sample.Scalar(sample.Reader, rNext.Group())))
+}
+
+func TestKeygenWrongDegree(t *testing.T) {
+	group := curve.Secp256k1{}
+	N := 3
+	partyIDs := test.PartyIDs(N)
+
 	rounds := make([]round.Session, 0, N)
 	for _, partyID := range partyIDs {
-		r, err := StartKeygenCommon(false, group, partyIDs, N-1, partyID, nil, nil, nil)(nil)
-		require.NoError(t, err, "round creation should not result in an error")
+		r, err := StartKeygenCommon(false, group, partyIDs, 1, partyID, nil, nil, nil)(nil)
+		require.NoError(t, err)
 		rounds = append(rounds, r)
 	}
 
+	rule := wrongDegreeRule{from: partyIDs[0], degree: 2}
 	for {
-		err, done := test.Rounds(rounds, nil)
-		require.NoError(t, err, "failed to process round")
-		if done {
-			break
+		err, done := test.Rounds(rounds, rule)
+		if err != nil {
+			assert.EqualError(t, err, fmt.Sprintf("party %s sent a polynomial of degree 2 instead of 1", partyIDs[0]))
+			return
 		}
+		require.False(t, done, "a polynomial of the wrong degree should be rejected")
 	}
-
-	checkOutput(t, rounds, partyIDs)
 }
 
 func checkOutputTaproot(t *testing.T, rounds []round.Session, parties party.IDSlice) {
@@ -154,13 +188,7 @@ func TestKeygenTaproot(t *testing.T) {
 
 	}
 
-	for {
-		err, done := test.Rounds(rounds, nil)
-		require.NoError(t, err, "failed to process round")
-		if done {
-			break
-		}
-	}
+	require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
 
 	checkOutputTaproot(t, rounds, partyIDs)
 }
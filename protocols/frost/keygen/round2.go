@@ -76,6 +76,14 @@ func (r *round2) StoreBroadcastMessage(msg round.Message) error {
 	// produced in the previous round. Note how we do the same hash cloning,
 	// but this time with the ID of the message sender.
 
+	if err := body.Phi_i.Validate(); err != nil {
+		return fmt.Errorf("party %s: %w", from, err)
+	}
+	// a polynomial of higher degree would raise the number of parties needed to sign
+	if body.Phi_i.Degree() != r.threshold {
+		return fmt.Errorf("party %s sent a polynomial of degree %d instead of %d", from, body.Phi_i.Degree(), r.threshold)
+	}
+
 	// Refresh: There's no proof to verify, but instead check that the constant is identity
 	if r.refresh {
 		if !body.Phi_i.Constant().IsIdentity() {
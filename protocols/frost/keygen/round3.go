@@ -85,7 +85,10 @@ func (r *round3) StoreMessage(msg round.Message) error {
 	//
 	// aborting if the check fails."
 	expected := body.F_li.ActOnBase()
-	actual := r.Phi[from].Evaluate(r.SelfID().Scalar(r.Group()))
+	actual, err := r.Phi[from].EvaluateChecked(r.SelfID().Scalar(r.Group()))
+	if err != nil {
+		return fmt.Errorf("party %s: %w", from, err)
+	}
 	if !expected.Equal(actual) {
 		return fmt.Errorf("VSS failed to validate")
 	}
@@ -73,13 +73,7 @@ func TestSign(t *testing.T) {
 		rounds = append(rounds, r)
 	}
 
-	for {
-		err, done := test.Rounds(rounds, nil)
-		require.NoError(t, err, "failed to process round")
-		if done {
-			break
-		}
-	}
+	require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
 
 	checkOutput(t, rounds, newPublicKey, steak)
 }
@@ -156,13 +150,7 @@ func TestSignTaproot(t *testing.T) {
 		rounds = append(rounds, r)
 	}
 
-	for {
-		err, done := test.Rounds(rounds, nil)
-		require.NoError(t, err, "failed to process round")
-		if done {
-			break
-		}
-	}
+	require.NoError(t, test.RunRounds(rounds, nil), "failed to process round")
 
 	checkOutputTaproot(t, rounds, newPublicKey, steak)
 }